@@ -0,0 +1,86 @@
+/*
+ * gofirewallder-agent runs on each host and relays firewalld of the local system bus to the controller over mTLS, e.g.
+ *
+ *   gofirewallder-agent --cert agent.pem --key agent-key.pem --cacert ca.pem
+ *   gofirewallder --host 10.0.0.1 --cert controller.pem --key controller-key.pem --cacert ca.pem --list-all
+ *
+ * The controller must present a certificate signed by --cacert, dbus-daemon does not listen on tcp.
+ * With --policy the calls are authorized by the common name and organizational units of the certificate, see libs/auth.
+ */
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/cylonchau/gofirewallder/libs/agent"
+	"github.com/cylonchau/gofirewallder/libs/auth"
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run serves until SIGINT or SIGTERM, the status is 0 when stopped by a signal, 1 on errors and 2 on usage errors.
+func run(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gofirewallder-agent", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	listen := flags.String("listen", ":"+strconv.Itoa(dbus.PORT), "address of the agent")
+	bus := flags.String("bus", "", "address of the bus, the system bus if empty")
+	cert := flags.String("cert", "", "PEM certificate of the agent")
+	key := flags.String("key", "", "PEM key of the certificate")
+	cacert := flags.String("cacert", "", "PEM certificates of the CAs of the controllers")
+	policy := flags.String("policy", "", "yaml file of the rules of the controllers, every call to firewalld is allowed if empty")
+	host := flags.String("host", "", "host of the rules of --policy, the address the controller connected to if empty")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if *cert == "" || *key == "" || *cacert == "" {
+		fmt.Fprintln(stderr, "Error: --cert, --key and --cacert are required")
+		return 2
+	}
+
+	config, err := agent.ServerTLSConfig(*cert, *key, *cacert)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	a := agent.NewAgent()
+	a.BusAddress = *bus
+	a.Host = *host
+	a.ErrorLog = log.New(stderr, "", log.LstdFlags)
+	if *policy != "" {
+		if a.Policy, err = auth.LoadPolicy(*policy); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 1
+		}
+		a.Policy.ErrorLog = a.ErrorLog
+	}
+
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+		signal.Stop(signals)
+		a.Close()
+	}()
+
+	a.ErrorLog.Printf("agent: serving on %s", *listen)
+	if err = a.ListenAndServeTLS(*listen, config); err != nil && !errors.Is(err, net.ErrClosed) {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
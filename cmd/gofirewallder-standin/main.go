@@ -0,0 +1,93 @@
+/*
+ * gofirewallder-standin runs on each host and reverts the changes of confirm.Commit that are not confirmed in time, e.g.
+ *
+ *   gofirewallder-standin
+ *   gofirewallder-standin --addr 127.0.0.1:55557
+ *
+ * It owns com.github.cylonchau.gofirewallder on the bus of firewalld, the system bus if --addr is empty,
+ * so the bus policy must allow it, e.g. in /etc/dbus-1/system.d/gofirewallder.conf
+ *
+ *   <busconfig>
+ *     <policy user="root">
+ *       <allow own="com.github.cylonchau.gofirewallder"/>
+ *       <allow send_destination="com.github.cylonchau.gofirewallder"/>
+ *     </policy>
+ *   </busconfig>
+ *
+ * The stand-in also refuses callers that are neither root nor its own user, a revert rewrites the whole firewall.
+ */
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cylonchau/gofirewallder/libs/confirm"
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run serves until SIGINT or SIGTERM, the status is 0 when stopped by a signal, 1 on errors and 2 on usage errors.
+func run(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gofirewallder-standin", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", "", "host:port of dbus-daemon listening on tcp, the system bus if empty")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	var (
+		client *dbus.DbusClientSerivce
+		err    error
+	)
+	if *addr == "" {
+		client, err = dbus.NewDbusClientServiceSystemBus()
+	} else {
+		client, err = dbus.NewDbusClientService(*addr)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	defer client.Close()
+
+	standIn, err := confirm.NewStandIn(client)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	logger := log.New(stderr, "", log.LstdFlags)
+	standIn.OnRevert = func(id string, err error) {
+		if err != nil {
+			logger.Printf("standin: change %s was not confirmed, revert failed: %v", id, err)
+			return
+		}
+		logger.Printf("standin: change %s was not confirmed, reverted", id)
+	}
+
+	logger.Printf("standin: serving %s on %s", confirm.NAME, client.Addr())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+	signal.Stop(signals)
+	if pending := standIn.Pending(); pending > 0 {
+		logger.Printf("standin: stopped with %d change(s) waiting for confirmation", pending)
+	}
+	if err = standIn.Close(); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
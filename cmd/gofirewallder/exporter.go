@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cylonchau/gofirewallder/libs/fleet"
+	"github.com/cylonchau/gofirewallder/libs/metrics"
+)
+
+/*
+ * @title         runExporter
+ * @description   gofirewallder exporter, collect the state of the firewalls of the inventory every --interval and
+ *                  serve it with the latency and errors of the D-Bus calls on /metrics for Prometheus until SIGINT
+ *                  or SIGTERM, e.g.
+ *                  gofirewallder exporter --listen :9163 --inventory hosts.txt --interval 30s
+ * @auth          author           2021-10-18
+ * @param         args             []string       "the arguments after exporter."
+ * @return        status           int            "EXIT_OK when stopped by a signal, EXIT_FAILED or EXIT_USAGE otherwise."
+ */
+func runExporter(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gofirewallder exporter", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	listen := flags.String("listen", ":9163", "address of the http server of /metrics")
+	host := flags.String("host", "", "firewalld of one host, addr:port")
+	inventory := flags.String("inventory", "", "file with one host per line, instead of --host")
+	interval := flags.Duration("interval", metrics.DEFAULT_INTERVAL, "time between collections")
+	hostTimeout := flags.Duration("host-timeout", fleet.DEFAULT_TIMEOUT, "time limit of the collection of a host")
+	parallel := flags.Int("parallel", fleet.DEFAULT_CONCURRENCY, "number of hosts collected at the same time")
+	var tlsOpts tlsOptions
+	tlsOpts.register(flags)
+	var traceOpts traceOptions
+	traceOpts.register(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gofirewallder exporter (--host addr[:port] | --inventory file) [--listen addr] [--interval time] [--host-timeout time] [--parallel n] [--cert file --key file --cacert file] [--trace-file file]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return EXIT_OK
+		}
+		return EXIT_USAGE
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", flags.Arg(0))
+		return EXIT_USAGE
+	}
+	if (*host == "") == (*inventory == "") {
+		fmt.Fprintln(stderr, "Error: exactly one of --host and --inventory is required")
+		return EXIT_USAGE
+	}
+
+	hosts := []string{fleet.HostPort(*host)}
+	if *inventory != "" {
+		var err error
+		if hosts, err = fleet.LoadInventory(*inventory); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return EXIT_USAGE
+		}
+	}
+	dial, err := tlsOpts.dialer()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return EXIT_USAGE
+	}
+	shutdownTrace, err := traceOpts.start()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return EXIT_USAGE
+	}
+	defer shutdownTrace()
+	exporter := metrics.NewExporter(hosts)
+	exporter.Interval = *interval
+	exporter.Executor.Timeout = *hostTimeout
+	exporter.Executor.Concurrency = *parallel
+	if dial != nil {
+		exporter.Executor.Dial = dial
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go exporter.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exporter)
+	server := &http.Server{Addr: *listen, Handler: mux}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+		signal.Stop(signals)
+		cancel()
+		shutdown, cancelShutdown := context.WithTimeout(context.Background(), SHUTDOWN_TIMEOUT)
+		defer cancelShutdown()
+		server.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintf(stdout, "serving the metrics of %d host(s) on %s/metrics\n", len(hosts), *listen)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(stderr, "Error:", err)
+		return EXIT_FAILED
+	}
+	<-stopped
+	return EXIT_OK
+}
@@ -0,0 +1,645 @@
+/*
+ * gofirewallder runs firewall-cmd style commands against firewalld of remote hosts, e.g.
+ *
+ *   gofirewallder --host 10.0.0.1 --zone public --add-port 8080/tcp --timeout 5m
+ *   gofirewallder --inventory hosts.txt --permanent --add-service http --add-service https --reload
+ *   gofirewallder --inventory hosts.txt --zone dmz --list-all -o json
+ *   gofirewallder --host 10.0.0.1 --cert controller.pem --key controller-key.pem --cacert ca.pem --list-ports
+ *   gofirewallder server --listen :8080 --inventory hosts.txt --policy policy.yaml
+ *   gofirewallder exporter --listen :9163 --inventory hosts.txt
+ *
+ * Commands run in the order they are given, on every host, and the hosts run concurrently.
+ * With --cert, --key and --cacert the hosts are connected through gofirewallder-agent over mTLS.
+ * With --dry-run the changes are printed to stderr instead of being sent, the queries are still answered.
+ * With --audit-log or --audit-syslog every change is recorded with the user running the command, see dbus.Auditor.
+ * With --trace-file the spans of the command, its hosts and their firewalld methods are appended to the file as json.
+ * The server subcommand serves the same operations as a REST API, see libs/rest.
+ * The exporter subcommand serves the state of the hosts on /metrics for Prometheus, see libs/metrics.
+ * Exit status: 0 success, 1 a query answered no, 2 a command or a host failed, 3 usage error.
+ */
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cylonchau/gofirewallder/libs/agent"
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/libs/fleet"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	EXIT_OK     = 0
+	EXIT_NO     = 1
+	EXIT_FAILED = 2
+	EXIT_USAGE  = 3
+
+	OUTPUT_TABLE = "table"
+	OUTPUT_JSON  = "json"
+
+	// TRACER_NAME is the instrumentation name of the span of a command line.
+	TRACER_NAME = "github.com/cylonchau/gofirewallder/cmd/gofirewallder"
+)
+
+var errReported = errors.New("usage error.")
+
+// command is a command flag in the order of the command line, value is "true" for flags without argument.
+type command struct {
+	name  string
+	value string
+}
+
+// commandFlag appends every occurrence of the flag to commands.
+type commandFlag struct {
+	name     string
+	boolFlag bool
+	commands *[]command
+}
+
+func (f *commandFlag) String() string { return "" }
+
+func (f *commandFlag) Set(value string) error {
+	if f.boolFlag && value != "true" {
+		return fmt.Errorf("--%s does not take a value", f.name)
+	}
+	*f.commands = append(*f.commands, command{name: f.name, value: value})
+	return nil
+}
+
+func (f *commandFlag) IsBoolFlag() bool { return f.boolFlag }
+
+// Line is one answer of a host, e.g. {Command: "query-service ssh", Result: "yes"}.
+type Line struct {
+	Command string `json:"command"`
+	Result  string `json:"result,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// options are the flags that are not commands.
+type options struct {
+	host        string
+	inventory   string
+	zone        string
+	permanent   bool
+	dryRun      bool
+	noGuard     bool
+	timeout     int
+	output      string
+	parallel    int
+	hostTimeout time.Duration
+	tls         tlsOptions
+	audit       auditOptions
+	trace       traceOptions
+	commands    []command
+}
+
+// tlsOptions are the files of the mTLS connection to gofirewallder-agent, none for dbus-daemon on tcp.
+type tlsOptions struct {
+	cert   string
+	key    string
+	cacert string
+}
+
+func (o *tlsOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.cert, "cert", "", "PEM client certificate, connect to gofirewallder-agent over mTLS")
+	flags.StringVar(&o.key, "key", "", "PEM key of --cert")
+	flags.StringVar(&o.cacert, "cacert", "", "PEM certificates of the CAs of the agents")
+}
+
+// auditOptions are the sinks of the audit records of the changes, none if empty.
+type auditOptions struct {
+	file   string
+	syslog bool
+}
+
+func (o *auditOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.file, "audit-log", "", "file the changes are appended to as hash chained json lines")
+	flags.BoolVar(&o.syslog, "audit-syslog", false, "send the changes to syslog")
+}
+
+// auditor opens the sinks of the options, nil without sinks, closeAll closes them.
+func (o *auditOptions) auditor() (auditor *dbus.Auditor, closeAll func(), err error) {
+	var sinks []dbus.AuditSink
+	var closers []io.Closer
+	closeAll = func() {
+		for _, closer := range closers {
+			closer.Close()
+		}
+	}
+	if o.file != "" {
+		sink, err := dbus.OpenAuditFile(o.file)
+		if err != nil {
+			return nil, closeAll, err
+		}
+		sinks, closers = append(sinks, sink), append(closers, sink)
+	}
+	if o.syslog {
+		sink, err := dbus.NewSyslogAuditSink("gofirewallder")
+		if err != nil {
+			closeAll()
+			return nil, closeAll, err
+		}
+		sinks, closers = append(sinks, sink), append(closers, sink)
+	}
+	if len(sinks) == 0 {
+		return nil, closeAll, nil
+	}
+	return dbus.NewAuditor(sinks...), closeAll, nil
+}
+
+// traceOptions is the file the spans are written to, none are recorded if empty.
+type traceOptions struct {
+	file string
+}
+
+func (o *traceOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.file, "trace-file", "", "file the OpenTelemetry spans of the operations are appended to as json")
+}
+
+// start sets the global tracer provider of go.opentelemetry.io/otel writing to the file, shutdown flushes and closes it.
+func (o *traceOptions) start() (shutdown func(), err error) {
+	shutdown = func() {}
+	if o.file == "" {
+		return shutdown, nil
+	}
+	file, err := os.OpenFile(o.file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return shutdown, err
+	}
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+	if err != nil {
+		file.Close()
+		return shutdown, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String("gofirewallder"))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return func() {
+		provider.Shutdown(context.Background())
+		file.Close()
+	}, nil
+}
+
+// dialer is the dialer of the options, nil for the default of dbus-daemon on tcp.
+func (o *tlsOptions) dialer() (fleet.Dialer, error) {
+	if o.cert == "" && o.key == "" && o.cacert == "" {
+		return nil, nil
+	}
+	if o.cert == "" || o.key == "" || o.cacert == "" {
+		return nil, errors.New("--cert, --key and --cacert are used together")
+	}
+	config, err := agent.ClientTLSConfig(o.cert, o.key, o.cacert)
+	if err != nil {
+		return nil, err
+	}
+	return dbus.TLSDialer(config), nil
+}
+
+var valueCommands = []struct{ name, usage string }{
+	{"add-port", "add a port, e.g. 8080/tcp, 1000-1100/udp"},
+	{"remove-port", "remove a port"},
+	{"query-port", "report whether the port is added"},
+	{"add-service", "add a service, e.g. http"},
+	{"remove-service", "remove a service"},
+	{"query-service", "report whether the service is added"},
+	{"add-source", "bind a source to the zone, e.g. 10.0.0.0/8, ipset:blocklist"},
+	{"remove-source", "remove a source"},
+	{"query-source", "report whether the source is bound"},
+	{"add-interface", "bind an interface to the zone, e.g. eth0"},
+	{"remove-interface", "remove an interface"},
+	{"query-interface", "report whether the interface is bound"},
+	{"add-forward-port", "add a forward port, e.g. port=80:proto=tcp:toport=8080:toaddr=10.0.0.2"},
+	{"remove-forward-port", "remove a forward port"},
+	{"query-forward-port", "report whether the forward port is added"},
+	{"add-rich-rule", "add a rich rule, e.g. 'rule family=\"ipv4\" source address=\"10.0.0.0/8\" accept'"},
+	{"remove-rich-rule", "remove a rich rule"},
+	{"query-rich-rule", "report whether the rich rule is added"},
+	{"set-default-zone", "set the default zone, runtime and permanent"},
+}
+
+var boolCommands = []struct{ name, usage string }{
+	{"add-masquerade", "enable masquerade"},
+	{"remove-masquerade", "disable masquerade"},
+	{"query-masquerade", "report whether masquerade is enabled"},
+	{"list-all", "list the settings of the zone"},
+	{"list-ports", "list the ports of the zone"},
+	{"list-services", "list the services of the zone"},
+	{"list-sources", "list the sources of the zone"},
+	{"list-interfaces", "list the interfaces of the zone"},
+	{"list-forward-ports", "list the forward ports of the zone"},
+	{"list-rich-rules", "list the rich rules of the zone"},
+	{"get-zones", "list the zones"},
+	{"get-default-zone", "print the default zone"},
+	{"runtime-to-permanent", "make the runtime configuration permanent"},
+	{"reload", "reload the permanent configuration"},
+}
+
+// kinds maps the item of a command name to the Operation kind.
+var kinds = map[string]string{
+	"port":         dbus.KIND_PORT,
+	"service":      dbus.KIND_SERVICE,
+	"source":       dbus.KIND_SOURCE,
+	"interface":    dbus.KIND_INTERFACE,
+	"forward-port": dbus.KIND_FORWARDPORT,
+	"rich-rule":    dbus.KIND_RICHRULE,
+	"masquerade":   dbus.KIND_MASQUERADE,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the exit status.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "server" {
+		return runServer(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "exporter" {
+		return runExporter(args[1:], stdout, stderr)
+	}
+	opts, err := parse(args, stderr)
+	if err != nil {
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			return EXIT_OK
+		case !errors.Is(err, errReported):
+			fmt.Fprintln(stderr, "Error:", err)
+		}
+		return EXIT_USAGE
+	}
+
+	var inventory []string
+	if opts.inventory != "" {
+		if inventory, err = fleet.LoadInventory(opts.inventory); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return EXIT_USAGE
+		}
+	} else {
+		inventory = []string{fleet.HostPort(opts.host)}
+	}
+
+	dial, err := opts.tls.dialer()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return EXIT_USAGE
+	}
+	auditor, closeAudit, err := opts.audit.auditor()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return EXIT_USAGE
+	}
+	defer closeAudit()
+	shutdownTrace, err := opts.trace.start()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return EXIT_USAGE
+	}
+	defer shutdownTrace()
+	executor := fleet.NewExecutor()
+	executor.Timeout = opts.hostTimeout
+	if dial != nil {
+		executor.Dial = dial
+	}
+	if opts.parallel > 0 {
+		executor.Concurrency = opts.parallel
+	}
+	actor := currentUser()
+	names := make([]string, 0, len(opts.commands))
+	for _, command := range opts.commands {
+		names = append(names, command.name)
+	}
+	ctx, span := otel.Tracer(TRACER_NAME).Start(context.Background(), "gofirewallder",
+		trace.WithAttributes(attribute.StringSlice("gofirewallder.commands", names), attribute.String("gofirewallder.actor", actor)))
+	report := executor.Run(ctx, inventory, func(ctx context.Context, client *dbus.DbusClientSerivce) (interface{}, error) {
+		if opts.dryRun {
+			client.DryRun = &dbus.DryRun{Log: log.New(stderr, client.Addr()+" dry-run: ", 0)}
+		}
+		if opts.noGuard {
+			client = client.Unguarded()
+		}
+		if auditor != nil {
+			return runCommands(auditor.Wrap(client, actor), opts)
+		}
+		return runCommands(client, opts)
+	})
+	span.End()
+
+	if err = render(stdout, report, opts.output); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return EXIT_FAILED
+	}
+	return exitStatus(report)
+}
+
+// currentUser is the actor of the audit records of the command line.
+func currentUser() string {
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
+
+// parse reads the flags, commands keep their order, e.g. --add-port 80 --reload --query-port 80.
+func parse(args []string, stderr io.Writer) (opts *options, err error) {
+	opts = &options{}
+	flags := flag.NewFlagSet("gofirewallder", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&opts.host, "host", "", "firewalld of one host, addr:port, the port defaults to "+strconv.Itoa(dbus.PORT))
+	flags.StringVar(&opts.inventory, "inventory", "", "file with one host per line, instead of --host")
+	flags.StringVar(&opts.zone, "zone", "", "the zone of the commands, the default zone if empty")
+	flags.BoolVar(&opts.permanent, "permanent", false, "change and query the permanent configuration")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "print the D-Bus calls of the changes to stderr instead of sending them")
+	flags.BoolVar(&opts.noGuard, "no-guard", false, "allow changes that would block the connection to the host or ssh")
+	timeout := flags.String("timeout", "", "runtime additions expire after the time, e.g. 30, 30s, 5m, 1h")
+	flags.StringVar(&opts.output, "o", OUTPUT_TABLE, "output format, table or json")
+	flags.StringVar(&opts.output, "output", OUTPUT_TABLE, "output format, table or json")
+	flags.IntVar(&opts.parallel, "parallel", fleet.DEFAULT_CONCURRENCY, "maximum number of hosts in progress")
+	flags.DurationVar(&opts.hostTimeout, "host-timeout", fleet.DEFAULT_TIMEOUT, "time limit of each host")
+	opts.tls.register(flags)
+	opts.audit.register(flags)
+	opts.trace.register(flags)
+	for _, value := range valueCommands {
+		flags.Var(&commandFlag{name: value.name, commands: &opts.commands}, value.name, value.usage)
+	}
+	for _, value := range boolCommands {
+		flags.Var(&commandFlag{name: value.name, boolFlag: true, commands: &opts.commands}, value.name, value.usage)
+	}
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gofirewallder (--host addr[:port] | --inventory file) [--zone zone] [--permanent] [--timeout time] command...")
+		flags.PrintDefaults()
+	}
+
+	if err = flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		// the flag package has printed the error and the usage.
+		return nil, errReported
+	}
+	switch {
+	case flags.NArg() > 0:
+		return nil, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	case (opts.host == "") == (opts.inventory == ""):
+		return nil, errors.New("exactly one of --host and --inventory is required")
+	case len(opts.commands) == 0:
+		return nil, errors.New("no command given")
+	case opts.output != OUTPUT_TABLE && opts.output != OUTPUT_JSON:
+		return nil, fmt.Errorf("unknown output %q", opts.output)
+	}
+	if *timeout != "" {
+		if opts.permanent {
+			return nil, errors.New("--timeout can not be used with --permanent")
+		}
+		if opts.timeout, err = parseTimeout(*timeout); err != nil {
+			return nil, err
+		}
+	}
+	return opts, nil
+}
+
+// parseTimeout reads seconds like firewall-cmd, a number with an optional unit s, m or h.
+func parseTimeout(str string) (int, error) {
+	if seconds, err := strconv.Atoi(str); err == nil && seconds >= 0 {
+		return seconds, nil
+	}
+	duration, err := time.ParseDuration(str)
+	if err != nil || duration < time.Second {
+		return 0, fmt.Errorf("invalid timeout %q", str)
+	}
+	return int(duration / time.Second), nil
+}
+
+/*
+ * @title         runCommands
+ * @description   run the commands of opts on one host, a failed command does not stop the next one.
+ * @auth          author           2021-10-17
+ * @param         client           dbus.Firewall  "the host."
+ * @param         opts             *options       ""
+ * @return        lines            []Line         "one or more lines per command."
+ * @return        error            error          "the number of failed commands."
+ */
+func runCommands(client dbus.Firewall, opts *options) (lines []Line, err error) {
+	failed := 0
+	for _, command := range opts.commands {
+		name := command.name
+		if command.value != "true" {
+			name += " " + command.value
+		}
+		results, err := runCommand(client, opts, command)
+		if err != nil {
+			failed++
+			lines = append(lines, Line{Command: name, Error: err.Error()})
+			continue
+		}
+		for _, result := range results {
+			lines = append(lines, Line{Command: name, Result: result})
+		}
+	}
+	if failed > 0 {
+		return lines, fmt.Errorf("%d command(s) failed", failed)
+	}
+	return lines, nil
+}
+
+// runCommand returns the result lines of command, "success" for changes and yes or no for queries.
+func runCommand(client dbus.Firewall, opts *options, command command) (results []string, err error) {
+	scope := dbus.SCOPE_RUNTIME
+	if opts.permanent {
+		scope = dbus.SCOPE_PERMANENT
+	}
+	zone := client.Zone(opts.zone)
+
+	switch command.name {
+	case "set-default-zone":
+		return []string{"success"}, client.SetDefaultZone(command.value)
+	case "get-default-zone":
+		return []string{client.GetDefaultZone()}, nil
+	case "get-zones":
+		var zones []string
+		if opts.permanent {
+			zones, err = client.PermanentGetZones()
+		} else {
+			zones, err = client.GetZones()
+		}
+		return []string{strings.Join(zones, " ")}, err
+	case "reload":
+		return []string{"success"}, client.Reload()
+	case "runtime-to-permanent":
+		return []string{"success"}, client.RuntimeToPermanent()
+	}
+
+	if strings.HasPrefix(command.name, "list-") {
+		return list(client, opts, strings.TrimPrefix(command.name, "list-"))
+	}
+
+	index := strings.Index(command.name, "-")
+	action, kind := command.name[:index], kinds[command.name[index+1:]]
+	value := command.value
+	if kind == dbus.KIND_MASQUERADE {
+		value = ""
+	}
+	if action == "query" {
+		var ok bool
+		switch kind {
+		case dbus.KIND_PORT:
+			ok, err = zone.QueryPort(scope, value)
+		case dbus.KIND_SERVICE:
+			ok, err = zone.QueryService(scope, value)
+		case dbus.KIND_SOURCE:
+			ok, err = zone.QuerySource(scope, value)
+		case dbus.KIND_INTERFACE:
+			ok, err = zone.QueryInterface(scope, value)
+		case dbus.KIND_MASQUERADE:
+			ok, err = zone.QueryMasquerade(scope)
+		case dbus.KIND_FORWARDPORT, dbus.KIND_RICHRULE:
+			ok, err = queryValue(zone, scope, kind, value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return []string{"yes"}, nil
+		}
+		return []string{"no"}, nil
+	}
+
+	op := dbus.Operation{Action: dbus.OP_ADD, Kind: kind, Zone: opts.zone, Value: value, Permanent: opts.permanent}
+	if action == "remove" {
+		op.Action = dbus.OP_REMOVE
+	} else if !opts.permanent {
+		op.Timeout = opts.timeout
+	}
+	if err = client.Execute(op); err != nil {
+		return nil, err
+	}
+	return []string{"success"}, nil
+}
+
+// queryValue queries the items given in firewall-cmd form, forward ports and rich rules.
+func queryValue(zone *dbus.Zone, scope dbus.Scope, kind, value string) (bool, error) {
+	if kind == dbus.KIND_RICHRULE {
+		rule, err := dbus.ParseRule(value)
+		if err != nil {
+			return false, err
+		}
+		return zone.QueryRichRule(scope, rule)
+	}
+	forward, err := dbus.StringToForwardPort(value)
+	if err != nil {
+		return false, err
+	}
+	return zone.QueryForwardPort(scope, forward.Port+"/"+forward.Protocol, net.JoinHostPort(forward.ToAddr, forward.ToPort))
+}
+
+// list returns the items of the zone, list-all returns one line per item kind like firewall-cmd.
+func list(client dbus.Firewall, opts *options, what string) (results []string, err error) {
+	var settings *dbus.Settings
+	if opts.permanent {
+		settings, err = client.PermanentGetZoneSettings(opts.zone)
+	} else {
+		settings, err = client.GetZoneSettings(opts.zone)
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := dbus.ZoneStateOf(settings)
+	items := map[string][]string{
+		"ports":         state.Ports,
+		"services":      state.Services,
+		"sources":       state.Sources,
+		"interfaces":    state.Interfaces,
+		"forward-ports": state.ForwardPorts,
+		"rich-rules":    state.RichRules,
+	}
+	if what != "all" {
+		return []string{strings.Join(items[what], " ")}, nil
+	}
+
+	masquerade := "no"
+	if settings.Masquerade {
+		masquerade = "yes"
+	}
+	results = []string{"target: " + settings.Targe}
+	for _, name := range []string{"interfaces", "sources", "services", "ports"} {
+		results = append(results, name+": "+strings.Join(items[name], " "))
+	}
+	results = append(results, "masquerade: "+masquerade)
+	results = append(results, "forward-ports: "+strings.Join(items["forward-ports"], " "))
+	results = append(results, "rich rules: "+strings.Join(items["rich-rules"], " "))
+	return results, nil
+}
+
+// render writes the report as a table of host, command and result, or as the json of fleet.Report.
+func render(w io.Writer, report *fleet.Report, output string) error {
+	if output == OUTPUT_JSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "HOST\tCOMMAND\tRESULT")
+	for _, result := range report.Results {
+		lines, _ := result.Value.([]Line)
+		for _, line := range lines {
+			if line.Error != "" {
+				fmt.Fprintf(table, "%s\t%s\terror: %s\n", result.Host, line.Command, line.Error)
+				continue
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\n", result.Host, line.Command, line.Result)
+		}
+		if len(lines) == 0 && result.Err != nil {
+			fmt.Fprintf(table, "%s\t-\terror: %s\n", result.Host, result.Error)
+		}
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	if len(report.Results) > 1 {
+		_, err := fmt.Fprint(w, report.String())
+		return err
+	}
+	return nil
+}
+
+// exitStatus is EXIT_FAILED if a host failed, EXIT_NO if a query answered no, EXIT_OK otherwise.
+func exitStatus(report *fleet.Report) int {
+	if report.Failure > 0 {
+		return EXIT_FAILED
+	}
+	for _, result := range report.Results {
+		lines, _ := result.Value.([]Line)
+		for _, line := range lines {
+			if strings.HasPrefix(line.Command, "query-") && line.Result == "no" {
+				return EXIT_NO
+			}
+		}
+	}
+	return EXIT_OK
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cylonchau/gofirewallder/libs/agent"
+	"github.com/cylonchau/gofirewallder/libs/auth"
+	"github.com/cylonchau/gofirewallder/libs/fleet"
+	"github.com/cylonchau/gofirewallder/libs/rest"
+	"github.com/cylonchau/gofirewallder/libs/rpc"
+	"github.com/cylonchau/gofirewallder/libs/rpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// SHUTDOWN_TIMEOUT is how long the server waits for requests in progress when it is stopped.
+const SHUTDOWN_TIMEOUT = 10 * time.Second
+
+/*
+ * @title         runServer
+ * @description   gofirewallder server, serve the REST API of libs/rest and optionally the gRPC API of libs/rpc
+ *                  until SIGINT or SIGTERM, e.g.
+ *                  gofirewallder server --listen :8080 --grpc-listen :9090 --inventory hosts.txt --policy policy.yaml
+ *                  only the hosts of --inventory are managed, the server is not a relay to any host.
+ *                  with --policy the clients are authorized by the rules of libs/auth, without it the server refuses to
+ *                  start unless --insecure allows every client everything. with --server-cert the servers serve TLS and
+ *                  verify the client certificates signed by --client-cacert, the tokens of --policy need it.
+ *                  with --audit-log or --audit-syslog the changes are recorded with the client as actor.
+ *                  with --trace-file the spans of the requests are appended to the file, their parents are taken
+ *                  from the traceparent of the http headers and the grpc metadata.
+ * @auth          author           2021-10-18
+ * @param         args             []string       "the arguments after server."
+ * @return        status           int            "EXIT_OK when stopped by a signal, EXIT_FAILED or EXIT_USAGE otherwise."
+ */
+func runServer(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gofirewallder server", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	listen := flags.String("listen", ":8080", "address of the http server")
+	grpcListen := flags.String("grpc-listen", "", "address of the grpc server, none if empty")
+	inventory := flags.String("inventory", "", "file with the hosts that may be managed, required")
+	hostTimeout := flags.Duration("host-timeout", fleet.DEFAULT_TIMEOUT, "time limit of a request on its host")
+	policyFile := flags.String("policy", "", "file with the tokens and the rules of the clients, required unless --insecure")
+	insecure := flags.Bool("insecure", false, "without --policy, let every client do everything")
+	serverCert := flags.String("server-cert", "", "PEM certificate of the servers, TLS is served with it")
+	serverKey := flags.String("server-key", "", "PEM key of the server certificate")
+	clientCACert := flags.String("client-cacert", "", "PEM certificates of the CAs of the client certificates")
+	var tlsOpts tlsOptions
+	tlsOpts.register(flags)
+	var traceOpts traceOptions
+	traceOpts.register(flags)
+	var auditOpts auditOptions
+	auditOpts.register(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gofirewallder server --inventory file (--policy file | --insecure) [--listen addr] [--grpc-listen addr] [--host-timeout time] [--server-cert file --server-key file [--client-cacert file]] [--cert file --key file --cacert file] [--trace-file file] [--audit-log file] [--audit-syslog]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return EXIT_OK
+		}
+		return EXIT_USAGE
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", flags.Arg(0))
+		return EXIT_USAGE
+	}
+
+	switch {
+	case *inventory == "":
+		fmt.Fprintln(stderr, "Error: --inventory is required, only its hosts are managed")
+		return EXIT_USAGE
+	case *policyFile == "" && !*insecure:
+		fmt.Fprintln(stderr, "Error: --policy is required, --insecure lets every client change every host")
+		return EXIT_USAGE
+	}
+	hosts, err := fleet.LoadInventory(*inventory)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return EXIT_USAGE
+	}
+	dial, err := tlsOpts.dialer()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return EXIT_USAGE
+	}
+	shutdownTrace, err := traceOpts.start()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return EXIT_USAGE
+	}
+	defer shutdownTrace()
+	var policy *auth.Policy
+	if *policyFile != "" {
+		if policy, err = auth.LoadPolicy(*policyFile); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return EXIT_USAGE
+		}
+	} else {
+		fmt.Fprintln(stderr, "Warning: --insecure, every client may change every host")
+	}
+	serverTLS, err := serverTLSConfig(*serverCert, *serverKey, *clientCACert)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return EXIT_USAGE
+	}
+	if policy != nil && len(policy.Tokens) > 0 && serverTLS == nil {
+		fmt.Fprintln(stderr, "Error: the tokens of --policy need --server-cert, they are not accepted over plain connections")
+		return EXIT_USAGE
+	}
+	auditor, closeAudit, err := auditOpts.auditor()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return EXIT_USAGE
+	}
+	defer closeAudit()
+	handler := rest.NewServer(hosts)
+	handler.Timeout = *hostTimeout
+	handler.Policy = policy
+	handler.Audit = auditor
+	if dial != nil {
+		handler.Dial = dial
+	}
+	server := &http.Server{Addr: *listen, Handler: handler, TLSConfig: serverTLS}
+
+	var grpcServer *grpc.Server
+	if *grpcListen != "" {
+		listener, err := net.Listen("tcp", *grpcListen)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return EXIT_FAILED
+		}
+		service := rpc.NewServer(hosts)
+		service.Timeout = *hostTimeout
+		service.Policy = policy
+		service.Audit = auditor
+		if dial != nil {
+			service.Dial = dial
+		}
+		var options []grpc.ServerOption
+		if serverTLS != nil {
+			options = append(options, grpc.Creds(credentials.NewTLS(serverTLS)))
+		}
+		grpcServer = grpc.NewServer(options...)
+		pb.RegisterFirewallServer(grpcServer, service)
+		go grpcServer.Serve(listener)
+		defer grpcServer.Stop()
+		fmt.Fprintf(stdout, "serving grpc on %s\n", *grpcListen)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+		signal.Stop(signals)
+		ctx, cancel := context.WithTimeout(context.Background(), SHUTDOWN_TIMEOUT)
+		defer cancel()
+		server.Shutdown(ctx)
+		if grpcServer != nil {
+			// WatchEvents streams only end with their clients, they are cut off after SHUTDOWN_TIMEOUT.
+			done := make(chan struct{})
+			go func() {
+				grpcServer.GracefulStop()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-ctx.Done():
+				grpcServer.Stop()
+			}
+		}
+	}()
+
+	fmt.Fprintf(stdout, "serving on %s, the API is described at /openapi.json\n", *listen)
+	serve := server.ListenAndServe
+	if serverTLS != nil {
+		serve = func() error { return server.ListenAndServeTLS("", "") }
+	}
+	if err := serve(); !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(stderr, "Error:", err)
+		return EXIT_FAILED
+	}
+	<-stopped
+	return EXIT_OK
+}
+
+// serverTLSConfig is the TLS of the servers, nil without cert, the client certificates are verified if they are given.
+func serverTLSConfig(cert, key, clientCACert string) (*tls.Config, error) {
+	switch {
+	case cert == "" && key == "" && clientCACert == "":
+		return nil, nil
+	case cert == "" || key == "":
+		return nil, errors.New("--server-cert and --server-key are used together")
+	case clientCACert == "":
+		certificate, err := tls.LoadX509KeyPair(cert, key)
+		if err != nil {
+			return nil, err
+		}
+		return &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}, nil
+	}
+	config, err := agent.ServerTLSConfig(cert, key, clientCACert)
+	if err != nil {
+		return nil, err
+	}
+	// tokens authenticate the clients without certificate.
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+	return config, nil
+}
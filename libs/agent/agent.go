@@ -0,0 +1,555 @@
+/*
+ * Package agent relays the D-Bus API of firewalld from the local system bus to mTLS clients, e.g.
+ *
+ *   config, err := agent.ServerTLSConfig("agent.pem", "agent-key.pem", "ca.pem")
+ *   a := agent.NewAgent()
+ *   err = a.ListenAndServeTLS(":55557", config)
+ *
+ * and on the controller
+ *
+ *   config, err := agent.ClientTLSConfig("controller.pem", "controller-key.pem", "ca.pem")
+ *   client, err := dbus.NewDbusClientServiceTLS("10.0.0.1:55557", config)
+ *
+ * so dbus-daemon does not need to listen on tcp with ANONYMOUS auth.
+ * Every client gets its own connection to the bus, the messages are relayed as they are, so the client is the same
+ * DbusClientSerivce as over tcp. Calls to other destinations than firewalld and the stand-in of libs/confirm,
+ * or methods of the bus other than Hello, AddMatch and the name lookups are refused with
+ * org.freedesktop.DBus.Error.AccessDenied. With a Policy the calls to firewalld are also authorized by the identity
+ * of the client certificate, e.g. a controller of the group app may only change the ports of the zone app:
+ *
+ *   policy, err := auth.LoadPolicy("policy.yaml")
+ *   a.Policy = policy
+ *
+ * DbusClientSerivce reads the default zone and the zone list on its own, a controller needs a rule allowing read
+ * without zones as well.
+ */
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cylonchau/gofirewallder/libs/auth"
+	"github.com/cylonchau/gofirewallder/libs/confirm"
+	"github.com/cylonchau/gofirewallder/object"
+	godbus "github.com/godbus/dbus/v5"
+)
+
+const (
+	SYSTEM_BUS_ADDRESS = "unix:path=/var/run/dbus/system_bus_socket"
+
+	BUS_NAME             = "org.freedesktop.DBus"
+	ERROR_ACCESS_DENIED  = "org.freedesktop.DBus.Error.AccessDenied"
+	MAX_MESSAGE_SIZE     = 128 << 20
+	MESSAGE_HEADER_SIZE  = 16
+	MESSAGE_ALIGNMENT    = 8
+	DEFAULT_DESTINATIONS = object.INTERFACE
+)
+
+// busMethods are the methods of the bus a client may call, Hello and the match rules of Events.
+var busMethods = map[string]bool{
+	"org.freedesktop.DBus.Hello":             true,
+	"org.freedesktop.DBus.AddMatch":          true,
+	"org.freedesktop.DBus.RemoveMatch":       true,
+	"org.freedesktop.DBus.GetNameOwner":      true,
+	"org.freedesktop.DBus.NameHasOwner":      true,
+	"org.freedesktop.DBus.GetId":             true,
+	"org.freedesktop.DBus.Peer.Ping":         true,
+	"org.freedesktop.DBus.Peer.GetMachineId": true,
+}
+
+/*
+ * Agent relays clients to firewalld on the local bus.
+ *   BusAddress    the bus, DBUS_SYSTEM_BUS_ADDRESS or SYSTEM_BUS_ADDRESS if empty, unix:path=, unix:abstract= or tcp:host=,port=.
+ *   Destinations  the bus names a client may call, firewalld and the stand-in of libs/confirm if empty.
+ *   Policy        authorizes the calls of a client by the identity of its certificate, see actionOf, nil allows every
+ *                 call to Destinations.
+ *   Host          the host of the actions of Policy, e.g. 10.0.0.1:55557, the address the client connected to if empty.
+ *   ErrorLog      refused calls and failed connections, the log package if nil.
+ *   lookup        the own connection of the agent to the bus, it finds the zones of the actions of Policy.
+ */
+type Agent struct {
+	BusAddress   string
+	Destinations []string
+	Policy       *auth.Policy
+	Host         string
+	ErrorLog     *log.Logger
+
+	lock      sync.Mutex
+	listeners map[net.Listener]bool
+	conns     map[net.Conn]bool
+	closed    bool
+	wg        sync.WaitGroup
+
+	lookupLock sync.Mutex
+	lookup     *godbus.Conn
+}
+
+// NewAgent returns an agent of the system bus.
+func NewAgent() *Agent {
+	return &Agent{}
+}
+
+/*
+ * @title         ServerTLSConfig
+ * @description   the TLS config of the agent, clients must present a certificate signed by the CA of caFile.
+ * @auth          author           2021-10-18
+ * @param         certFile         string         "PEM certificate of the agent."
+ * @param         keyFile          string         "PEM key of the certificate."
+ * @param         caFile           string         "PEM certificates of the CAs of the clients."
+ * @return        config           *tls.Config    ""
+ * @return        error            error          "a file can not be read or parsed."
+ */
+func ServerTLSConfig(certFile, keyFile, caFile string) (config *tls.Config, err error) {
+	cert, pool, err := loadTLS(certFile, keyFile, caFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ClientTLSConfig is the TLS config of the controller, the agents must present a certificate signed by the CA of caFile.
+func ClientTLSConfig(certFile, keyFile, caFile string) (config *tls.Config, err error) {
+	cert, pool, err := loadTLS(certFile, keyFile, caFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+func loadTLS(certFile, keyFile, caFile string) (cert tls.Certificate, pool *x509.CertPool, err error) {
+	if cert, err = tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		return cert, nil, err
+	}
+	data, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return cert, nil, err
+	}
+	pool = x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return cert, nil, fmt.Errorf("no certificate in %s", caFile)
+	}
+	return cert, pool, nil
+}
+
+// ListenAndServeTLS listens on addr, tcp, and serves the clients with config until Close.
+func (a *Agent) ListenAndServeTLS(addr string, config *tls.Config) error {
+	if config == nil || config.ClientAuth != tls.RequireAndVerifyClientCert {
+		return errors.New("the agent requires client certificates, see ServerTLSConfig")
+	}
+	listener, err := tls.Listen("tcp", addr, config)
+	if err != nil {
+		return err
+	}
+	return a.Serve(listener)
+}
+
+/*
+ * @title         Serve
+ * @description   accept clients on listener until Close, each one is relayed to its own bus connection.
+ * @auth          author           2021-10-18
+ * @param         listener         net.Listener   "a tls listener, see ListenAndServeTLS."
+ * @return        error            error          "nil after Close."
+ */
+func (a *Agent) Serve(listener net.Listener) error {
+	a.lock.Lock()
+	if a.closed {
+		a.lock.Unlock()
+		listener.Close()
+		return nil
+	}
+	if a.listeners == nil {
+		a.listeners = map[net.Listener]bool{}
+		a.conns = map[net.Conn]bool{}
+	}
+	a.listeners[listener] = true
+	a.lock.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			a.lock.Lock()
+			closed := a.closed
+			delete(a.listeners, listener)
+			a.lock.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		a.lock.Lock()
+		if a.closed {
+			a.lock.Unlock()
+			conn.Close()
+			return nil
+		}
+		a.conns[conn] = true
+		a.wg.Add(1)
+		a.lock.Unlock()
+
+		go func() {
+			defer a.wg.Done()
+			if err := a.relay(conn); err != nil {
+				a.logf("agent: %s: %v", conn.RemoteAddr(), err)
+			}
+			a.lock.Lock()
+			delete(a.conns, conn)
+			a.lock.Unlock()
+		}()
+	}
+}
+
+// Close stops the listeners and closes the clients and their bus connections.
+func (a *Agent) Close() error {
+	a.lock.Lock()
+	a.closed = true
+	for listener := range a.listeners {
+		listener.Close()
+	}
+	for conn := range a.conns {
+		conn.Close()
+	}
+	a.lock.Unlock()
+	a.wg.Wait()
+
+	a.lookupLock.Lock()
+	defer a.lookupLock.Unlock()
+	if a.lookup != nil {
+		a.lookup.Close()
+		a.lookup = nil
+	}
+	return nil
+}
+
+// relay authenticates client, connects it to the bus and copies the messages both ways until one side closes.
+func (a *Agent) relay(client net.Conn) error {
+	defer client.Close()
+	if conn, ok := client.(*tls.Conn); ok {
+		if err := conn.Handshake(); err != nil {
+			return err
+		}
+	}
+	var identity auth.Identity
+	if a.Policy != nil {
+		var err error
+		if identity, err = a.identityOf(client); err != nil {
+			return err
+		}
+	}
+	host := a.hostOf(client)
+	bus, err := a.dialBus()
+	if err != nil {
+		return fmt.Errorf("can not connect to the bus: %v", err)
+	}
+	defer bus.Close()
+
+	clientReader := bufio.NewReader(client)
+	if err = authClient(client, clientReader); err != nil {
+		return err
+	}
+	busReader := bufio.NewReader(bus)
+
+	var writeLock sync.Mutex
+	done := make(chan error, 1)
+	go func() {
+		for {
+			raw, err := readMessage(busReader)
+			if err == nil {
+				writeLock.Lock()
+				_, err = client.Write(raw)
+				writeLock.Unlock()
+			}
+			if err != nil {
+				// the reading of the client ends with the bus.
+				client.Close()
+				done <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		raw, err := readMessage(clientReader)
+		if err != nil {
+			bus.Close()
+			busErr := <-done
+			switch {
+			case errors.Is(err, io.EOF):
+				return nil
+			case errors.Is(err, net.ErrClosed):
+				return fmt.Errorf("the bus closed the connection: %v", busErr)
+			}
+			return err
+		}
+		msg, err := godbus.DecodeMessage(bytes.NewReader(raw))
+		if err != nil {
+			return err
+		}
+		if err = a.allow(msg); err == nil && a.Policy != nil {
+			err = a.authorize(identity, host, msg)
+		}
+		if err == nil {
+			if _, err = bus.Write(raw); err != nil {
+				return err
+			}
+			continue
+		}
+
+		a.logf("agent: %s: %v", client.RemoteAddr(), err)
+		if msg.Type != godbus.TypeMethodCall || msg.Flags&godbus.FlagNoReplyExpected != 0 {
+			continue
+		}
+		var reply bytes.Buffer
+		if err = deniedOf(msg, err).EncodeTo(&reply, binary.LittleEndian); err != nil {
+			return err
+		}
+		writeLock.Lock()
+		_, err = client.Write(reply.Bytes())
+		writeLock.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// allow checks a message of a client, only method calls to Destinations and busMethods pass.
+func (a *Agent) allow(msg *godbus.Message) error {
+	if msg.Type != godbus.TypeMethodCall {
+		return fmt.Errorf("message type %d is not relayed", msg.Type)
+	}
+	destination, _ := msg.Headers[godbus.FieldDestination].Value().(string)
+	iface, _ := msg.Headers[godbus.FieldInterface].Value().(string)
+	member, _ := msg.Headers[godbus.FieldMember].Value().(string)
+
+	if destination == BUS_NAME {
+		if !busMethods[iface+"."+member] {
+			return fmt.Errorf("%s.%s of the bus is not allowed", iface, member)
+		}
+		if member == "AddMatch" && len(msg.Body) > 0 {
+			if rule, _ := msg.Body[0].(string); strings.Contains(rule, "eavesdrop") {
+				return errors.New("eavesdropping match rules are not allowed")
+			}
+		}
+		return nil
+	}
+	destinations := a.Destinations
+	if len(destinations) == 0 {
+		destinations = []string{DEFAULT_DESTINATIONS, confirm.NAME}
+	}
+	for _, value := range destinations {
+		if destination == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("destination %s is not allowed", destination)
+}
+
+// deniedOf is the AccessDenied reply to call.
+func deniedOf(call *godbus.Message, err error) *godbus.Message {
+	return &godbus.Message{
+		Type: godbus.TypeError,
+		Headers: map[godbus.HeaderField]godbus.Variant{
+			godbus.FieldReplySerial: godbus.MakeVariant(call.Serial()),
+			godbus.FieldErrorName:   godbus.MakeVariant(ERROR_ACCESS_DENIED),
+			godbus.FieldSignature:   godbus.MakeVariant(godbus.SignatureOf(err.Error())),
+		},
+		Body: []interface{}{err.Error()},
+	}
+}
+
+// authClient runs the server side of the SASL handshake, the client is authenticated by its certificate already.
+func authClient(conn net.Conn, reader *bufio.Reader) error {
+	if _, err := reader.ReadByte(); err != nil {
+		return err
+	}
+	guid := make([]byte, 16)
+	rand.Read(guid)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		command := strings.Fields(line)
+		if len(command) == 0 {
+			continue
+		}
+		switch command[0] {
+		case "AUTH":
+			if len(command) == 1 {
+				_, err = conn.Write([]byte("REJECTED ANONYMOUS EXTERNAL\r\n"))
+			} else {
+				_, err = conn.Write([]byte("OK " + hex.EncodeToString(guid) + "\r\n"))
+			}
+		case "BEGIN":
+			return nil
+		case "CANCEL", "ERROR":
+			_, err = conn.Write([]byte("REJECTED ANONYMOUS EXTERNAL\r\n"))
+		default:
+			_, err = conn.Write([]byte("ERROR\r\n"))
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// busAddress is BusAddress, DBUS_SYSTEM_BUS_ADDRESS or SYSTEM_BUS_ADDRESS.
+func (a *Agent) busAddress() string {
+	address := a.BusAddress
+	if address == "" {
+		address = os.Getenv("DBUS_SYSTEM_BUS_ADDRESS")
+	}
+	if address == "" {
+		address = SYSTEM_BUS_ADDRESS
+	}
+	return address
+}
+
+// dialBus connects and authenticates to the bus, EXTERNAL on unix sockets, ANONYMOUS on tcp.
+func (a *Agent) dialBus() (conn net.Conn, err error) {
+	address := a.busAddress()
+	err = fmt.Errorf("no usable address in %q", address)
+	for _, value := range strings.Split(address, ";") {
+		network, addr, ok := parseAddress(value)
+		if !ok {
+			continue
+		}
+		if conn, err = net.Dial(network, addr); err != nil {
+			continue
+		}
+		auth := "AUTH ANONYMOUS " + hex.EncodeToString([]byte("gofirewallder"))
+		if network == "unix" {
+			auth = "AUTH EXTERNAL " + hex.EncodeToString([]byte(strconv.Itoa(os.Getuid())))
+		}
+		if err = authBus(conn, auth); err != nil {
+			conn.Close()
+			continue
+		}
+		return conn, nil
+	}
+	return nil, err
+}
+
+// parseAddress reads a D-Bus server address, e.g. unix:path=/var/run/dbus/system_bus_socket or tcp:host=127.0.0.1,port=55556.
+func parseAddress(address string) (network, addr string, ok bool) {
+	index := strings.Index(address, ":")
+	if index < 0 {
+		return "", "", false
+	}
+	transport, params := address[:index], map[string]string{}
+	for _, pair := range strings.Split(address[index+1:], ",") {
+		if i := strings.Index(pair, "="); i > 0 {
+			params[pair[:i]] = unescape(pair[i+1:])
+		}
+	}
+	switch {
+	case transport == "unix" && params["path"] != "":
+		return "unix", params["path"], true
+	case transport == "unix" && params["abstract"] != "":
+		return "unix", "@" + params["abstract"], true
+	case transport == "tcp" && params["host"] != "" && params["port"] != "":
+		return "tcp", net.JoinHostPort(params["host"], params["port"]), true
+	}
+	return "", "", false
+}
+
+// unescape decodes the %xx escapes of an address value.
+func unescape(value string) string {
+	if !strings.Contains(value, "%") {
+		return value
+	}
+	var out []byte
+	for i := 0; i < len(value); i++ {
+		if value[i] == '%' && i+2 < len(value) {
+			if b, err := hex.DecodeString(value[i+1 : i+3]); err == nil {
+				out = append(out, b[0])
+				i += 2
+				continue
+			}
+		}
+		out = append(out, value[i])
+	}
+	return string(out)
+}
+
+// authBus runs the client side of the SASL handshake with the bus.
+func authBus(conn net.Conn, auth string) error {
+	if _, err := conn.Write([]byte("\x00" + auth + "\r\n")); err != nil {
+		return err
+	}
+	// the reply is read byte by byte, the bus sends nothing more before BEGIN.
+	var line []byte
+	buf := make([]byte, 1)
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		if _, err := conn.Read(buf); err != nil {
+			return err
+		}
+		line = append(line, buf[0])
+	}
+	if !bytes.HasPrefix(line, []byte("OK ")) {
+		return fmt.Errorf("the bus refused %s: %s", strings.Fields(auth)[1], strings.TrimSpace(string(line)))
+	}
+	_, err := conn.Write([]byte("BEGIN\r\n"))
+	return err
+}
+
+// readMessage reads one message as it is, the size is taken from the fixed header and the header fields length.
+func readMessage(reader *bufio.Reader) (raw []byte, err error) {
+	header := make([]byte, MESSAGE_HEADER_SIZE)
+	if _, err = io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+	var order binary.ByteOrder
+	switch header[0] {
+	case 'l':
+		order = binary.LittleEndian
+	case 'B':
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("invalid byte order %q", header[0])
+	}
+	bodyLength, fieldsLength := order.Uint32(header[4:8]), order.Uint32(header[12:16])
+	fields := (uint64(fieldsLength) + MESSAGE_ALIGNMENT - 1) / MESSAGE_ALIGNMENT * MESSAGE_ALIGNMENT
+	size := MESSAGE_HEADER_SIZE + fields + uint64(bodyLength)
+	if size > MAX_MESSAGE_SIZE {
+		return nil, fmt.Errorf("message of %d bytes is too large", size)
+	}
+	raw = make([]byte, size)
+	copy(raw, header)
+	if _, err = io.ReadFull(reader, raw[MESSAGE_HEADER_SIZE:]); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func (a *Agent) logf(format string, args ...interface{}) {
+	if a.ErrorLog != nil {
+		a.ErrorLog.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
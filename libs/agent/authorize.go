@@ -0,0 +1,148 @@
+package agent
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cylonchau/gofirewallder/libs/auth"
+	"github.com/cylonchau/gofirewallder/object"
+	godbus "github.com/godbus/dbus/v5"
+)
+
+// zonelessMethods are the methods of the runtime zone interface whose first argument is not a zone.
+var zonelessMethods = map[string]bool{
+	"getZones":           true,
+	"getActiveZones":     true,
+	"getZoneOfInterface": true,
+	"getZoneOfSource":    true,
+}
+
+// identityOf authenticates client by its verified certificate, see auth.Policy.Authenticate.
+func (a *Agent) identityOf(client net.Conn) (identity auth.Identity, err error) {
+	var state *tls.ConnectionState
+	if conn, ok := client.(*tls.Conn); ok {
+		connState := conn.ConnectionState()
+		state = &connState
+	}
+	return a.Policy.Authenticate(state, "")
+}
+
+// hostOf is the host of the actions of client, Host or else the address the client connected to.
+func (a *Agent) hostOf(client net.Conn) string {
+	if a.Host != "" {
+		return a.Host
+	}
+	return client.LocalAddr().String()
+}
+
+// authorize checks a method call of identity against Policy, the methods of the bus are checked by allow only.
+func (a *Agent) authorize(identity auth.Identity, host string, msg *godbus.Message) error {
+	if destination, _ := msg.Headers[godbus.FieldDestination].Value().(string); destination == BUS_NAME {
+		return nil
+	}
+	action, err := a.actionOf(host, msg)
+	if err != nil {
+		return err
+	}
+	return a.Policy.Authorize(identity, action)
+}
+
+/*
+ * actionOf is the action of a method call, its operation is named like the commands of gofirewallder:
+ *   read             get*, query*, list* and the methods of Properties but Set, Introspectable and Peer
+ *   reload           reload and completeReload
+ *   the others       the method in kebab case, e.g. addPort is add-port, update of a config zone is update
+ * The zone is the first argument of the methods of the runtime zones, the default zone if it is empty, or the name of
+ * the config zone object called, the other methods have no zone.
+ */
+func (a *Agent) actionOf(host string, msg *godbus.Message) (action auth.Action, err error) {
+	path, _ := msg.Headers[godbus.FieldPath].Value().(godbus.ObjectPath)
+	iface, _ := msg.Headers[godbus.FieldInterface].Value().(string)
+	member, _ := msg.Headers[godbus.FieldMember].Value().(string)
+
+	action = auth.Action{Host: host, Operation: operationOf(iface, member)}
+	switch {
+	case iface == object.ZONE && !zonelessMethods[member], iface+"."+member == object.INTERFACE_GETZONESETTINGS:
+		if len(msg.Body) > 0 {
+			action.Zone, _ = msg.Body[0].(string)
+		}
+		if action.Zone == "" {
+			action.Zone, err = a.defaultZone()
+		}
+	case iface == object.CONFIG_ZONE:
+		action.Zone, err = a.configName(path, iface)
+	}
+	if err != nil {
+		return action, fmt.Errorf("can not find the zone of %s.%s: %v", iface, member, err)
+	}
+	return action, nil
+}
+
+func operationOf(iface, member string) string {
+	switch {
+	case iface == object.PROPERTIES && member != "Set",
+		iface == "org.freedesktop.DBus.Introspectable", iface == "org.freedesktop.DBus.Peer",
+		strings.HasPrefix(member, "get"), strings.HasPrefix(member, "query"), strings.HasPrefix(member, "list"):
+		return auth.OP_READ
+	case member == "reload" || member == "completeReload":
+		return auth.OP_RELOAD
+	}
+	var builder strings.Builder
+	for index, char := range member {
+		if unicode.IsUpper(char) {
+			if index > 0 {
+				builder.WriteByte('-')
+			}
+			char = unicode.ToLower(char)
+		}
+		builder.WriteRune(char)
+	}
+	return builder.String()
+}
+
+// defaultZone asks firewalld for the zone of calls with an empty zone.
+func (a *Agent) defaultZone() (zone string, err error) {
+	conn, err := a.lookupBus()
+	if err != nil {
+		return "", err
+	}
+	err = conn.Object(object.INTERFACE, object.PATH).Call(object.INTERFACE_GETDEFAULTZONE, godbus.FlagNoAutoStart).Store(&zone)
+	return zone, err
+}
+
+// configName asks firewalld for the name of the config object at path, e.g. the zone of /org/fedoraproject/FirewallD1/config/zone/3.
+func (a *Agent) configName(path godbus.ObjectPath, iface string) (name string, err error) {
+	conn, err := a.lookupBus()
+	if err != nil {
+		return "", err
+	}
+	var value godbus.Variant
+	if err = conn.Object(object.INTERFACE, path).Call(object.PROPERTIES_GET, godbus.FlagNoAutoStart, iface, object.PROPERTY_NAME).Store(&value); err != nil {
+		return "", err
+	}
+	if name, ok := value.Value().(string); ok {
+		return name, nil
+	}
+	return "", fmt.Errorf("the name of %s is a %s", path, value.Signature())
+}
+
+// lookupBus returns the own connection of the agent to the bus, it is opened on first use and again once it is closed.
+func (a *Agent) lookupBus() (*godbus.Conn, error) {
+	a.lookupLock.Lock()
+	defer a.lookupLock.Unlock()
+	if a.lookup != nil && a.lookup.Connected() {
+		return a.lookup, nil
+	}
+	conn, err := godbus.Connect(a.busAddress(),
+		godbus.WithAuth(godbus.AuthExternal(strconv.Itoa(os.Getuid())), godbus.AuthAnonymous()))
+	if err != nil {
+		return nil, err
+	}
+	a.lookup = conn
+	return conn, nil
+}
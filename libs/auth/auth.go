@@ -0,0 +1,278 @@
+/*
+ * Package auth authenticates the clients of the servers, libs/rest, libs/rpc and libs/agent, and authorizes their operations, e.g.
+ *
+ *   tokens:
+ *     - name: ci
+ *       sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+ *       groups: [app]
+ *   rules:
+ *     - groups: [admin]
+ *       operations: ["*"]
+ *     - groups: [app]
+ *       hosts: ["10.0.1.*"]
+ *       zones: [app]
+ *       operations: [read, add-port, remove-port]
+ *
+ * A client is a bearer token of tokens, or a client certificate verified by the server, its common name is the
+ * user and its organizational units are the groups. Tokens are only accepted over TLS, they would be sent in clear text.
+ * An operation is allowed if a rule of the user or one of its groups matches, every denial is logged. Operations are named like the commands of gofirewallder:
+ *   read                      every read, the zone list, zone settings and the items of a zone, and watching events
+ *   add-port, remove-port     and the same for service, forward-port, rich-rule and masquerade
+ *   reload                    other operations of a host, as the zone list they have no zone
+ */
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OP_READ   = "read"
+	OP_RELOAD = "reload"
+
+	BEARER_PREFIX = "Bearer "
+)
+
+var (
+	ErrNotAuthenticated = errors.New("NOT_AUTHENTICATED: a client certificate or a bearer token is required")
+	ErrInvalidToken     = errors.New("NOT_AUTHENTICATED: invalid token")
+	ErrTokenWithoutTLS  = errors.New("NOT_AUTHENTICATED: bearer tokens are only accepted over TLS")
+)
+
+// Identity is an authenticated client.
+type Identity struct {
+	Name   string   `json:"name" yaml:"name"`
+	Groups []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+func (i Identity) String() string {
+	if len(i.Groups) == 0 {
+		return i.Name
+	}
+	return i.Name + " (" + strings.Join(i.Groups, ", ") + ")"
+}
+
+type contextKey struct{}
+
+// NewContext returns ctx carrying identity, e.g. for the actor of the audit records of a request.
+func NewContext(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+// FromContext returns the identity of NewContext, ok is false if ctx has none.
+func FromContext(ctx context.Context) (identity Identity, ok bool) {
+	identity, ok = ctx.Value(contextKey{}).(Identity)
+	return identity, ok
+}
+
+// Action is an operation of a client on a zone of a host, Zone is empty for operations of the host.
+type Action struct {
+	Host      string `json:"host" yaml:"host"`
+	Zone      string `json:"zone,omitempty" yaml:"zone,omitempty"`
+	Operation string `json:"operation" yaml:"operation"`
+}
+
+func (a Action) String() string {
+	if a.Zone == "" {
+		return a.Operation + " on " + a.Host
+	}
+	return a.Operation + " on " + a.Host + " zone " + a.Zone
+}
+
+// Token is a bearer token of an identity, Token in clear text or its SHA256 in hex.
+type Token struct {
+	Name   string   `json:"name" yaml:"name"`
+	Groups []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Token  string   `json:"token,omitempty" yaml:"token,omitempty"`
+	SHA256 string   `json:"sha256,omitempty" yaml:"sha256,omitempty"`
+}
+
+/*
+ * Rule allows operations to users and groups, the values are patterns of path.Match, e.g. 10.0.1.*, add-*.
+ *   Hosts       host:port of the hosts, all hosts if empty.
+ *   Zones       the zones, all zones if empty, operations without zone need a rule without zones or with "*".
+ *   Operations  the allowed operations, none if empty.
+ */
+type Rule struct {
+	Users      []string `json:"users,omitempty" yaml:"users,omitempty"`
+	Groups     []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Hosts      []string `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+	Zones      []string `json:"zones,omitempty" yaml:"zones,omitempty"`
+	Operations []string `json:"operations" yaml:"operations"`
+}
+
+/*
+ * Policy is the tokens and the rules of a server, nothing is allowed without a rule.
+ *   ErrorLog  the denials and failed authentications, the log package if nil.
+ */
+type Policy struct {
+	Tokens []Token `json:"tokens,omitempty" yaml:"tokens,omitempty"`
+	Rules  []Rule  `json:"rules" yaml:"rules"`
+
+	ErrorLog *log.Logger `json:"-" yaml:"-"`
+}
+
+// ParsePolicy decodes a policy document, yaml or json.
+func ParsePolicy(data []byte) (policy *Policy, err error) {
+	policy = &Policy{}
+	if err = yaml.Unmarshal(data, policy); err != nil {
+		return nil, err
+	}
+	for index, token := range policy.Tokens {
+		switch {
+		case token.Name == "":
+			return nil, fmt.Errorf("token %d has no name", index+1)
+		case (token.Token == "") == (token.SHA256 == ""):
+			return nil, fmt.Errorf("token %s needs one of token and sha256", token.Name)
+		}
+	}
+	for index, rule := range policy.Rules {
+		for _, pattern := range append(append(append(rule.Hosts, rule.Zones...), rule.Operations...), rule.Users...) {
+			if _, err = path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("rule %d: invalid pattern %q", index+1, pattern)
+			}
+		}
+	}
+	return policy, nil
+}
+
+// LoadPolicy reads the policy file, see ParsePolicy.
+func LoadPolicy(file string) (*Policy, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePolicy(data)
+}
+
+/*
+ * @title         Authenticate
+ * @description   the identity of a client, by the bearer token of authorization or else by the verified certificate.
+ * @auth          author           2021-10-18
+ * @param         state            *tls.ConnectionState "the TLS connection of the client, nil without TLS, the leaf of its
+ *                                                  verified chains is the client."
+ * @param         authorization    string         "the Authorization header, e.g. Bearer s3cr3t, or empty."
+ * @return        identity         Identity       ""
+ * @return        error            error          "NOT_AUTHENTICATED: the token is unknown or sent without TLS, or there is
+ *                                                  neither a token nor a certificate."
+ */
+func (p *Policy) Authenticate(state *tls.ConnectionState, authorization string) (identity Identity, err error) {
+	if authorization != "" {
+		if state == nil {
+			return identity, p.fail(ErrTokenWithoutTLS)
+		}
+		if !strings.HasPrefix(authorization, BEARER_PREFIX) {
+			return identity, p.fail(ErrNotAuthenticated)
+		}
+		if identity, ok := p.token(strings.TrimSpace(strings.TrimPrefix(authorization, BEARER_PREFIX))); ok {
+			return identity, nil
+		}
+		return identity, p.fail(ErrInvalidToken)
+	}
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return identity, p.fail(ErrNotAuthenticated)
+	}
+	return IdentityOf(state.VerifiedChains[0][0]), nil
+}
+
+// IdentityOf is the identity of a client certificate, the common name and the organizational units.
+func IdentityOf(cert *x509.Certificate) Identity {
+	return Identity{Name: cert.Subject.CommonName, Groups: cert.Subject.OrganizationalUnit}
+}
+
+// token looks up a bearer token in constant time, tokens are compared by their SHA256.
+func (p *Policy) token(value string) (identity Identity, ok bool) {
+	sum := sha256.Sum256([]byte(value))
+	for _, token := range p.Tokens {
+		expected := token.SHA256
+		if token.Token != "" {
+			tokenSum := sha256.Sum256([]byte(token.Token))
+			expected = hex.EncodeToString(tokenSum[:])
+		}
+		if subtle.ConstantTimeCompare([]byte(strings.ToLower(expected)), []byte(hex.EncodeToString(sum[:]))) == 1 {
+			identity, ok = Identity{Name: token.Name, Groups: token.Groups}, true
+		}
+	}
+	return identity, ok
+}
+
+/*
+ * @title         Authorize
+ * @description   check action against the rules of identity, a denial is logged.
+ * @auth          author           2021-10-18
+ * @param         identity         Identity       "see Authenticate."
+ * @param         action           Action         ""
+ * @return        error            error          "ACCESS_DENIED: no rule allows the action."
+ */
+func (p *Policy) Authorize(identity Identity, action Action) error {
+	for _, rule := range p.Rules {
+		if rule.allows(identity, action) {
+			return nil
+		}
+	}
+	err := fmt.Errorf("ACCESS_DENIED: %s may not %s", identity.Name, action)
+	p.logf("auth: denied %s: %s", identity, action)
+	return err
+}
+
+func (r *Rule) allows(identity Identity, action Action) bool {
+	subject := match(r.Users, identity.Name)
+	for _, group := range identity.Groups {
+		subject = subject || match(r.Groups, group)
+	}
+	if !subject || !match(r.Operations, action.Operation) {
+		return false
+	}
+	if len(r.Hosts) > 0 && !match(r.Hosts, action.Host) {
+		return false
+	}
+	if action.Zone == "" {
+		return len(r.Zones) == 0 || contains(r.Zones, "*")
+	}
+	return len(r.Zones) == 0 || match(r.Zones, action.Zone)
+}
+
+func (p *Policy) fail(err error) error {
+	p.logf("auth: %v", err)
+	return err
+}
+
+func (p *Policy) logf(format string, args ...interface{}) {
+	if p.ErrorLog != nil {
+		p.ErrorLog.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// match reports whether value matches one of patterns.
+func match(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"log"
+	"testing"
+)
+
+func TestRuleAllows(t *testing.T) {
+	app := Identity{Name: "ci", Groups: []string{"app"}}
+	tests := []struct {
+		name   string
+		rule   Rule
+		action Action
+		want   bool
+	}{
+		{
+			name:   "user",
+			rule:   Rule{Users: []string{"ci"}, Operations: []string{"read"}},
+			action: Action{Host: "10.0.1.5:55557", Zone: "public", Operation: "read"},
+			want:   true,
+		},
+		{
+			name:   "other group",
+			rule:   Rule{Groups: []string{"admin"}, Operations: []string{"*"}},
+			action: Action{Host: "10.0.1.5:55557", Zone: "public", Operation: "read"},
+		},
+		{
+			name:   "operation pattern",
+			rule:   Rule{Groups: []string{"app"}, Operations: []string{"add-*"}},
+			action: Action{Host: "10.0.1.5:55557", Zone: "public", Operation: "remove-port"},
+		},
+		{
+			name:   "host pattern",
+			rule:   Rule{Groups: []string{"app"}, Hosts: []string{"10.0.1.*"}, Operations: []string{"read"}},
+			action: Action{Host: "10.0.1.5:55557", Zone: "public", Operation: "read"},
+			want:   true,
+		},
+		{
+			name:   "host pattern of another network",
+			rule:   Rule{Groups: []string{"app"}, Hosts: []string{"10.0.1.*"}, Operations: []string{"read"}},
+			action: Action{Host: "10.0.2.5:55557", Zone: "public", Operation: "read"},
+		},
+		{
+			name:   "host without port",
+			rule:   Rule{Groups: []string{"app"}, Hosts: []string{"10.0.1.5"}, Operations: []string{"read"}},
+			action: Action{Host: "10.0.1.5:55557", Zone: "public", Operation: "read"},
+		},
+		{
+			name:   "zone",
+			rule:   Rule{Groups: []string{"app"}, Zones: []string{"app"}, Operations: []string{"add-port"}},
+			action: Action{Host: "10.0.1.5:55557", Zone: "public", Operation: "add-port"},
+		},
+		{
+			name:   "zone wildcard",
+			rule:   Rule{Groups: []string{"app"}, Zones: []string{"app-*"}, Operations: []string{"add-port"}},
+			action: Action{Host: "10.0.1.5:55557", Zone: "app-web", Operation: "add-port"},
+			want:   true,
+		},
+		{
+			name:   "zoneless action against a zone-scoped rule",
+			rule:   Rule{Groups: []string{"app"}, Zones: []string{"app-*"}, Operations: []string{"*"}},
+			action: Action{Host: "10.0.1.5:55557", Operation: "reload"},
+		},
+		{
+			name:   "zoneless action against a rule of every zone",
+			rule:   Rule{Groups: []string{"app"}, Zones: []string{"*"}, Operations: []string{"reload"}},
+			action: Action{Host: "10.0.1.5:55557", Operation: "reload"},
+			want:   true,
+		},
+		{
+			name:   "zoneless action against a rule without zones",
+			rule:   Rule{Groups: []string{"app"}, Operations: []string{"reload"}},
+			action: Action{Host: "10.0.1.5:55557", Operation: "reload"},
+			want:   true,
+		},
+	}
+	for _, test := range tests {
+		if got := test.rule.allows(app, test.action); got != test.want {
+			t.Errorf("%s: allows %s: got %v, want %v", test.name, test.action, got, test.want)
+		}
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	policy := &Policy{
+		Tokens: []Token{
+			{Name: "ci", Groups: []string{"app"}, Token: "s3cr3t"},
+			// sha256 of test
+			{Name: "ops", SHA256: "9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08"},
+		},
+		ErrorLog: log.New(ioutil.Discard, "", 0),
+	}
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "admin", OrganizationalUnit: []string{"admin"}}}
+	verified := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	tests := []struct {
+		name          string
+		state         *tls.ConnectionState
+		authorization string
+		want          string
+		err           error
+	}{
+		{name: "token", state: &tls.ConnectionState{}, authorization: "Bearer s3cr3t", want: "ci"},
+		{name: "sha256 token", state: &tls.ConnectionState{}, authorization: "Bearer test", want: "ops"},
+		{name: "token before certificate", state: verified, authorization: "Bearer s3cr3t", want: "ci"},
+		{name: "certificate", state: verified, want: "admin"},
+		{name: "token without TLS", authorization: "Bearer s3cr3t", err: ErrTokenWithoutTLS},
+		{name: "unknown token", state: &tls.ConnectionState{}, authorization: "Bearer guess", err: ErrInvalidToken},
+		{name: "unknown token and certificate", state: verified, authorization: "Bearer guess", err: ErrInvalidToken},
+		{name: "not a bearer", state: &tls.ConnectionState{}, authorization: "Basic s3cr3t", err: ErrNotAuthenticated},
+		{name: "TLS without certificate", state: &tls.ConnectionState{}, err: ErrNotAuthenticated},
+		{name: "nothing", err: ErrNotAuthenticated},
+	}
+	for _, test := range tests {
+		identity, err := policy.Authenticate(test.state, test.authorization)
+		if err != test.err || identity.Name != test.want {
+			t.Errorf("%s: got %q, %v, want %q, %v", test.name, identity.Name, err, test.want, test.err)
+		}
+	}
+}
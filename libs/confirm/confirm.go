@@ -0,0 +1,277 @@
+package confirm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/libs/fleet"
+	godbus "github.com/godbus/dbus/v5"
+)
+
+const (
+	NAME      = "com.github.cylonchau.gofirewallder"
+	PATH      = "/com/github/cylonchau/gofirewallder/Confirm"
+	INTERFACE = NAME + ".Confirm"
+
+	METHOD_ARM     = INTERFACE + ".Arm"
+	METHOD_CONFIRM = INTERFACE + ".Confirm"
+	METHOD_REVERT  = INTERFACE + ".Revert"
+
+	ERROR_ACCESS_DENIED = "org.freedesktop.DBus.Error.AccessDenied"
+)
+
+// DEFAULT_WINDOW is the confirmation window of Commit, windows are whole seconds and at least one second.
+var DEFAULT_WINDOW = 60 * time.Second
+
+/*
+ * StandIn runs on the managed host and reverts a change that is not confirmed in time.
+ * It owns NAME on the bus of firewalld, so a controller reaching firewalld also reaches the stand-in,
+ * the bus policy must allow the stand-in to own NAME and root to call it, see gofirewallder-standin.
+ * The agent of libs/agent relays the calls to NAME.
+ * Arm takes the snapshot reverted to itself and only root and the user of the stand-in may call it,
+ * so no caller can make it restore a firewall of their own.
+ *   OnRevert  called after an unconfirmed change was reverted, err is the error of Restore.
+ */
+type StandIn struct {
+	OnRevert func(id string, err error)
+
+	client  *dbus.DbusClientSerivce
+	lock    sync.Mutex
+	pending map[string]*change
+}
+
+type change struct {
+	timer    *time.Timer
+	snapshot *dbus.Snapshot
+}
+
+/*
+ * @title         NewStandIn
+ * @description   export the stand-in on the bus of client and request NAME.
+ * @auth          author           2021-10-15
+ * @param         client           *dbus.DbusClientSerivce "a connection local to the host, e.g. to 127.0.0.1:55557, it is used to revert."
+ * @return        standIn          *StandIn       ""
+ * @return        error            error          "NAME is owned by another stand-in, or the bus policy denies it."
+ */
+func NewStandIn(client *dbus.DbusClientSerivce) (standIn *StandIn, err error) {
+	standIn = &StandIn{client: client, pending: map[string]*change{}}
+	if err = client.Conn.Export(&methods{standIn}, PATH, INTERFACE); err != nil {
+		return nil, err
+	}
+	reply, err := client.Conn.RequestName(NAME, godbus.NameFlagDoNotQueue)
+	if err != nil {
+		return nil, err
+	}
+	if reply != godbus.RequestNameReplyPrimaryOwner {
+		return nil, fmt.Errorf("name %s is already owned", NAME)
+	}
+	return standIn, nil
+}
+
+// Close reverts nothing, pending changes are left as they are and the name is released.
+func (s *StandIn) Close() error {
+	s.lock.Lock()
+	for id, change := range s.pending {
+		change.timer.Stop()
+		delete(s.pending, id)
+	}
+	s.lock.Unlock()
+	_, err := s.client.Conn.ReleaseName(NAME)
+	return err
+}
+
+// Pending returns the number of changes waiting for confirmation.
+func (s *StandIn) Pending() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.pending)
+}
+
+func (s *StandIn) arm(id string, snapshot *dbus.Snapshot, window time.Duration) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.pending[id]; ok {
+		return fmt.Errorf("change %s is already armed", id)
+	}
+	s.pending[id] = &change{
+		timer:    time.AfterFunc(window, func() { s.revert(id) }),
+		snapshot: snapshot,
+	}
+	return nil
+}
+
+// disarm stops the timer of id and returns its snapshot, nil if id is not pending.
+func (s *StandIn) disarm(id string) *dbus.Snapshot {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	change, ok := s.pending[id]
+	if !ok {
+		return nil
+	}
+	change.timer.Stop()
+	delete(s.pending, id)
+	return change.snapshot
+}
+
+// revert restores the snapshot of id, it returns false if id is not pending.
+func (s *StandIn) revert(id string) bool {
+	snapshot := s.disarm(id)
+	if snapshot == nil {
+		return false
+	}
+	err := s.client.Restore(snapshot)
+	if s.OnRevert != nil {
+		s.OnRevert(id, err)
+	}
+	return true
+}
+
+// authorize refuses callers that are neither root nor the user of the stand-in, asking the bus for their user.
+func (s *StandIn) authorize(sender godbus.Sender) *godbus.Error {
+	var uid uint32
+	err := s.client.Conn.BusObject().Call("org.freedesktop.DBus.GetConnectionUnixUser", 0, string(sender)).Store(&uid)
+	switch {
+	case err != nil:
+		return godbus.NewError(ERROR_ACCESS_DENIED, []interface{}{fmt.Sprintf("can not find the user of %s: %v", sender, err)})
+	case uid != 0 && int64(uid) != int64(os.Getuid()):
+		return godbus.NewError(ERROR_ACCESS_DENIED, []interface{}{fmt.Sprintf("user %d may not call the stand-in", uid)})
+	}
+	return nil
+}
+
+// methods is the D-Bus interface of the stand-in, the sender is checked by authorize.
+type methods struct {
+	standIn *StandIn
+}
+
+// Arm snapshots the firewall and reverts to it after seconds unless id is confirmed.
+func (m *methods) Arm(sender godbus.Sender, id string, seconds uint32) *godbus.Error {
+	if err := m.standIn.authorize(sender); err != nil {
+		return err
+	}
+	if seconds == 0 {
+		return godbus.MakeFailedError(fmt.Errorf("INVALID_COMMAND: the confirmation window of %s is 0s", id))
+	}
+	snapshot, err := m.standIn.client.Snapshot()
+	if err != nil {
+		return godbus.MakeFailedError(err)
+	}
+	if err = m.standIn.arm(id, snapshot, time.Duration(seconds)*time.Second); err != nil {
+		return godbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+func (m *methods) Confirm(sender godbus.Sender, id string) *godbus.Error {
+	if err := m.standIn.authorize(sender); err != nil {
+		return err
+	}
+	if m.standIn.disarm(id) == nil {
+		return godbus.MakeFailedError(fmt.Errorf("change %s is not pending, it may have been reverted", id))
+	}
+	return nil
+}
+
+func (m *methods) Revert(sender godbus.Sender, id string) *godbus.Error {
+	if err := m.standIn.authorize(sender); err != nil {
+		return err
+	}
+	if !m.standIn.revert(id) {
+		return godbus.MakeFailedError(fmt.Errorf("change %s is not pending", id))
+	}
+	return nil
+}
+
+// Pending is a change waiting for confirmation.
+type Pending struct {
+	ID       string
+	Addr     string
+	Deadline time.Time
+}
+
+var ErrExpired = errors.New("confirmation window expired, the change is reverted.")
+
+/*
+ * @title         Commit
+ * @description   arm the stand-in of the host, it takes a snapshot, then execute ops as a dbus.Tx.
+ *                  the change is reverted by the stand-in unless Confirm is called on the returned Pending within window.
+ *                  if ops fail they are rolled back and the stand-in is disarmed, or told to revert if the rollback failed.
+ * @auth          author           2021-10-15
+ * @param         client           *dbus.DbusClientSerivce "connected to the host."
+ * @param         window           time.Duration   "the confirmation window in whole seconds, DEFAULT_WINDOW if zero."
+ * @param         ops              []dbus.Operation "the change."
+ * @return        pending          *Pending        "confirm it from a fresh connection."
+ * @return        error            error           "INVALID_COMMAND for windows under 1s, the stand-in is not running, or the error of the change."
+ */
+func Commit(client *dbus.DbusClientSerivce, window time.Duration, ops []dbus.Operation) (pending *Pending, err error) {
+	if window == 0 {
+		window = DEFAULT_WINDOW
+	}
+	if window < time.Second {
+		return nil, fmt.Errorf("INVALID_COMMAND: the confirmation window %s is shorter than 1s", window)
+	}
+	window = window.Truncate(time.Second)
+
+	id := newID()
+	obj := client.Conn.Object(NAME, PATH)
+	if call := obj.Call(METHOD_ARM, 0, id, uint32(window/time.Second)); call.Err != nil {
+		return nil, fmt.Errorf("arm stand-in: %v", call.Err)
+	}
+	deadline := time.Now().Add(window)
+
+	if err = client.NewTx(ops...).Commit(); err != nil {
+		var txErr *dbus.TxError
+		if errors.As(err, &txErr) && len(txErr.RollbackErrors) > 0 {
+			if call := obj.Call(METHOD_REVERT, 0, id); call.Err != nil {
+				return nil, fmt.Errorf("%v; stand-in revert failed: %v", err, call.Err)
+			}
+			return nil, fmt.Errorf("%v; reverted to the snapshot", err)
+		}
+		obj.Call(METHOD_CONFIRM, 0, id)
+		return nil, err
+	}
+	return &Pending{ID: id, Addr: client.Addr(), Deadline: deadline}, nil
+}
+
+/*
+ * @title         Confirm
+ * @description   connect to the host again and confirm the change, proving the management path still works.
+ * @auth          author           2021-10-15
+ * @param         dial             fleet.Dialer   "connects the way Commit's client did, e.g. dbus.TLSDialer through the agent,
+ *                                                  dbus.NewDbusClientServiceContext if nil, it is cancelled at the deadline."
+ * @return        error            error          "ErrExpired, or the connection failed and the stand-in will revert."
+ */
+func (p *Pending) Confirm(dial fleet.Dialer) (err error) {
+	if time.Now().After(p.Deadline) {
+		return ErrExpired
+	}
+	if dial == nil {
+		dial = dbus.NewDbusClientServiceContext
+	}
+	ctx, cancel := context.WithDeadline(context.Background(), p.Deadline)
+	defer cancel()
+	client, err := dial(ctx, p.Addr)
+	if err != nil {
+		return fmt.Errorf("reconnect to %s: %v", p.Addr, err)
+	}
+	defer client.Close()
+
+	call := client.Conn.Object(NAME, PATH).Call(METHOD_CONFIRM, 0, p.ID)
+	if call.Err != nil {
+		return fmt.Errorf("confirm %s: %v", p.ID, call.Err)
+	}
+	return nil
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
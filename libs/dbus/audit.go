@@ -0,0 +1,680 @@
+package dbus
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	AUDIT_OK    = "ok"
+	AUDIT_ERROR = "error"
+)
+
+/*
+ * AuditRecord is one change of a firewall, the records of an Auditor form a hash chain, see VerifyAuditChain.
+ *   Operation  the method, e.g. PermanentAddPort.
+ *   Args       the arguments besides the zone, structs as json, e.g. ["3306/tcp", "0"].
+ *   Scope      runtime or permanent.
+ *   Result     AUDIT_OK or AUDIT_ERROR with Error.
+ *   Before     the settings of Zone in Scope before the change, After after it, only for changes of a zone.
+ *   PrevHash   the Hash of the previous record, empty for the first one.
+ *   Hash       sha256 of the json of the record with an empty Hash.
+ */
+type AuditRecord struct {
+	Seq       uint64    `json:"seq"`
+	Time      time.Time `json:"time"`
+	Actor     string    `json:"actor"`
+	Host      string    `json:"host"`
+	Zone      string    `json:"zone,omitempty"`
+	Operation string    `json:"operation"`
+	Args      []string  `json:"args,omitempty"`
+	Scope     string    `json:"scope"`
+	Result    string    `json:"result"`
+	Error     string    `json:"error,omitempty"`
+	Before    *Settings `json:"before,omitempty"`
+	After     *Settings `json:"after,omitempty"`
+	PrevHash  string    `json:"prev_hash,omitempty"`
+	Hash      string    `json:"hash"`
+}
+
+// AuditSink stores the records of an Auditor, it is called in the order of the chain.
+type AuditSink interface {
+	WriteAudit(record *AuditRecord) error
+}
+
+// auditResumer is implemented by sinks holding earlier records, the chain continues after the last one.
+type auditResumer interface {
+	lastAudit() *AuditRecord
+}
+
+/*
+ * Auditor records the changes of the firewalls it wraps to its sinks, e.g.
+ *
+ *   sink, err := dbus.OpenAuditFile("/var/log/gofirewallder/audit.jsonl")
+ *   auditor := dbus.NewAuditor(sink)
+ *   fw := auditor.Wrap(client, "alice")
+ *   fw.Zone("public").AddPort(dbus.SCOPE_BOTH, "3306/tcp", 0)
+ *
+ *   States    read the zone settings before and after a change of a zone, two more calls for each change.
+ *   ErrorLog  the records a sink failed to write, the log package if nil.
+ */
+type Auditor struct {
+	Sinks    []AuditSink
+	States   bool
+	ErrorLog *log.Logger
+
+	lock sync.Mutex
+	seq  uint64
+	hash string
+}
+
+// NewAuditor returns an auditor reading the zone states, the chain continues after the last record of a sink.
+func NewAuditor(sinks ...AuditSink) *Auditor {
+	auditor := &Auditor{Sinks: sinks, States: true}
+	for _, sink := range sinks {
+		if resumer, ok := sink.(auditResumer); ok {
+			if last := resumer.lastAudit(); last != nil && last.Seq >= auditor.seq {
+				auditor.seq, auditor.hash = last.Seq, last.Hash
+			}
+		}
+	}
+	return auditor
+}
+
+// Wrap returns fw recording its changes as made by actor, the host is the address of fw if it has one.
+func (a *Auditor) Wrap(fw Firewall, actor string) *AuditedFirewall {
+	audited := &AuditedFirewall{Firewall: fw, auditor: a, actor: actor}
+	if addr, ok := fw.(interface{ Addr() string }); ok {
+		audited.host = addr.Addr()
+	}
+	return audited
+}
+
+// record chains record to the previous one and writes it to every sink, failed writes are logged.
+func (a *Auditor) record(record *AuditRecord) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.seq++
+	record.Seq, record.PrevHash, record.Hash = a.seq, a.hash, ""
+	record.Hash = auditHash(record)
+	a.hash = record.Hash
+	for _, sink := range a.Sinks {
+		if err := sink.WriteAudit(record); err != nil {
+			a.logf("audit: can not write record %d %s of %s: %v", record.Seq, record.Operation, record.Actor, err)
+		}
+	}
+}
+
+func (a *Auditor) logf(format string, args ...interface{}) {
+	if a.ErrorLog != nil {
+		a.ErrorLog.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+func auditHash(record *AuditRecord) string {
+	copied := *record
+	copied.Hash = ""
+	data, _ := json.Marshal(&copied)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+/*
+ * @title         VerifyAuditChain
+ * @description   check that records are an unbroken chain, e.g. of ReadAuditLog, a changed, removed or
+ *                  reordered record breaks it. The first record may continue an older chain.
+ * @auth          author           2021-10-18
+ * @param         records          []AuditRecord  "the records in their order."
+ * @return        error            error          "AUDIT_TAMPERED: the first broken record."
+ */
+func VerifyAuditChain(records []AuditRecord) error {
+	for index := range records {
+		record := &records[index]
+		if record.Hash != auditHash(record) {
+			return fmt.Errorf("AUDIT_TAMPERED: record %d does not match its hash", record.Seq)
+		}
+		if index == 0 {
+			continue
+		}
+		previous := &records[index-1]
+		if record.PrevHash != previous.Hash || record.Seq != previous.Seq+1 {
+			return fmt.Errorf("AUDIT_TAMPERED: record %d does not follow record %d", record.Seq, previous.Seq)
+		}
+	}
+	return nil
+}
+
+/*
+ * AuditedFirewall is a Firewall whose changes are recorded by an Auditor, see Auditor.Wrap.
+ * Execute, NewTx, Zone, the bulk methods, Apply and the drift methods run on it, their single changes are recorded.
+ * Reads are passed through, but Snapshot. Snapshot and Restore need a wrapped DbusClientSerivce.
+ */
+type AuditedFirewall struct {
+	Firewall
+
+	auditor *Auditor
+	actor   string
+	host    string
+}
+
+// change runs call as operation on zone, the empty string is usage default zone, with the states of the zone.
+func (f *AuditedFirewall) change(operation string, permanent bool, zone string, call func() error, args ...interface{}) error {
+	if zone == "" {
+		zone = f.Firewall.GetDefaultZone()
+	}
+	record := f.newRecord(operation, permanent, zone, args)
+	if f.auditor.States {
+		record.Before = f.state(permanent, zone)
+	}
+	err := call()
+	if f.auditor.States {
+		record.After = f.state(permanent, zone)
+	}
+	f.finish(record, err)
+	return err
+}
+
+// changeHost runs call as operation of the whole host or of an object besides the zones.
+func (f *AuditedFirewall) changeHost(operation string, permanent bool, call func() error, args ...interface{}) error {
+	record := f.newRecord(operation, permanent, "", args)
+	err := call()
+	f.finish(record, err)
+	return err
+}
+
+func (f *AuditedFirewall) newRecord(operation string, permanent bool, zone string, args []interface{}) *AuditRecord {
+	record := &AuditRecord{
+		Time:      time.Now().UTC(),
+		Actor:     f.actor,
+		Host:      f.host,
+		Zone:      zone,
+		Operation: operation,
+		Scope:     scopeOf(permanent).String(),
+	}
+	for _, arg := range args {
+		record.Args = append(record.Args, auditArg(arg))
+	}
+	return record
+}
+
+func (f *AuditedFirewall) finish(record *AuditRecord, err error) {
+	record.Result = AUDIT_OK
+	if err != nil {
+		record.Result, record.Error = AUDIT_ERROR, err.Error()
+	}
+	f.auditor.record(record)
+}
+
+// state reads the settings of zone, nil if it can not be read, e.g. the zone does not exist.
+func (f *AuditedFirewall) state(permanent bool, zone string) (settings *Settings) {
+	if permanent {
+		settings, _ = f.Firewall.PermanentGetZoneSettings(zone)
+	} else {
+		settings, _ = f.Firewall.GetZoneSettings(zone)
+	}
+	return settings
+}
+
+// auditArg renders an argument, strings and numbers as they are, rules as rich rules, other values as json.
+func auditArg(arg interface{}) string {
+	switch value := arg.(type) {
+	case string:
+		return value
+	case int, bool:
+		return fmt.Sprint(value)
+	case *Rule:
+		if value != nil {
+			return value.ToString()
+		}
+	}
+	data, err := json.Marshal(arg)
+	if err != nil {
+		return fmt.Sprint(arg)
+	}
+	return string(data)
+}
+
+func (f *AuditedFirewall) Execute(op Operation) error {
+	return execute(f, op)
+}
+
+func (f *AuditedFirewall) NewTx(ops ...Operation) *Tx {
+	return &Tx{Operations: ops, client: f}
+}
+
+func (f *AuditedFirewall) Zone(zone string) *Zone {
+	return &Zone{Name: zone, client: f}
+}
+
+/************************************************** snapshot area ***********************************************************/
+
+// snapshotter is a firewall saving and restoring its whole configuration, see DbusClientSerivce.Snapshot.
+type snapshotter interface {
+	Snapshot() (*Snapshot, error)
+	Restore(snapshot *Snapshot) error
+}
+
+// Snapshot is recorded as well, the snapshot holds the whole configuration of the host.
+func (f *AuditedFirewall) Snapshot() (snapshot *Snapshot, err error) {
+	fw, ok := f.Firewall.(snapshotter)
+	if !ok {
+		return nil, fmt.Errorf("INVALID_COMMAND: %T has no snapshots", f.Firewall)
+	}
+	record := f.newRecord("Snapshot", false, "", nil)
+	record.Scope = SCOPE_BOTH.String()
+	snapshot, err = fw.Snapshot()
+	f.finish(record, err)
+	return snapshot, err
+}
+
+// Restore is recorded with the time the snapshot was taken, the zone states are left out.
+func (f *AuditedFirewall) Restore(snapshot *Snapshot) (err error) {
+	fw, ok := f.Firewall.(snapshotter)
+	if !ok {
+		return fmt.Errorf("INVALID_COMMAND: %T has no snapshots", f.Firewall)
+	}
+	record := f.newRecord("Restore", false, "", []interface{}{snapshot.Taken.Format(time.RFC3339)})
+	record.Scope = SCOPE_BOTH.String()
+	err = fw.Restore(snapshot)
+	f.finish(record, err)
+	return err
+}
+
+/************************************************** zone area ***********************************************************/
+
+func (f *AuditedFirewall) SetDefaultZone(zone string) error {
+	return f.changeHost("SetDefaultZone", false, func() error {
+		return f.Firewall.SetDefaultZone(zone)
+	}, zone)
+}
+
+func (f *AuditedFirewall) AddZone(name string) error {
+	return f.change("AddZone", true, name, func() error {
+		return f.Firewall.AddZone(name)
+	})
+}
+
+func (f *AuditedFirewall) PermanentSetZoneSettings(zone string, settings *Settings) error {
+	return f.change("PermanentSetZoneSettings", true, zone, func() error {
+		return f.Firewall.PermanentSetZoneSettings(zone, settings)
+	})
+}
+
+func (f *AuditedFirewall) PermanentRemoveZone(zone string) error {
+	return f.change("PermanentRemoveZone", true, zone, func() error {
+		return f.Firewall.PermanentRemoveZone(zone)
+	})
+}
+
+func (f *AuditedFirewall) PermanentSetZoneTarget(zone, target string) error {
+	return f.change("PermanentSetZoneTarget", true, zone, func() error {
+		return f.Firewall.PermanentSetZoneTarget(zone, target)
+	}, target)
+}
+
+/************************************************** port area ***********************************************************/
+
+func (f *AuditedFirewall) AddPort(port, zone string, timeout int) (list string, err error) {
+	err = f.change("AddPort", false, zone, func() (err error) {
+		list, err = f.Firewall.AddPort(port, zone, timeout)
+		return err
+	}, port, timeout)
+	return list, err
+}
+
+func (f *AuditedFirewall) PermanentAddPort(port, zone string) error {
+	return f.change("PermanentAddPort", true, zone, func() error {
+		return f.Firewall.PermanentAddPort(port, zone)
+	}, port)
+}
+
+func (f *AuditedFirewall) RemovePort(port, zone string) (b bool, err error) {
+	err = f.change("RemovePort", false, zone, func() (err error) {
+		b, err = f.Firewall.RemovePort(port, zone)
+		return err
+	}, port)
+	return b, err
+}
+
+func (f *AuditedFirewall) PermanentRemovePort(port, zone string) (b bool, err error) {
+	err = f.change("PermanentRemovePort", true, zone, func() (err error) {
+		b, err = f.Firewall.PermanentRemovePort(port, zone)
+		return err
+	}, port)
+	return b, err
+}
+
+func (f *AuditedFirewall) PermanentAddPorts(zone string, ports []Port) ([]Conflict, error) {
+	return permanentAddPorts(f, zone, ports)
+}
+
+func (f *AuditedFirewall) PermanentRemovePorts(zone string, ports []Port) ([]Conflict, error) {
+	return permanentRemovePorts(f, zone, ports)
+}
+
+func (f *AuditedFirewall) AddProtocol(zone, protocol string, timeout int) (list string, err error) {
+	err = f.change("AddProtocol", false, zone, func() (err error) {
+		list, err = f.Firewall.AddProtocol(zone, protocol, timeout)
+		return err
+	}, protocol, timeout)
+	return list, err
+}
+
+/************************************************** service area ***********************************************************/
+
+func (f *AuditedFirewall) AddService(zone, service string, timeout int) (list string, err error) {
+	err = f.change("AddService", false, zone, func() (err error) {
+		list, err = f.Firewall.AddService(zone, service, timeout)
+		return err
+	}, service, timeout)
+	return list, err
+}
+
+func (f *AuditedFirewall) PermanentAddService(zone, service string) error {
+	return f.change("PermanentAddService", true, zone, func() error {
+		return f.Firewall.PermanentAddService(zone, service)
+	}, service)
+}
+
+func (f *AuditedFirewall) RemoveService(zone, service string) error {
+	return f.change("RemoveService", false, zone, func() error {
+		return f.Firewall.RemoveService(zone, service)
+	}, service)
+}
+
+func (f *AuditedFirewall) PermanentRemoveService(zone, service string) error {
+	return f.change("PermanentRemoveService", true, zone, func() error {
+		return f.Firewall.PermanentRemoveService(zone, service)
+	}, service)
+}
+
+func (f *AuditedFirewall) PermanentSetServices(zone string, services []string) ([]Conflict, error) {
+	return permanentSetServices(f, zone, services)
+}
+
+/************************************************** masquerade area ***********************************************************/
+
+func (f *AuditedFirewall) EnableMasquerade(zone string, timeout int) error {
+	return f.change("EnableMasquerade", false, zone, func() error {
+		return f.Firewall.EnableMasquerade(zone, timeout)
+	}, timeout)
+}
+
+func (f *AuditedFirewall) PermanentEnableMasquerade(zone string) error {
+	return f.change("PermanentEnableMasquerade", true, zone, func() error {
+		return f.Firewall.PermanentEnableMasquerade(zone)
+	})
+}
+
+func (f *AuditedFirewall) DisableMasquerade(zone string) error {
+	return f.change("DisableMasquerade", false, zone, func() error {
+		return f.Firewall.DisableMasquerade(zone)
+	})
+}
+
+func (f *AuditedFirewall) PermanentDisableMasquerade(zone string) error {
+	return f.change("PermanentDisableMasquerade", true, zone, func() error {
+		return f.Firewall.PermanentDisableMasquerade(zone)
+	})
+}
+
+/************************************************** interface area ***********************************************************/
+
+func (f *AuditedFirewall) BindInterface(zone, iface string) (list string, err error) {
+	err = f.change("BindInterface", false, zone, func() (err error) {
+		list, err = f.Firewall.BindInterface(zone, iface)
+		return err
+	}, iface)
+	return list, err
+}
+
+func (f *AuditedFirewall) PermanentBindInterface(zone, iface string) error {
+	return f.change("PermanentBindInterface", true, zone, func() error {
+		return f.Firewall.PermanentBindInterface(zone, iface)
+	}, iface)
+}
+
+func (f *AuditedFirewall) RemoveInterface(zone, iface string) error {
+	return f.change("RemoveInterface", false, zone, func() error {
+		return f.Firewall.RemoveInterface(zone, iface)
+	}, iface)
+}
+
+func (f *AuditedFirewall) PermanentRemoveInterface(zone, iface string) error {
+	return f.change("PermanentRemoveInterface", true, zone, func() error {
+		return f.Firewall.PermanentRemoveInterface(zone, iface)
+	}, iface)
+}
+
+/************************************************** source area ***********************************************************/
+
+func (f *AuditedFirewall) AddSource(zone, source string) (list string, err error) {
+	err = f.change("AddSource", false, zone, func() (err error) {
+		list, err = f.Firewall.AddSource(zone, source)
+		return err
+	}, source)
+	return list, err
+}
+
+func (f *AuditedFirewall) PermanentAddSource(zone, source string) error {
+	return f.change("PermanentAddSource", true, zone, func() error {
+		return f.Firewall.PermanentAddSource(zone, source)
+	}, source)
+}
+
+func (f *AuditedFirewall) RemoveSource(zone, source string) error {
+	return f.change("RemoveSource", false, zone, func() error {
+		return f.Firewall.RemoveSource(zone, source)
+	}, source)
+}
+
+func (f *AuditedFirewall) PermanentRemoveSource(zone, source string) error {
+	return f.change("PermanentRemoveSource", true, zone, func() error {
+		return f.Firewall.PermanentRemoveSource(zone, source)
+	}, source)
+}
+
+/************************************************** forward port area ***********************************************************/
+
+func (f *AuditedFirewall) AddForwardPort(zone string, portProtocol, toHostPort string, timeout int) error {
+	return f.change("AddForwardPort", false, zone, func() error {
+		return f.Firewall.AddForwardPort(zone, portProtocol, toHostPort, timeout)
+	}, portProtocol, toHostPort, timeout)
+}
+
+func (f *AuditedFirewall) PermanentAddForwardPort(zone string, portProtocol, toHostPort string) error {
+	return f.change("PermanentAddForwardPort", true, zone, func() error {
+		return f.Firewall.PermanentAddForwardPort(zone, portProtocol, toHostPort)
+	}, portProtocol, toHostPort)
+}
+
+func (f *AuditedFirewall) RemoveForwardPort(zone string, portProtocol, toHostPort string) error {
+	return f.change("RemoveForwardPort", false, zone, func() error {
+		return f.Firewall.RemoveForwardPort(zone, portProtocol, toHostPort)
+	}, portProtocol, toHostPort)
+}
+
+func (f *AuditedFirewall) PermanentRemoveForwardPort(zone string, portProtocol, toHostPort string) error {
+	return f.change("PermanentRemoveForwardPort", true, zone, func() error {
+		return f.Firewall.PermanentRemoveForwardPort(zone, portProtocol, toHostPort)
+	}, portProtocol, toHostPort)
+}
+
+/************************************************** rich rule area ***********************************************************/
+
+func (f *AuditedFirewall) AddRichRule(zone string, rule *Rule, timeout int) error {
+	return f.change("AddRichRule", false, zone, func() error {
+		return f.Firewall.AddRichRule(zone, rule, timeout)
+	}, rule, timeout)
+}
+
+func (f *AuditedFirewall) PermanentAddRichRule(zone string, rule *Rule) error {
+	return f.change("PermanentAddRichRule", true, zone, func() error {
+		return f.Firewall.PermanentAddRichRule(zone, rule)
+	}, rule)
+}
+
+func (f *AuditedFirewall) RemoveRichRule(zone string, rule *Rule) error {
+	return f.change("RemoveRichRule", false, zone, func() error {
+		return f.Firewall.RemoveRichRule(zone, rule)
+	}, rule)
+}
+
+func (f *AuditedFirewall) PermanentRemoveRichRule(zone string, rule *Rule) error {
+	return f.change("PermanentRemoveRichRule", true, zone, func() error {
+		return f.Firewall.PermanentRemoveRichRule(zone, rule)
+	}, rule)
+}
+
+func (f *AuditedFirewall) PermanentReplaceRichRules(zone string, rules []string) ([]Conflict, error) {
+	return permanentReplaceRichRules(f, zone, rules)
+}
+
+/************************************************** ipset area ***********************************************************/
+
+func (f *AuditedFirewall) AddIPSetEntry(ipset, entry string) error {
+	return f.changeHost("AddIPSetEntry", false, func() error {
+		return f.Firewall.AddIPSetEntry(ipset, entry)
+	}, ipset, entry)
+}
+
+func (f *AuditedFirewall) RemoveIPSetEntry(ipset, entry string) error {
+	return f.changeHost("RemoveIPSetEntry", false, func() error {
+		return f.Firewall.RemoveIPSetEntry(ipset, entry)
+	}, ipset, entry)
+}
+
+func (f *AuditedFirewall) PermanentAddIPSet(ipset, ipsetType string) error {
+	return f.changeHost("PermanentAddIPSet", true, func() error {
+		return f.Firewall.PermanentAddIPSet(ipset, ipsetType)
+	}, ipset, ipsetType)
+}
+
+func (f *AuditedFirewall) PermanentSetIPSetSettings(ipset string, settings *IPSetSettings) error {
+	return f.changeHost("PermanentSetIPSetSettings", true, func() error {
+		return f.Firewall.PermanentSetIPSetSettings(ipset, settings)
+	}, ipset, settings)
+}
+
+func (f *AuditedFirewall) PermanentRemoveIPSet(ipset string) error {
+	return f.changeHost("PermanentRemoveIPSet", true, func() error {
+		return f.Firewall.PermanentRemoveIPSet(ipset)
+	}, ipset)
+}
+
+func (f *AuditedFirewall) PermanentAddIPSetEntry(ipset, entry string) error {
+	return f.changeHost("PermanentAddIPSetEntry", true, func() error {
+		return f.Firewall.PermanentAddIPSetEntry(ipset, entry)
+	}, ipset, entry)
+}
+
+func (f *AuditedFirewall) PermanentRemoveIPSetEntry(ipset, entry string) error {
+	return f.changeHost("PermanentRemoveIPSetEntry", true, func() error {
+		return f.Firewall.PermanentRemoveIPSetEntry(ipset, entry)
+	}, ipset, entry)
+}
+
+/************************************************** policy area ***********************************************************/
+
+func (f *AuditedFirewall) SetPolicySettings(policy string, settings *PolicySettings) error {
+	return f.changeHost("SetPolicySettings", false, func() error {
+		return f.Firewall.SetPolicySettings(policy, settings)
+	}, policy, settings)
+}
+
+func (f *AuditedFirewall) PermanentSetPolicySettings(policy string, settings *PolicySettings) error {
+	return f.changeHost("PermanentSetPolicySettings", true, func() error {
+		return f.Firewall.PermanentSetPolicySettings(policy, settings)
+	}, policy, settings)
+}
+
+func (f *AuditedFirewall) PermanentAddPolicy(policy string, settings *PolicySettings) error {
+	return f.changeHost("PermanentAddPolicy", true, func() error {
+		return f.Firewall.PermanentAddPolicy(policy, settings)
+	}, policy, settings)
+}
+
+func (f *AuditedFirewall) PermanentRemovePolicy(policy string) error {
+	return f.changeHost("PermanentRemovePolicy", true, func() error {
+		return f.Firewall.PermanentRemovePolicy(policy)
+	}, policy)
+}
+
+/************************************************** direct area ***********************************************************/
+
+func (f *AuditedFirewall) PermanentSetDirectSettings(settings *DirectSettings) error {
+	return f.changeHost("PermanentSetDirectSettings", true, func() error {
+		return f.Firewall.PermanentSetDirectSettings(settings)
+	}, settings)
+}
+
+func (f *AuditedFirewall) AddDirectChain(chain DirectChain) error {
+	return f.changeHost("AddDirectChain", false, func() error {
+		return f.Firewall.AddDirectChain(chain)
+	}, chain)
+}
+
+func (f *AuditedFirewall) RemoveDirectChain(chain DirectChain) error {
+	return f.changeHost("RemoveDirectChain", false, func() error {
+		return f.Firewall.RemoveDirectChain(chain)
+	}, chain)
+}
+
+func (f *AuditedFirewall) AddDirectRule(rule DirectRule) error {
+	return f.changeHost("AddDirectRule", false, func() error {
+		return f.Firewall.AddDirectRule(rule)
+	}, rule)
+}
+
+func (f *AuditedFirewall) RemoveDirectRule(rule DirectRule) error {
+	return f.changeHost("RemoveDirectRule", false, func() error {
+		return f.Firewall.RemoveDirectRule(rule)
+	}, rule)
+}
+
+func (f *AuditedFirewall) AddDirectPassthrough(passthrough DirectPassthrough) error {
+	return f.changeHost("AddDirectPassthrough", false, func() error {
+		return f.Firewall.AddDirectPassthrough(passthrough)
+	}, passthrough)
+}
+
+func (f *AuditedFirewall) RemoveDirectPassthrough(passthrough DirectPassthrough) error {
+	return f.changeHost("RemoveDirectPassthrough", false, func() error {
+		return f.Firewall.RemoveDirectPassthrough(passthrough)
+	}, passthrough)
+}
+
+/************************************************** whole firewall area ***********************************************************/
+
+func (f *AuditedFirewall) Reload() error {
+	return f.changeHost("Reload", false, f.Firewall.Reload)
+}
+
+func (f *AuditedFirewall) RuntimeToPermanent() error {
+	return f.changeHost("RuntimeToPermanent", true, f.Firewall.RuntimeToPermanent)
+}
+
+func (f *AuditedFirewall) RuntimeFlush(zone string) error {
+	return f.change("RuntimeFlush", true, zone, func() error {
+		return f.Firewall.RuntimeFlush(zone)
+	})
+}
+
+func (f *AuditedFirewall) Apply(plan *Plan) error {
+	return applyPlan(f, plan)
+}
+
+func (f *AuditedFirewall) PromoteDrift(report *DriftReport, items ...DriftItem) error {
+	return promoteDrift(f, report, items...)
+}
+
+func (f *AuditedFirewall) RevertDrift(report *DriftReport, items ...DriftItem) error {
+	return revertDrift(f, report, items...)
+}
@@ -0,0 +1,129 @@
+package dbus
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// JSONLinesAuditSink writes each record as a line of json, e.g. to a file of OpenAuditFile.
+type JSONLinesAuditSink struct {
+	lock    sync.Mutex
+	writer  io.Writer
+	encoder *json.Encoder
+	last    *AuditRecord
+}
+
+// NewJSONLinesAuditSink writes the records to writer.
+func NewJSONLinesAuditSink(writer io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{writer: writer, encoder: json.NewEncoder(writer)}
+}
+
+/*
+ * @title         OpenAuditFile
+ * @description   append the records to file, it is created with mode 0600. The records already in file are
+ *                  verified, the chain of NewAuditor continues after the last one.
+ * @auth          author           2021-10-18
+ * @param         file             string         "e.g. /var/log/gofirewallder/audit.jsonl"
+ * @return        sink             *JSONLinesAuditSink "Close closes the file."
+ * @return        error            error          "the file can not be opened, AUDIT_TAMPERED."
+ */
+func OpenAuditFile(file string) (sink *JSONLinesAuditSink, err error) {
+	f, err := os.OpenFile(file, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	records, err := ReadAuditLog(f)
+	if err == nil {
+		err = VerifyAuditChain(records)
+	}
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	sink = NewJSONLinesAuditSink(f)
+	if len(records) > 0 {
+		sink.last = &records[len(records)-1]
+	}
+	return sink, nil
+}
+
+func (s *JSONLinesAuditSink) WriteAudit(record *AuditRecord) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err := s.encoder.Encode(record); err != nil {
+		return err
+	}
+	copied := *record
+	s.last = &copied
+	return nil
+}
+
+func (s *JSONLinesAuditSink) lastAudit() *AuditRecord {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.last
+}
+
+// Close closes the writer if it is an io.Closer.
+func (s *JSONLinesAuditSink) Close() error {
+	if closer, ok := s.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// ReadAuditLog decodes the records of a JSONLinesAuditSink, check them with VerifyAuditChain.
+func ReadAuditLog(reader io.Reader) (records []AuditRecord, err error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record AuditRecord
+		if err = json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("AUDIT_TAMPERED: line %d: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// RingAuditSink keeps the last records in memory, e.g. to show the recent changes.
+type RingAuditSink struct {
+	lock    sync.Mutex
+	records []AuditRecord
+	next    int
+	full    bool
+}
+
+// NewRingAuditSink keeps the last size records.
+func NewRingAuditSink(size int) *RingAuditSink {
+	if size < 1 {
+		size = 1
+	}
+	return &RingAuditSink{records: make([]AuditRecord, size)}
+}
+
+func (s *RingAuditSink) WriteAudit(record *AuditRecord) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.records[s.next] = *record
+	s.next = (s.next + 1) % len(s.records)
+	s.full = s.full || s.next == 0
+	return nil
+}
+
+// Records returns the kept records, the oldest first.
+func (s *RingAuditSink) Records() []AuditRecord {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.full {
+		return append([]AuditRecord{}, s.records[:s.next]...)
+	}
+	return append(append([]AuditRecord{}, s.records[s.next:]...), s.records[:s.next]...)
+}
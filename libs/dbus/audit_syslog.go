@@ -0,0 +1,38 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package dbus
+
+import (
+	"encoding/json"
+	"log/syslog"
+)
+
+// SyslogAuditSink sends each record as json to syslog, failed changes with priority warning.
+type SyslogAuditSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogAuditSink connects to the local syslog with facility authpriv, tag is e.g. gofirewallder.
+func NewSyslogAuditSink(tag string) (*SyslogAuditSink, error) {
+	writer, err := syslog.New(syslog.LOG_AUTHPRIV|syslog.LOG_NOTICE, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogAuditSink{writer: writer}, nil
+}
+
+func (s *SyslogAuditSink) WriteAudit(record *AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if record.Result == AUDIT_ERROR {
+		return s.writer.Warning(string(data))
+	}
+	return s.writer.Notice(string(data))
+}
+
+func (s *SyslogAuditSink) Close() error {
+	return s.writer.Close()
+}
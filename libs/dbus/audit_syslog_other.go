@@ -0,0 +1,24 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package dbus
+
+import (
+	"errors"
+)
+
+// SyslogAuditSink is not supported without syslog.
+type SyslogAuditSink struct{}
+
+// NewSyslogAuditSink fails, there is no syslog on this system.
+func NewSyslogAuditSink(tag string) (*SyslogAuditSink, error) {
+	return nil, errors.New("syslog is not supported on this system.")
+}
+
+func (s *SyslogAuditSink) WriteAudit(record *AuditRecord) error {
+	return errors.New("syslog is not supported on this system.")
+}
+
+func (s *SyslogAuditSink) Close() error {
+	return nil
+}
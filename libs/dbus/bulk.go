@@ -0,0 +1,214 @@
+package dbus
+
+import (
+	"fmt"
+)
+
+/*
+ * Conflict is an item of a bulk change that was left out, the other items are applied, e.g.
+ *   {Item: 80/tcp, Reason: ALREADY_ENABLED}
+ *   {Item: 70000/tcp, Reason: INVALID_PORT}
+ */
+type Conflict struct {
+	Item   string `json:"item"`
+	Reason string `json:"reason"`
+}
+
+func (c Conflict) String() string {
+	return c.Reason + ": " + c.Item
+}
+
+/*
+ * @title         PermanentAddPorts
+ * @description   Permanently add ports to zone with a single read and a single update of the zone settings.
+ * @auth          author           2021-10-16
+ * @param         zone             string         "zone name. The empty string is usage default zone."
+ * @param         ports            []Port         "empty protocol is tcp."
+ * @return        conflicts        []Conflict     "ports left out, ALREADY_ENABLED or INVALID_PORT."
+ * @return        error            error          "Possible errors: INVALID_ZONE"
+ */
+func (c *DbusClientSerivce) PermanentAddPorts(zone string, ports []Port) (conflicts []Conflict, err error) {
+	c, end := c.zoneSpan("PermanentAddPorts", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	return permanentAddPorts(c, zone, ports)
+}
+
+func permanentAddPorts(c Firewall, zone string, ports []Port) (conflicts []Conflict, err error) {
+	settings, err := c.PermanentGetZoneSettings(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	for _, port := range ports {
+		if port.Protocol == "" {
+			port.Protocol = "tcp"
+		}
+		item := port.Port + "/" + port.Protocol
+		switch {
+		case port.Port == "" || checkPort(item) != nil:
+			conflicts = append(conflicts, Conflict{Item: item, Reason: "INVALID_PORT"})
+		case hasPort(settings.Port, port):
+			conflicts = append(conflicts, Conflict{Item: item, Reason: "ALREADY_ENABLED"})
+		default:
+			settings.Port = append(settings.Port, port)
+			changed = true
+		}
+	}
+	if !changed {
+		return conflicts, nil
+	}
+	return conflicts, c.PermanentSetZoneSettings(zone, settings)
+}
+
+/*
+ * @title         PermanentRemovePorts
+ * @description   Permanently remove ports from zone with a single read and a single update of the zone settings.
+ * @auth          author           2021-10-16
+ * @param         zone             string         "zone name. The empty string is usage default zone."
+ * @param         ports            []Port         "empty protocol is tcp."
+ * @return        conflicts        []Conflict     "ports left out, NOT_ENABLED."
+ * @return        error            error          "Possible errors: INVALID_ZONE, LOCKOUT"
+ */
+func (c *DbusClientSerivce) PermanentRemovePorts(zone string, ports []Port) (conflicts []Conflict, err error) {
+	c, end := c.zoneSpan("PermanentRemovePorts", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	return permanentRemovePorts(c, zone, ports)
+}
+
+func permanentRemovePorts(c Firewall, zone string, ports []Port) (conflicts []Conflict, err error) {
+	settings, err := c.PermanentGetZoneSettings(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	for _, port := range ports {
+		if port.Protocol == "" {
+			port.Protocol = "tcp"
+		}
+		item := port.Port + "/" + port.Protocol
+		if !hasPort(settings.Port, port) {
+			conflicts = append(conflicts, Conflict{Item: item, Reason: "NOT_ENABLED"})
+			continue
+		}
+		settings.Port = removePort(settings.Port, port)
+		changed = true
+	}
+	if !changed {
+		return conflicts, nil
+	}
+	return conflicts, c.PermanentSetZoneSettings(zone, settings)
+}
+
+/*
+ * @title         PermanentSetServices
+ * @description   Replace the services of zone in permanent configuration with a single update of the zone settings.
+ * @auth          author           2021-10-16
+ * @param         zone             string         "zone name. The empty string is usage default zone."
+ * @param         services         []string       "the complete list of services, e.g. [ssh, http, https]."
+ * @return        conflicts        []Conflict     "services left out, INVALID_SERVICE or ALREADY_ENABLED for duplicates."
+ * @return        error            error          "Possible errors: INVALID_ZONE, LOCKOUT"
+ */
+func (c *DbusClientSerivce) PermanentSetServices(zone string, services []string) (conflicts []Conflict, err error) {
+	c, end := c.zoneSpan("PermanentSetServices", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	return permanentSetServices(c, zone, services)
+}
+
+func permanentSetServices(c Firewall, zone string, services []string) (conflicts []Conflict, err error) {
+	settings, err := c.PermanentGetZoneSettings(zone)
+	if err != nil {
+		return nil, err
+	}
+	known, err := c.PermanentGetServices()
+	if err != nil {
+		return nil, err
+	}
+
+	var list []string
+	for _, service := range services {
+		switch {
+		case !contains(known, service):
+			conflicts = append(conflicts, Conflict{Item: service, Reason: "INVALID_SERVICE"})
+		case contains(list, service):
+			conflicts = append(conflicts, Conflict{Item: service, Reason: "ALREADY_ENABLED"})
+		default:
+			list = append(list, service)
+		}
+	}
+	if sameJSON(list, settings.Service) {
+		return conflicts, nil
+	}
+	settings.Service = list
+	return conflicts, c.PermanentSetZoneSettings(zone, settings)
+}
+
+/*
+ * @title         PermanentReplaceRichRules
+ * @description   Replace the rich rules of zone in permanent configuration with a single update of the zone settings.
+ * @auth          author           2021-10-16
+ * @param         zone             string         "zone name. The empty string is usage default zone."
+ * @param         rules            []string       "the complete list of rich rules."
+ * @return        conflicts        []Conflict     "duplicates left out, ALREADY_ENABLED."
+ * @return        error            error          "Possible errors: INVALID_ZONE, INVALID_RULE, LOCKOUT, nothing is replaced on errors."
+ */
+func (c *DbusClientSerivce) PermanentReplaceRichRules(zone string, rules []string) (conflicts []Conflict, err error) {
+	c, end := c.zoneSpan("PermanentReplaceRichRules", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	return permanentReplaceRichRules(c, zone, rules)
+}
+
+func permanentReplaceRichRules(c Firewall, zone string, rules []string) (conflicts []Conflict, err error) {
+	settings, err := c.PermanentGetZoneSettings(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		list    []Rule
+		strs    []string
+		current []string
+	)
+	for index := range settings.Rule {
+		current = append(current, settings.Rule[index].ToString())
+	}
+	for _, str := range rules {
+		// a rule the parser does not know is kept if the zone has it as written, else the replace is refused.
+		rule, err := ParseRule(str)
+		if err != nil {
+			if !contains(current, str) {
+				return nil, fmt.Errorf("%v in %s", err, str)
+			}
+			rule = &Rule{Raw: str}
+		}
+		key := rule.ToString()
+		if contains(strs, key) {
+			conflicts = append(conflicts, Conflict{Item: str, Reason: "ALREADY_ENABLED"})
+			continue
+		}
+		// a rule the zone already has is kept as firewalld returned it, only new rules are rendered by the parser.
+		for index := range current {
+			if current[index] == key {
+				rule = &settings.Rule[index]
+				break
+			}
+		}
+		list = append(list, *rule)
+		strs = append(strs, key)
+	}
+	if sameJSON(strs, current) {
+		return conflicts, nil
+	}
+	settings.Rule = list
+	return conflicts, c.PermanentSetZoneSettings(zone, settings)
+}
+
+func hasPort(ports []Port, port Port) bool {
+	for _, value := range ports {
+		if value == port {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+package dbus
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cylonchau/gofirewallder/object"
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+ * Cache keeps zone lists, permanent zone paths and permanent zone settings of a client between calls.
+ * Entries are dropped on the signals of firewalld, Reloaded drops everything, and on the client's own changes.
+ * A change made by another client is seen once its signal arrived, a read racing the signal may return the old value.
+ * A value read while its entry was dropped is not stored, see generation.
+ * Runtime zone settings are not cached, they also change when a timeout expires.
+ */
+type Cache struct {
+	lock           sync.Mutex
+	zones          []string
+	permanentZones []string
+	paths          map[string]dbus.ObjectPath
+	settings       map[string]*zoneSettings
+	epoch          uint64
+	generations    map[string]uint64
+	stats          CacheStats
+	cancel         context.CancelFunc
+}
+
+// cacheSlot holds the cache of a client and its copies, e.g. of WithContext, calls may run while it is enabled or disabled.
+type cacheSlot struct {
+	lock  sync.Mutex
+	cache *Cache
+}
+
+// CacheStats counts lookups answered from the cache, lookups sent to firewalld and entries dropped.
+type CacheStats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Invalidations uint64 `json:"invalidations"`
+}
+
+/*
+ * @title         EnableCache
+ * @description   cache zone lookups of the client until DisableCache, it subscribes to the signals of firewalld.
+ * @auth          author           2021-10-16
+ * @return        cache            *Cache         "the cache of the client, e.g. for Stats."
+ * @return        error            error          "the signal subscription failed."
+ */
+func (c *DbusClientSerivce) EnableCache() (cache *Cache, err error) {
+	c.cache.lock.Lock()
+	defer c.cache.lock.Unlock()
+	if c.cache.cache != nil {
+		return c.cache.cache, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := c.Events(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	cache = &Cache{
+		paths:       map[string]dbus.ObjectPath{},
+		settings:    map[string]*zoneSettings{},
+		generations: map[string]uint64{},
+		cancel:      cancel,
+	}
+	go func() {
+		for event := range events {
+			cache.invalidateEvent(event)
+		}
+	}()
+	c.cache.cache = cache
+	return cache, nil
+}
+
+// DisableCache stops the signal subscription and drops the cache, lookups go to firewalld again.
+func (c *DbusClientSerivce) DisableCache() {
+	c.cache.lock.Lock()
+	defer c.cache.lock.Unlock()
+	if c.cache.cache != nil {
+		c.cache.cache.cancel()
+		c.cache.cache = nil
+	}
+}
+
+// Cache returns the cache enabled by EnableCache, nil if it is disabled.
+func (c *DbusClientSerivce) Cache() *Cache {
+	c.cache.lock.Lock()
+	defer c.cache.lock.Unlock()
+	return c.cache.cache
+}
+
+// Stats returns the counters of the cache.
+func (cache *Cache) Stats() CacheStats {
+	if cache == nil {
+		return CacheStats{}
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.stats
+}
+
+// Invalidate drops all entries, e.g. after changing firewalld outside of the client.
+func (cache *Cache) Invalidate() {
+	if cache == nil {
+		return
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.zones, cache.permanentZones = nil, nil
+	cache.paths = map[string]dbus.ObjectPath{}
+	cache.settings = map[string]*zoneSettings{}
+	cache.epoch++
+	cache.stats.Invalidations++
+}
+
+// invalidateZone drops the permanent settings of zone, with removed also its path and the zone lists.
+func (cache *Cache) invalidateZone(zone string, removed bool) {
+	if cache == nil {
+		return
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	delete(cache.settings, zone)
+	cache.generations[zone]++
+	if removed {
+		delete(cache.paths, zone)
+		cache.zones, cache.permanentZones = nil, nil
+		cache.generations[""]++
+	}
+	cache.stats.Invalidations++
+}
+
+/*
+ * generation changes whenever the entries of zone are dropped, "" stands for the zone lists.
+ * A lookup takes it before asking firewalld and passes it to the set method, which does not store the
+ * answer if the entry was dropped meanwhile, the answer may predate the change that dropped it.
+ */
+func (cache *Cache) generation(zone string) uint64 {
+	if cache == nil {
+		return 0
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.epoch + cache.generations[zone]
+}
+
+func (cache *Cache) invalidateEvent(event Event) {
+	switch {
+	case event.Name == EVENT_RELOADED || event.Name == EVENT_OVERFLOW:
+		cache.Invalidate()
+	case event.Interface == object.CONFIG_ZONE:
+		cache.invalidateZone(event.Zone, event.Name != EVENT_UPDATED)
+	case event.Interface == object.CONFIG_INTERFACE && event.Name == "ZoneAdded":
+		cache.lock.Lock()
+		cache.permanentZones = nil
+		cache.generations[""]++
+		cache.stats.Invalidations++
+		cache.lock.Unlock()
+	}
+}
+
+func (cache *Cache) getZones(permanent bool) (zones []string, ok bool) {
+	if cache == nil {
+		return nil, false
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	zones = cache.zones
+	if permanent {
+		zones = cache.permanentZones
+	}
+	return append([]string(nil), zones...), cache.count(zones != nil)
+}
+
+func (cache *Cache) setZones(permanent bool, zones []string, generation uint64) {
+	if cache == nil {
+		return
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if generation != cache.epoch+cache.generations[""] {
+		return
+	}
+	if permanent {
+		cache.permanentZones = append([]string{}, zones...)
+	} else {
+		cache.zones = append([]string{}, zones...)
+	}
+}
+
+func (cache *Cache) getPath(zone string) (path dbus.ObjectPath, ok bool) {
+	if cache == nil {
+		return "", false
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	path, ok = cache.paths[zone]
+	return path, cache.count(ok)
+}
+
+func (cache *Cache) setPath(zone string, path dbus.ObjectPath, generation uint64) {
+	if cache == nil {
+		return
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if generation == cache.epoch+cache.generations[zone] {
+		cache.paths[zone] = path
+	}
+}
+
+// getSettings returns new Settings on every hit, callers may modify them.
+func (cache *Cache) getSettings(zone string) (settings *Settings, ok bool) {
+	if cache == nil {
+		return nil, false
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	raw, ok := cache.settings[zone]
+	if !cache.count(ok) {
+		return nil, false
+	}
+	return raw.toSettings(), true
+}
+
+func (cache *Cache) setSettings(zone string, raw *zoneSettings, generation uint64) {
+	if cache == nil {
+		return
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if generation == cache.epoch+cache.generations[zone] {
+		cache.settings[zone] = raw
+	}
+}
+
+// count records a hit or a miss, the lock is held by the caller.
+func (cache *Cache) count(hit bool) bool {
+	if hit {
+		cache.stats.Hits++
+	} else {
+		cache.stats.Misses++
+	}
+	return hit
+}
@@ -0,0 +1,37 @@
+package dbus
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestCacheStaleRead(t *testing.T) {
+	cache := &Cache{paths: map[string]dbus.ObjectPath{}, settings: map[string]*zoneSettings{}, generations: map[string]uint64{}}
+
+	// a read that began before the zone changed must not be stored.
+	generation := cache.generation("public")
+	cache.invalidateZone("public", false)
+	cache.setSettings("public", &zoneSettings{}, generation)
+	if _, ok := cache.getSettings("public"); ok {
+		t.Error("settings read before invalidateZone were stored")
+	}
+	cache.setSettings("public", &zoneSettings{}, cache.generation("public"))
+	if _, ok := cache.getSettings("public"); !ok {
+		t.Error("settings read after invalidateZone were not stored")
+	}
+
+	// other zones and Invalidate.
+	generation = cache.generation("dmz")
+	cache.invalidateZone("public", true)
+	cache.setPath("dmz", "/org/fedoraproject/FirewallD1/config/zone/1", generation)
+	if _, ok := cache.getPath("dmz"); !ok {
+		t.Error("a change of public dropped the path of dmz")
+	}
+	generation = cache.generation("")
+	cache.Invalidate()
+	cache.setZones(true, []string{"public"}, generation)
+	if _, ok := cache.getZones(true); ok {
+		t.Error("zones read before Invalidate were stored")
+	}
+}
@@ -0,0 +1,140 @@
+package dbus
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/cylonchau/gofirewallder/object"
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+ * CallInfo is a D-Bus call of a client to firewalld.
+ *   Addr       the host:port of the client, see DbusClientSerivce.Addr.
+ *   Interface  e.g. org.fedoraproject.FirewallD1.zone, Method e.g. addPort.
+ *   Read       the method only reads, a get or query method.
+ */
+type CallInfo struct {
+	Addr      string          `json:"addr"`
+	Path      dbus.ObjectPath `json:"path"`
+	Interface string          `json:"interface"`
+	Method    string          `json:"method"`
+	Args      []interface{}   `json:"args,omitempty"`
+	Read      bool            `json:"read"`
+}
+
+// FullMethod returns the interface and the method, e.g. org.fedoraproject.FirewallD1.zone.addPort.
+func (info CallInfo) FullMethod() string {
+	return info.Interface + "." + info.Method
+}
+
+// Result is the reply of a call, the values of the method.
+type Result struct {
+	Body []interface{}
+}
+
+// Invoker sends a call, or hands it to the next interceptor.
+type Invoker func(ctx context.Context, info CallInfo) (Result, error)
+
+/*
+ * Interceptor wraps the D-Bus calls of a client, it may change the call, answer it itself or call next, e.g.
+ *
+ *   client.Interceptors = append(client.Interceptors, func(ctx context.Context, info dbus.CallInfo, next dbus.Invoker) (dbus.Result, error) {
+ *       start := time.Now()
+ *       result, err := next(ctx, info)
+ *       observe(info.Method, time.Since(start), err)
+ *       return result, err
+ *   })
+ *
+ * The error of firewalld carries its code, see ErrorCode.
+ */
+type Interceptor func(ctx context.Context, info CallInfo, next Invoker) (Result, error)
+
+// Use appends interceptors to the chain of the client, they run inside the ones already there.
+func (c *DbusClientSerivce) Use(interceptors ...Interceptor) {
+	c.Interceptors = append(c.Interceptors, interceptors...)
+}
+
+// WithContext returns a client sharing the connection whose calls run with ctx, e.g. to cancel them or to trace them.
+func (c *DbusClientSerivce) WithContext(ctx context.Context) *DbusClientSerivce {
+	client := *c
+	client.ctx = ctx
+	return &client
+}
+
+// callContext returns the context of the calls, context.Background without WithContext.
+func (c *DbusClientSerivce) callContext() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// busObject is a firewalld object of the client, its calls go through invoke.
+type busObject struct {
+	client *DbusClientSerivce
+	path   dbus.ObjectPath
+}
+
+// object returns the firewalld object at path.
+func (c *DbusClientSerivce) object(path dbus.ObjectPath) *busObject {
+	return &busObject{client: c, path: path}
+}
+
+// Call calls method, the interface and the method name, e.g. org.fedoraproject.FirewallD1.zone.addPort.
+func (o *busObject) Call(method string, flags dbus.Flags, args ...interface{}) *dbus.Call {
+	index := strings.LastIndex(method, ".")
+	info := CallInfo{
+		Addr:      o.client.addr,
+		Path:      o.path,
+		Interface: method[:index],
+		Method:    method[index+1:],
+		Args:      args,
+		Read:      isReadMethod(method),
+	}
+	result, err := o.client.invoke(o.client.callContext(), info, flags)
+	return &dbus.Call{Destination: object.INTERFACE, Path: o.path, Method: method, Args: args, Body: result.Body, Err: err}
+}
+
+// invoke runs info through the interceptors of the client, DryRun is the innermost, and sends it to firewalld.
+func (c *DbusClientSerivce) invoke(ctx context.Context, info CallInfo, flags dbus.Flags) (Result, error) {
+	var invoker Invoker = func(ctx context.Context, info CallInfo) (Result, error) {
+		call := c.Conn.Object(object.INTERFACE, info.Path).CallWithContext(ctx, info.FullMethod(), flags, info.Args...)
+		return Result{Body: call.Body}, call.Err
+	}
+	if c.DryRun != nil {
+		invoker = chain(c.DryRun.Intercept, invoker)
+	}
+	for index := len(c.Interceptors) - 1; index >= 0; index-- {
+		invoker = chain(c.Interceptors[index], invoker)
+	}
+	return invoker(ctx, info)
+}
+
+func chain(interceptor Interceptor, next Invoker) Invoker {
+	return func(ctx context.Context, info CallInfo) (Result, error) {
+		return interceptor(ctx, info, next)
+	}
+}
+
+// LogCalls returns an interceptor printing each call with its duration and error to logger.
+func LogCalls(logger *log.Logger) Interceptor {
+	return func(ctx context.Context, info CallInfo, next Invoker) (Result, error) {
+		start := time.Now()
+		result, err := next(ctx, info)
+		if err != nil {
+			logger.Printf("%s %s %s: %v (%s)", info.Addr, info.Path, info.FullMethod(), err, time.Since(start))
+		} else {
+			logger.Printf("%s %s %s (%s)", info.Addr, info.Path, info.FullMethod(), time.Since(start))
+		}
+		return result, err
+	}
+}
+
+// isReadMethod reports whether method only reads, e.g. getZones, queryService or the Get of a property.
+func isReadMethod(method string) bool {
+	name := method[strings.LastIndex(method, ".")+1:]
+	return strings.HasPrefix(name, "get") || strings.HasPrefix(name, "query") || method == object.PROPERTIES_GET
+}
@@ -0,0 +1,149 @@
+package dbus
+
+import (
+	"github.com/cylonchau/gofirewallder/object"
+	"github.com/godbus/dbus/v5"
+)
+
+/************************************************** direct area ***********************************************************/
+
+// @title         GetDirectSettings
+// @description   Return the runtime direct chains, rules and passthroughs.
+// @auth      	  author           2021-10-15
+// @return        settings         *DirectSettings "runtime direct configuration."
+// @return        error            error           ""
+func (c *DbusClientSerivce) GetDirectSettings() (settings *DirectSettings, err error) {
+	c, end := c.span("GetDirectSettings", SCOPE_RUNTIME)
+	defer end(&err)
+	obj := c.object(object.PATH)
+
+	var raw directSettings
+	for method, dest := range map[string]interface{}{
+		object.DIRECT_GETALLCHAINS:       &raw.Chains,
+		object.DIRECT_GETALLRULES:        &raw.Rules,
+		object.DIRECT_GETALLPASSTHROUGHS: &raw.Passthroughs,
+	} {
+		call := obj.Call(method, dbus.FlagNoAutoStart)
+		if call.Err != nil {
+			return nil, call.Err
+		}
+		if err = call.Store(dest); err != nil {
+			return nil, err
+		}
+	}
+	return raw.toDirectSettings(), nil
+}
+
+// @title         PermanentGetDirectSettings
+// @description   Return the permanent direct chains, rules and passthroughs.
+// @auth      	  author           2021-10-15
+// @return        settings         *DirectSettings "permanent direct configuration."
+// @return        error            error           ""
+func (c *DbusClientSerivce) PermanentGetDirectSettings() (settings *DirectSettings, err error) {
+	c, end := c.span("PermanentGetDirectSettings", SCOPE_PERMANENT)
+	defer end(&err)
+	obj := c.object(object.CONFIG_PATH)
+	call := obj.Call(object.CONFIG_DIRECT_GETSETTINGS, dbus.FlagNoAutoStart)
+
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	var raw directSettings
+	if err = call.Store(&raw); err != nil {
+		return nil, err
+	}
+	return raw.toDirectSettings(), nil
+}
+
+// @title         PermanentSetDirectSettings
+// @description   Replace the permanent direct chains, rules and passthroughs in a single update.
+// @auth      	  author           2021-10-15
+// @param         settings         *DirectSettings "the complete direct configuration."
+// @return        error            error           "Possible errors: INVALID_IPV, INVALID_TABLE, INVALID_CHAIN"
+func (c *DbusClientSerivce) PermanentSetDirectSettings(settings *DirectSettings) (err error) {
+	c, end := c.span("PermanentSetDirectSettings", SCOPE_PERMANENT)
+	defer end(&err)
+	obj := c.object(object.CONFIG_PATH)
+	call := obj.Call(object.CONFIG_DIRECT_UPDATE, dbus.FlagNoAutoStart, settings.toTuple())
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+// @title         AddDirectChain
+// @description   temporary Add a new chain to table.
+// @auth      	  author           2021-10-15
+// @param         chain            DirectChain    "e.g. {ipv4 filter mychain}"
+// @return        error            error          "Possible errors: INVALID_IPV, INVALID_TABLE, ALREADY_ENABLED"
+func (c *DbusClientSerivce) AddDirectChain(chain DirectChain) (err error) {
+	c, end := c.span("AddDirectChain", SCOPE_RUNTIME)
+	defer end(&err)
+	return c.direct(object.DIRECT_ADDCHAIN, chain.IPV, chain.Table, chain.Chain)
+}
+
+// @title         RemoveDirectChain
+// @description   temporary Remove a chain from table, only chains added with AddDirectChain can be removed.
+// @auth      	  author           2021-10-15
+// @param         chain            DirectChain    "e.g. {ipv4 filter mychain}"
+// @return        error            error          "Possible errors: INVALID_IPV, INVALID_TABLE, NOT_ENABLED"
+func (c *DbusClientSerivce) RemoveDirectChain(chain DirectChain) (err error) {
+	c, end := c.span("RemoveDirectChain", SCOPE_RUNTIME)
+	defer end(&err)
+	return c.direct(object.DIRECT_REMOVECHAIN, chain.IPV, chain.Table, chain.Chain)
+}
+
+// @title         AddDirectRule
+// @description   temporary Add a rule with the arguments args to a chain in table with priority.
+// @auth      	  author           2021-10-15
+// @param         rule             DirectRule     "e.g. {ipv4 filter INPUT 0 [-p tcp --dport 22 -j ACCEPT]}"
+// @return        error            error          "Possible errors: INVALID_IPV, INVALID_TABLE, ALREADY_ENABLED"
+func (c *DbusClientSerivce) AddDirectRule(rule DirectRule) (err error) {
+	c, end := c.span("AddDirectRule", SCOPE_RUNTIME)
+	defer end(&err)
+	return c.direct(object.DIRECT_ADDRULE, rule.IPV, rule.Table, rule.Chain, int32(rule.Priority), rule.Args)
+}
+
+// @title         RemoveDirectRule
+// @description   temporary Remove a rule with the arguments args and priority from a chain in table.
+// @auth      	  author           2021-10-15
+// @param         rule             DirectRule     "e.g. {ipv4 filter INPUT 0 [-p tcp --dport 22 -j ACCEPT]}"
+// @return        error            error          "Possible errors: INVALID_IPV, INVALID_TABLE, NOT_ENABLED"
+func (c *DbusClientSerivce) RemoveDirectRule(rule DirectRule) (err error) {
+	c, end := c.span("RemoveDirectRule", SCOPE_RUNTIME)
+	defer end(&err)
+	return c.direct(object.DIRECT_REMOVERULE, rule.IPV, rule.Table, rule.Chain, int32(rule.Priority), rule.Args)
+}
+
+// @title         AddDirectPassthrough
+// @description   temporary Add a tracked passthrough rule with the arguments args.
+// @auth      	  author           2021-10-15
+// @param         passthrough      DirectPassthrough "e.g. {ipv4 [-A INPUT -s 192.0.2.1 -j DROP]}"
+// @return        error            error             "Possible errors: INVALID_IPV, ALREADY_ENABLED"
+func (c *DbusClientSerivce) AddDirectPassthrough(passthrough DirectPassthrough) (err error) {
+	c, end := c.span("AddDirectPassthrough", SCOPE_RUNTIME)
+	defer end(&err)
+	return c.direct(object.DIRECT_ADDPASSTHROUGH, passthrough.IPV, passthrough.Args)
+}
+
+// @title         RemoveDirectPassthrough
+// @description   temporary Remove a tracked passthrough rule with the arguments args.
+// @auth      	  author           2021-10-15
+// @param         passthrough      DirectPassthrough "e.g. {ipv4 [-A INPUT -s 192.0.2.1 -j DROP]}"
+// @return        error            error             "Possible errors: INVALID_IPV, NOT_ENABLED"
+func (c *DbusClientSerivce) RemoveDirectPassthrough(passthrough DirectPassthrough) (err error) {
+	c, end := c.span("RemoveDirectPassthrough", SCOPE_RUNTIME)
+	defer end(&err)
+	return c.direct(object.DIRECT_REMOVEPASSTHROUGH, passthrough.IPV, passthrough.Args)
+}
+
+func (c *DbusClientSerivce) direct(method string, args ...interface{}) (err error) {
+	obj := c.object(object.PATH)
+	call := obj.Call(method, dbus.FlagNoAutoStart, args...)
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
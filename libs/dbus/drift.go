@@ -0,0 +1,161 @@
+package dbus
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+ * DriftItem is an item that differs between runtime and permanent configuration of a zone.
+ *   RuntimeOnly  true: the item is only in runtime, e.g. added by AddPort and lost on the next Reload.
+ *                false: the item is only in permanent configuration, e.g. removed by RemovePort.
+ */
+type DriftItem struct {
+	Kind        string `json:"kind"`
+	Value       string `json:"value,omitempty"`
+	RuntimeOnly bool   `json:"runtimeonly"`
+}
+
+// DriftReport lists the differences between runtime and permanent configuration of a zone.
+type DriftReport struct {
+	Zone  string      `json:"zone"`
+	Items []DriftItem `json:"items"`
+}
+
+// IsEmpty reports whether runtime and permanent configuration are the same.
+func (r *DriftReport) IsEmpty() bool {
+	return len(r.Items) == 0
+}
+
+// String renders the report, e.g. "runtime only: port 8080/tcp"
+func (r *DriftReport) String() string {
+	if r.IsEmpty() {
+		return fmt.Sprintf("zone %s: runtime and permanent configuration are the same.\n", r.Zone)
+	}
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "zone %s:\n", r.Zone)
+	for _, item := range r.Items {
+		where := "permanent only"
+		if item.RuntimeOnly {
+			where = "runtime only"
+		}
+		fmt.Fprintf(&builder, "  %s: %s\n", where, strings.TrimSpace(item.Kind+" "+item.Value))
+	}
+	return builder.String()
+}
+
+// Promote returns the permanent operation making permanent configuration match runtime for the item.
+func (item DriftItem) Promote(zone string) Operation {
+	op := Operation{Action: OP_REMOVE, Kind: item.Kind, Zone: zone, Value: item.Value, Permanent: true}
+	if item.RuntimeOnly {
+		op.Action = OP_ADD
+	}
+	return op
+}
+
+// Revert returns the runtime operation making runtime match permanent configuration for the item.
+func (item DriftItem) Revert(zone string) Operation {
+	op := Operation{Action: OP_ADD, Kind: item.Kind, Zone: zone, Value: item.Value}
+	if item.RuntimeOnly {
+		op.Action = OP_REMOVE
+	}
+	return op
+}
+
+/*
+ * @title         Drift
+ * @description   compare runtime and permanent configuration of zone, across ports, services, rich rules,
+ *                  forward ports, masquerade, interfaces and sources.
+ * @auth          author           2021-10-12
+ * @param         zone             string         "If zone is empty string, use default zone. e.g. public|dmz..  "
+ * @return        report           *DriftReport   "the items only in runtime or only in permanent configuration."
+ * @return        error            error          "Possible errors: INVALID_ZONE"
+ */
+func (c *DbusClientSerivce) Drift(zone string) (report *DriftReport, err error) {
+	c, end := c.zoneSpan("Drift", 0, zone)
+	defer end(&err)
+	return zoneDrift(c, zone)
+}
+
+func zoneDrift(c Firewall, zone string) (report *DriftReport, err error) {
+	if zone == "" {
+		zone = c.GetDefaultZone()
+	}
+
+	var runtime, permanent *Settings
+	if runtime, err = c.GetZoneSettings(zone); err != nil {
+		return nil, err
+	}
+	if permanent, err = c.PermanentGetZoneSettings(zone); err != nil {
+		return nil, err
+	}
+
+	// the runtime operations converging runtime to permanent are the drift.
+	revert := &Plan{}
+	if err = revert.diffZone(zone, false, runtime, zoneStateOf(permanent)); err != nil {
+		return nil, err
+	}
+	report = &DriftReport{Zone: zone, Items: []DriftItem{}}
+	for _, op := range revert.Operations {
+		report.Items = append(report.Items, DriftItem{
+			Kind:        op.Kind,
+			Value:       op.Value,
+			RuntimeOnly: op.Action == OP_REMOVE,
+		})
+	}
+	return report, nil
+}
+
+/*
+ * @title         PromoteDrift
+ * @description   make permanent configuration match runtime, for the given items or the whole report if no item is given.
+ * @auth          author           2021-10-12
+ * @param         report           *DriftReport   "the report returned by Drift."
+ * @param         items            ...DriftItem   "the items to promote, e.g. report.Items[0]"
+ * @return        error            error          "the failed item and the error of firewalld."
+ */
+func (c *DbusClientSerivce) PromoteDrift(report *DriftReport, items ...DriftItem) (err error) {
+	c, end := c.span("PromoteDrift", 0)
+	defer end(&err)
+	return promoteDrift(c, report, items...)
+}
+
+func promoteDrift(c Firewall, report *DriftReport, items ...DriftItem) (err error) {
+	if len(items) == 0 {
+		items = report.Items
+	}
+	for _, item := range items {
+		op := item.Promote(report.Zone)
+		if err = c.Execute(op); err != nil {
+			return fmt.Errorf("promote %s failed: %v", op.String(), err)
+		}
+	}
+	return nil
+}
+
+/*
+ * @title         RevertDrift
+ * @description   make runtime match permanent configuration, for the given items or the whole report if no item is given.
+ * @auth          author           2021-10-12
+ * @param         report           *DriftReport   "the report returned by Drift."
+ * @param         items            ...DriftItem   "the items to revert, e.g. report.Items[0]"
+ * @return        error            error          "the failed item and the error of firewalld."
+ */
+func (c *DbusClientSerivce) RevertDrift(report *DriftReport, items ...DriftItem) (err error) {
+	c, end := c.span("RevertDrift", 0)
+	defer end(&err)
+	return revertDrift(c, report, items...)
+}
+
+func revertDrift(c Firewall, report *DriftReport, items ...DriftItem) (err error) {
+	if len(items) == 0 {
+		items = report.Items
+	}
+	for _, item := range items {
+		op := item.Revert(report.Zone)
+		if err = c.Execute(op); err != nil {
+			return fmt.Errorf("revert %s failed: %v", op.String(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package dbus
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/cylonchau/gofirewallder/object"
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+ * DryRun records the changes a client would send to firewalld instead of sending them, e.g.
+ *
+ *   client.DryRun = &dbus.DryRun{Log: log.New(os.Stderr, "dry-run: ", 0)}
+ *   client.Zone("public").AddPort(dbus.SCOPE_BOTH, "8080/tcp", 0)
+ *   for _, call := range client.DryRun.Calls() { ... }
+ *
+ * Reads, the get and query methods, are still sent so checks and plans see the real firewall, it is not changed
+ * by the recorded calls though, e.g. a second addition of the same port is recorded as well.
+ * A recorded call succeeds, the changes of a zone return the zone and added config objects a path ending in dry_run.
+ *   Log  prints each recorded call, none if nil.
+ */
+type DryRun struct {
+	Log *log.Logger
+
+	lock  sync.Mutex
+	calls []DryRunCall
+}
+
+// DryRunCall is a call that was not sent, Interface and Method are the parts of e.g. org.fedoraproject.FirewallD1.zone.addPort.
+type DryRunCall struct {
+	Interface string          `json:"interface"`
+	Method    string          `json:"method"`
+	Path      dbus.ObjectPath `json:"path"`
+	Args      []interface{}   `json:"args,omitempty"`
+}
+
+// String renders the call, e.g. /org/fedoraproject/FirewallD1 org.fedoraproject.FirewallD1.zone.addPort("public", "8080", "tcp", 0)
+func (call DryRunCall) String() string {
+	args := make([]string, 0, len(call.Args))
+	for _, arg := range call.Args {
+		args = append(args, fmt.Sprintf("%#v", arg))
+	}
+	return fmt.Sprintf("%s %s.%s(%s)", call.Path, call.Interface, call.Method, strings.Join(args, ", "))
+}
+
+// Calls returns the recorded calls in their order.
+func (d *DryRun) Calls() []DryRunCall {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return append([]DryRunCall{}, d.calls...)
+}
+
+// Reset drops the recorded calls.
+func (d *DryRun) Reset() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.calls = nil
+}
+
+// Intercept records the changes instead of calling next, reads are passed on, the client runs it innermost.
+func (d *DryRun) Intercept(ctx context.Context, info CallInfo, next Invoker) (Result, error) {
+	if info.Read {
+		return next(ctx, info)
+	}
+	call := DryRunCall{Interface: info.Interface, Method: info.Method, Path: info.Path, Args: info.Args}
+	d.lock.Lock()
+	d.calls = append(d.calls, call)
+	d.lock.Unlock()
+	if d.Log != nil {
+		d.Log.Print(call.String())
+	}
+	return Result{Body: dryRunBody(call)}, nil
+}
+
+// dryRunBody is the reply firewalld would send on success.
+func dryRunBody(call DryRunCall) []interface{} {
+	switch {
+	case call.Interface == object.ZONE && len(call.Args) > 0:
+		if zone, ok := call.Args[0].(string); ok {
+			return []interface{}{zone}
+		}
+	case call.Interface == object.CONFIG_INTERFACE && strings.HasPrefix(call.Method, "add"):
+		kind := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(call.Method, "add"), "2"))
+		return []interface{}{dbus.ObjectPath(object.CONFIG_PATH + "/" + kind + "/dry_run")}
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package dbus
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cylonchau/gofirewallder/object"
+	"github.com/godbus/dbus/v5"
+)
+
+// signal names of firewalld, Event.Name.
+const (
+	EVENT_RELOADED               = "Reloaded"
+	EVENT_DEFAULTZONECHANGED     = "DefaultZoneChanged"
+	EVENT_PORTADDED              = "PortAdded"
+	EVENT_PORTREMOVED            = "PortRemoved"
+	EVENT_SERVICEADDED           = "ServiceAdded"
+	EVENT_SERVICEREMOVED         = "ServiceRemoved"
+	EVENT_RICHRULEADDED          = "RichRuleAdded"
+	EVENT_RICHRULEREMOVED        = "RichRuleRemoved"
+	EVENT_SOURCEADDED            = "SourceAdded"
+	EVENT_SOURCEREMOVED          = "SourceRemoved"
+	EVENT_INTERFACEADDED         = "InterfaceAdded"
+	EVENT_INTERFACEREMOVED       = "InterfaceRemoved"
+	EVENT_FORWARDPORTADDED       = "ForwardPortAdded"
+	EVENT_FORWARDPORTREMOVED     = "ForwardPortRemoved"
+	EVENT_MASQUERADEADDED        = "MasqueradeAdded"
+	EVENT_MASQUERADEREMOVED      = "MasqueradeRemoved"
+	EVENT_ZONEOFINTERFACECHANGED = "ZoneOfInterfaceChanged"
+	EVENT_ZONEOFSOURCECHANGED    = "ZoneOfSourceChanged"
+	EVENT_ENTRYADDED             = "EntryAdded"
+	EVENT_ENTRYREMOVED           = "EntryRemoved"
+	EVENT_UPDATED                = "Updated"
+	EVENT_REMOVED                = "Removed"
+	EVENT_RENAMED                = "Renamed"
+)
+
+// EVENT_OVERFLOW is no signal of firewalld, MemoryFirewall sends it to a slow reader in place of the events it dropped.
+const EVENT_OVERFLOW = "Overflow"
+
+// the bus itself, Events follows the owner of the name of firewalld.
+const (
+	BUS_NAME         = "org.freedesktop.DBus"
+	BUS_GETNAMEOWNER = BUS_NAME + ".GetNameOwner"
+)
+
+// DEFAULT_EVENTS_BUFFER is the number of events buffered for a slow reader before signals are held back.
+const DEFAULT_EVENTS_BUFFER = 64
+
+/*
+ * Event is a decoded firewalld signal, e.g.
+ *   PortAdded        {Name: PortAdded, Zone: public, Item: 80/tcp, Timeout: 0}
+ *   RichRuleAdded    {Name: RichRuleAdded, Zone: public, Item: rule family="ipv4" ... accept}
+ *   EntryAdded       {Name: EntryAdded, IPSet: blocklist, Item: 192.0.2.1}
+ *   Updated          {Name: Updated, Interface: ...config.zone, Item: public, Permanent: true}
+ *   Interface  the D-Bus interface of the signal, e.g. org.fedoraproject.FirewallD1.zone
+ *   Permanent  true for signals of the permanent configuration, the config interfaces.
+ */
+type Event struct {
+	Name      string          `json:"name"`
+	Interface string          `json:"interface"`
+	Path      dbus.ObjectPath `json:"path"`
+	Zone      string          `json:"zone,omitempty"`
+	IPSet     string          `json:"ipset,omitempty"`
+	Item      string          `json:"item,omitempty"`
+	Timeout   int             `json:"timeout,omitempty"`
+	Permanent bool            `json:"permanent"`
+}
+
+/*
+ * @title         Events
+ * @description   subscribe to the signals of firewalld, runtime and permanent configuration.
+ * @auth          author           2021-10-16
+ * @param         ctx              context.Context "cancel to unsubscribe, the channel is closed afterwards."
+ * @return        events           <-chan Event    "decoded signals in the order firewalld emitted them, a restart of firewalld is Reloaded."
+ * @return        error            error           "the match rules could not be added, or firewalld is not on the bus."
+ */
+func (c *DbusClientSerivce) Events(ctx context.Context) (events <-chan Event, err error) {
+	// signals of other peers are dropped, any peer can emit signals with the interfaces of firewalld.
+	var owner string
+	if err = c.Conn.BusObject().Call(BUS_GETNAMEOWNER, 0, object.INTERFACE).Store(&owner); err != nil {
+		return nil, err
+	}
+	options := []dbus.MatchOption{
+		dbus.WithMatchSender(object.INTERFACE),
+		dbus.WithMatchPathNamespace(dbus.ObjectPath(object.PATH)),
+	}
+	ownerOptions := []dbus.MatchOption{
+		dbus.WithMatchSender(BUS_NAME),
+		dbus.WithMatchInterface(BUS_NAME),
+		dbus.WithMatchMember("NameOwnerChanged"),
+		dbus.WithMatchArg(0, object.INTERFACE),
+	}
+	if err = c.Conn.AddMatchSignal(options...); err != nil {
+		return nil, err
+	}
+	if err = c.Conn.AddMatchSignal(ownerOptions...); err != nil {
+		c.Conn.RemoveMatchSignal(options...)
+		return nil, err
+	}
+	signals := make(chan *dbus.Signal, DEFAULT_EVENTS_BUFFER)
+	c.Conn.Signal(signals)
+
+	out := make(chan Event, DEFAULT_EVENTS_BUFFER)
+	go func() {
+		defer close(out)
+		defer c.Conn.RemoveMatchSignal(options...)
+		defer c.Conn.RemoveMatchSignal(ownerOptions...)
+		defer c.Conn.RemoveSignal(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case signal, ok := <-signals:
+				if !ok {
+					return
+				}
+				var event Event
+				switch {
+				case signal.Sender == BUS_NAME && signal.Name == BUS_NAME+".NameOwnerChanged":
+					if len(signal.Body) < 3 || signal.Body[0] != object.INTERFACE {
+						continue
+					}
+					if owner, _ = signal.Body[2].(string); owner == "" {
+						continue
+					}
+					// firewalld was restarted and loaded its configuration again.
+					event = Event{Name: EVENT_RELOADED, Interface: object.INTERFACE, Path: dbus.ObjectPath(object.PATH)}
+				case signal.Sender != owner:
+					continue
+				default:
+					if event, ok = decodeEvent(signal); !ok {
+						continue
+					}
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// decodeEvent converts a signal of firewalld into Event, ok is false for signals of other services.
+func decodeEvent(signal *dbus.Signal) (event Event, ok bool) {
+	index := strings.LastIndex(signal.Name, ".")
+	if index < 0 || !strings.HasPrefix(signal.Name, object.INTERFACE) {
+		return event, false
+	}
+	event = Event{
+		Name:      signal.Name[index+1:],
+		Interface: signal.Name[:index],
+		Path:      signal.Path,
+		Permanent: strings.HasPrefix(signal.Name, object.CONFIG_INTERFACE),
+	}
+
+	var strs []string
+	for _, value := range signal.Body {
+		switch value := value.(type) {
+		case string:
+			strs = append(strs, value)
+		case int32:
+			event.Timeout = int(value)
+		}
+	}
+	arg := func(index int) string {
+		if index < len(strs) {
+			return strs[index]
+		}
+		return ""
+	}
+
+	switch {
+	case event.Permanent:
+		// config signals carry the object name, e.g. Updated("public") of config.zone
+		event.Item = arg(0)
+		if event.Interface == object.CONFIG_ZONE {
+			event.Zone = arg(0)
+		}
+	case event.Interface == object.IPSET:
+		event.IPSet, event.Item = arg(0), arg(1)
+	case event.Name == EVENT_PORTADDED || event.Name == EVENT_PORTREMOVED ||
+		event.Name == "SourcePortAdded" || event.Name == "SourcePortRemoved":
+		event.Zone, event.Item = arg(0), arg(1)+"/"+arg(2)
+	case event.Name == EVENT_FORWARDPORTADDED || event.Name == EVENT_FORWARDPORTREMOVED:
+		event.Zone = arg(0)
+		event.Item = ForwardPortToString(ForwardPort{Port: arg(1), Protocol: arg(2), ToPort: arg(3), ToAddr: arg(4)})
+	case event.Interface == object.INTERFACE && event.Name == EVENT_DEFAULTZONECHANGED:
+		event.Zone = arg(0)
+	default:
+		event.Zone, event.Item = arg(0), arg(1)
+	}
+	return event, true
+}
+
+/*
+ * Signal returns the signal firewalld emits for e, the reverse of the decoding of Events, e.g.
+ *   PortAdded  org.fedoraproject.FirewallD1.zone.PortAdded("public", "80", "tcp", 0)
+ *   Updated    org.fedoraproject.FirewallD1.config.zone.Updated("public")
+ * The path is e.Path, or the object path of the interface if it is empty.
+ */
+func (e Event) Signal() *dbus.Signal {
+	signal := &dbus.Signal{
+		Sender: object.INTERFACE,
+		Path:   e.Path,
+		Name:   e.Interface + "." + e.Name,
+	}
+	if signal.Path == "" {
+		signal.Path = dbus.ObjectPath(object.PATH)
+		if e.Permanent {
+			signal.Path = dbus.ObjectPath(object.CONFIG_PATH)
+		}
+	}
+
+	switch {
+	case e.Name == EVENT_RELOADED:
+	case e.Permanent:
+		signal.Body = []interface{}{e.Item}
+	case e.Interface == object.IPSET:
+		signal.Body = []interface{}{e.IPSet, e.Item}
+	case e.Name == EVENT_DEFAULTZONECHANGED:
+		signal.Body = []interface{}{e.Zone}
+	case e.Name == EVENT_PORTADDED || e.Name == EVENT_PORTREMOVED:
+		port, protocol := splitPortProtocol(e.Item)
+		signal.Body = []interface{}{e.Zone, port, protocol}
+	case e.Name == EVENT_FORWARDPORTADDED || e.Name == EVENT_FORWARDPORTREMOVED:
+		forward, _ := StringToForwardPort(e.Item)
+		signal.Body = []interface{}{e.Zone, forward.Port, forward.Protocol, forward.ToPort, forward.ToAddr}
+	case e.Name == EVENT_MASQUERADEADDED || e.Name == EVENT_MASQUERADEREMOVED:
+		signal.Body = []interface{}{e.Zone}
+	default:
+		signal.Body = []interface{}{e.Zone, e.Item}
+	}
+	// runtime additions carry their timeout as the last argument.
+	if !e.Permanent && e.Interface == object.ZONE && strings.HasSuffix(e.Name, "Added") {
+		signal.Body = append(signal.Body, int32(e.Timeout))
+	}
+	return signal
+}
+
+// Operation returns the change described by a runtime event, ok is false for events without an Operation kind.
+func (e Event) Operation() (op Operation, ok bool) {
+	op = Operation{Action: OP_ADD, Zone: e.Zone, Value: e.Item, Timeout: e.Timeout}
+	name := e.Name
+	switch {
+	case e.Permanent:
+		return op, false
+	case strings.HasSuffix(name, "Removed"):
+		op.Action = OP_REMOVE
+		name = strings.TrimSuffix(name, "Removed")
+	case strings.HasSuffix(name, "Added"):
+		name = strings.TrimSuffix(name, "Added")
+	}
+
+	switch {
+	case e.Name == EVENT_RELOADED:
+		return Operation{Kind: KIND_RELOAD}, true
+	case e.Name == EVENT_DEFAULTZONECHANGED:
+		return Operation{Action: OP_SET, Kind: KIND_DEFAULTZONE, Value: e.Zone, Permanent: true}, true
+	case e.Interface == object.IPSET && name == "Entry":
+		op.Kind, op.IPSet, op.Zone = KIND_IPSETENTRY, e.IPSet, ""
+	case e.Interface != object.ZONE:
+		return op, false
+	case name == "Port":
+		op.Kind = KIND_PORT
+	case name == "Service":
+		op.Kind = KIND_SERVICE
+	case name == "RichRule":
+		op.Kind = KIND_RICHRULE
+	case name == "Source":
+		op.Kind = KIND_SOURCE
+	case name == "Interface":
+		op.Kind = KIND_INTERFACE
+	case name == "ForwardPort":
+		op.Kind = KIND_FORWARDPORT
+	case name == "Masquerade":
+		op.Kind, op.Value = KIND_MASQUERADE, ""
+	default:
+		return op, false
+	}
+	return op, true
+}
@@ -0,0 +1,158 @@
+package dbus
+
+import (
+	"context"
+)
+
+/*
+ * Firewall is the API of a firewalld host, depend on it instead of *DbusClientSerivce to test without firewalld.
+ *   DbusClientSerivce  talks to firewalld over D-Bus.
+ *   MemoryFirewall     models firewalld in memory, see NewMemoryFirewall.
+ *   AuditedFirewall    records the changes of another Firewall, see Auditor.Wrap.
+ * Methods that need the D-Bus connection stay on DbusClientSerivce:
+ *   Addr, EnableCache, DisableCache, Cache, Unguarded, Snapshot and Restore, AuditedFirewall records the last two.
+ */
+type Firewall interface {
+	Close() error
+	Events(ctx context.Context) (<-chan Event, error)
+	Execute(op Operation) error
+	NewTx(ops ...Operation) *Tx
+	Zone(zone string) *Zone
+
+	// zones
+	GetDefaultZone() string
+	SetDefaultZone(zone string) error
+	GetZones() ([]string, error)
+	GetZoneSettings(zone string) (*Settings, error)
+	GetZoneOfInterface(iface string) string
+	AddZone(name string) error
+	PermanentGetZones() ([]string, error)
+	PermanentGetZoneSettings(zone string) (*Settings, error)
+	PermanentSetZoneSettings(zone string, settings *Settings) error
+	PermanentRemoveZone(zone string) error
+	PermanentSetZoneTarget(zone, target string) error
+
+	// ports and protocols
+	AddPort(port, zone string, timeout int) (string, error)
+	PermanentAddPort(port, zone string) error
+	GetPort(zone string) ([]*Port, error)
+	PermanentGetPort(zone string) ([]*Port, error)
+	RemovePort(port, zone string) (bool, error)
+	PermanentRemovePort(port, zone string) (bool, error)
+	PermanentAddPorts(zone string, ports []Port) ([]Conflict, error)
+	PermanentRemovePorts(zone string, ports []Port) ([]Conflict, error)
+	AddProtocol(zone, protocol string, timeout int) (string, error)
+
+	// services
+	AddService(zone, service string, timeout int) (string, error)
+	PermanentAddService(zone, service string) error
+	QueryService(zone, service string) bool
+	PermanentQueryService(zone, service string) bool
+	RemoveService(zone, service string) error
+	PermanentRemoveService(zone, service string) error
+	PermanentSetServices(zone string, services []string) ([]Conflict, error)
+	GetServiceSettings(service string) (*ServiceSettings, error)
+	PermanentGetServices() ([]string, error)
+	PermanentGetServiceSettings(service string) (*ServiceSettings, error)
+
+	// masquerade
+	EnableMasquerade(zone string, timeout int) error
+	PermanentEnableMasquerade(zone string) error
+	DisableMasquerade(zone string) error
+	PermanentDisableMasquerade(zone string) error
+	QueryMasquerade(zone string) (bool, error)
+	PermanentQueryMasquerade(zone string) (bool, error)
+
+	// interfaces
+	BindInterface(zone, iface string) (string, error)
+	PermanentBindInterface(zone, iface string) error
+	QueryInterface(zone, iface string) (bool, error)
+	PermanentQueryInterface(zone, iface string) error
+	RemoveInterface(zone, iface string) error
+	PermanentRemoveInterface(zone, iface string) error
+
+	// sources
+	AddSource(zone, source string) (string, error)
+	PermanentAddSource(zone, source string) error
+	QuerySource(zone, source string) (bool, error)
+	PermanentQuerySource(zone, source string) (bool, error)
+	RemoveSource(zone, source string) error
+	PermanentRemoveSource(zone, source string) error
+
+	// forward ports
+	AddForwardPort(zone string, portProtocol, toHostPort string, timeout int) error
+	PermanentAddForwardPort(zone string, portProtocol, toHostPort string) error
+	RemoveForwardPort(zone string, portProtocol, toHostPort string) error
+	PermanentRemoveForwardPort(zone string, portProtocol, toHostPort string) error
+	QueryForwardPort(zone string, portProtocol, toHostPort string) bool
+	PermanentQueryForwardPort(zone string, portProtocol, toHostPort string) (bool, error)
+
+	// rich rules
+	GetRichRules(zone string) ([]*Rule, error)
+	AddRichRule(zone string, rule *Rule, timeout int) error
+	PermanentAddRichRule(zone string, rule *Rule) error
+	RemoveRichRule(zone string, rule *Rule) error
+	PermanentRemoveRichRule(zone string, rule *Rule) error
+	QueryRichRule(zone string, rule *Rule) bool
+	PermanentQueryRichRule(zone string, rule *Rule) bool
+	PermanentReplaceRichRules(zone string, rules []string) ([]Conflict, error)
+
+	// ipsets
+	GetIPSets() ([]string, error)
+	GetIPSetEntries(ipset string) ([]string, error)
+	AddIPSetEntry(ipset, entry string) error
+	RemoveIPSetEntry(ipset, entry string) error
+	QueryIPSetEntry(ipset, entry string) (bool, error)
+	PermanentGetIPSets() ([]string, error)
+	PermanentGetIPSetSettings(ipset string) (*IPSetSettings, error)
+	PermanentAddIPSet(ipset, ipsetType string) error
+	PermanentSetIPSetSettings(ipset string, settings *IPSetSettings) error
+	PermanentRemoveIPSet(ipset string) error
+	PermanentGetIPSetEntries(ipset string) ([]string, error)
+	PermanentAddIPSetEntry(ipset, entry string) error
+	PermanentRemoveIPSetEntry(ipset, entry string) error
+	PermanentQueryIPSetEntry(ipset, entry string) (bool, error)
+
+	// policies
+	GetPolicies() ([]string, error)
+	GetPolicySettings(policy string) (*PolicySettings, error)
+	SetPolicySettings(policy string, settings *PolicySettings) error
+	PermanentGetPolicies() ([]string, error)
+	PermanentGetPolicySettings(policy string) (*PolicySettings, error)
+	PermanentSetPolicySettings(policy string, settings *PolicySettings) error
+	PermanentAddPolicy(policy string, settings *PolicySettings) error
+	PermanentRemovePolicy(policy string) error
+
+	// direct
+	GetDirectSettings() (*DirectSettings, error)
+	PermanentGetDirectSettings() (*DirectSettings, error)
+	PermanentSetDirectSettings(settings *DirectSettings) error
+	AddDirectChain(chain DirectChain) error
+	RemoveDirectChain(chain DirectChain) error
+	AddDirectRule(rule DirectRule) error
+	RemoveDirectRule(rule DirectRule) error
+	AddDirectPassthrough(passthrough DirectPassthrough) error
+	RemoveDirectPassthrough(passthrough DirectPassthrough) error
+
+	// whole firewall
+	Reload() error
+	RuntimeToPermanent() error
+	RuntimeFlush(zone string) error
+	QueryPanicMode() (bool, error)
+	GetState() (string, error)
+
+	// desired state, drift and simulation
+	Plan(desired *DesiredState) (*Plan, error)
+	Apply(plan *Plan) error
+	Drift(zone string) (*DriftReport, error)
+	PromoteDrift(report *DriftReport, items ...DriftItem) error
+	RevertDrift(report *DriftReport, items ...DriftItem) error
+	NewSimulator() (*Simulator, error)
+	Simulate(flow Flow) (*Verdict, error)
+}
+
+var (
+	_ Firewall = (*DbusClientSerivce)(nil)
+	_ Firewall = (*MemoryFirewall)(nil)
+	_ Firewall = (*AuditedFirewall)(nil)
+)
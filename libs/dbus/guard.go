@@ -0,0 +1,407 @@
+package dbus
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+/*
+ * Guard describes the management path of a host, changes that would block it are refused.
+ *   Interface  the management NIC of the host, e.g. eth0. If empty, it is found when the client runs on the host,
+ *              otherwise every zone the management connection could arrive in is checked,
+ *              set it to allow changes of zones the NIC is not in.
+ *   Ports      management ports besides the port of the client connection, e.g. 22/tcp for ssh.
+ */
+type Guard struct {
+	Interface string
+	Ports     []string
+}
+
+// NewGuard returns the default guard of new clients, protecting the client connection and ssh, every checked change
+// fetches the settings of all zones first.
+func NewGuard() *Guard {
+	return &Guard{Ports: []string{"22/tcp"}}
+}
+
+// Unguarded returns a client sharing the connection that does not check for lockout, the explicit override.
+func (c *DbusClientSerivce) Unguarded() *DbusClientSerivce {
+	client := *c
+	client.unguarded = true
+	return &client
+}
+
+/*
+ * @title         checkLockout
+ * @description   refuse ops if together they would block the management connection.
+ *                  the connection is modelled as a flow from the client address to the firewalld address,
+ *                  it is evaluated by a Simulator before and after ops are applied to the fetched settings,
+ *                  the settings are fetched once per scope however many ops there are.
+ * @auth          author           2021-10-15
+ * @param         ops              []Operation    "the changes about to be executed, in order."
+ * @return        error            error          "Possible errors: LOCKOUT"
+ */
+func (c *DbusClientSerivce) checkLockout(ops ...Operation) (err error) {
+	if !c.guarded() {
+		return nil
+	}
+	for _, permanent := range []bool{true, false} {
+		var scoped []Operation
+		var mayBlock bool
+		for _, op := range ops {
+			if op.Permanent == permanent {
+				scoped = append(scoped, op)
+				mayBlock = mayBlock || op.mayBlock()
+			}
+		}
+		if !mayBlock {
+			continue
+		}
+		what := scoped[0].String()
+		if len(scoped) > 1 {
+			what = fmt.Sprintf("%s and %d more change(s)", what, len(scoped)-1)
+		}
+		err = c.checkChange(what, permanent, func(before *Simulator) (*Simulator, error) {
+			if len(scoped) == 1 && scoped[0].Kind == KIND_RELOAD {
+				// the runtime configuration becomes the permanent one.
+				return c.newSimulator(true)
+			}
+			after := before.clone()
+			for _, op := range scoped {
+				if err := after.apply(op); err != nil {
+					return nil, err
+				}
+			}
+			return after, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// guarded reports whether the changes of c are checked, the client must be remote over tcp and have a Guard.
+func (c *DbusClientSerivce) guarded() bool {
+	if c.unguarded || c.Guard == nil {
+		return false
+	}
+	if _, ok := c.localAddr.(*net.TCPAddr); !ok {
+		return false
+	}
+	remote, ok := c.remoteAddr.(*net.TCPAddr)
+	return ok && !remote.IP.IsLoopback()
+}
+
+// checkChange refuses the change described by what if the management connection is accepted by the runtime, or the
+// permanent, settings before it and not by the settings change returns.
+func (c *DbusClientSerivce) checkChange(what string, permanent bool, change func(before *Simulator) (*Simulator, error)) (err error) {
+	if !c.guarded() {
+		return nil
+	}
+	local, remote := c.localAddr.(*net.TCPAddr), c.remoteAddr.(*net.TCPAddr)
+
+	var before, after *Simulator
+	if before, err = c.newSimulator(permanent); err != nil {
+		return fmt.Errorf("LOCKOUT: can not verify %s: %v", what, err)
+	}
+	interfaces := c.Guard.interfaces(before, remote.IP)
+	for _, iface := range interfaces {
+		if iface != "" && before.zoneOfInterface(iface) == "" {
+			if zone := c.GetZoneOfInterface(iface); zone != "" {
+				before.Interfaces[iface] = zone
+			}
+		}
+	}
+	if after, err = change(before); err != nil {
+		return err
+	}
+	for iface, zone := range before.Interfaces {
+		if after.zoneOfInterface(iface) == "" {
+			after.Interfaces[iface] = zone
+		}
+	}
+
+	ports := append([]string{strconv.Itoa(remote.Port) + "/tcp"}, c.Guard.Ports...)
+	for _, iface := range interfaces {
+		for _, port := range ports {
+			port, protocol := splitPortProtocol(port)
+			flow := Flow{
+				Interface:   iface,
+				Source:      local.IP.String(),
+				Destination: remote.IP.String(),
+				Protocol:    protocol,
+				Port:        port,
+			}
+			was, err := before.Evaluate(flow)
+			if err != nil || was.Action != VERDICT_ACCEPT {
+				continue
+			}
+			now, err := after.Evaluate(flow)
+			if err != nil {
+				return fmt.Errorf("LOCKOUT: can not verify %s: %v", what, err)
+			}
+			if now.Action != VERDICT_ACCEPT {
+				return fmt.Errorf("LOCKOUT: %s would %s the management connection %s -> %s/%s in zone %s (%s), use Unguarded to override",
+					what, now.Action, flow.Source, net.JoinHostPort(flow.Destination, port), protocol, now.Zone, now.Match)
+			}
+		}
+	}
+	return nil
+}
+
+// checkSettings refuses to replace the permanent settings of zone with settings if that would block the management connection.
+// All changes of the update are checked together, against the settings they result in, not one by one.
+func (c *DbusClientSerivce) checkSettings(zone string, settings *Settings) error {
+	return c.checkChange("replace permanent zone="+zone+" settings", true, func(before *Simulator) (*Simulator, error) {
+		if _, ok := before.Zones[zone]; !ok {
+			return nil, fmt.Errorf("INVALID_ZONE: %s", zone)
+		}
+		after := before.clone()
+		after.Zones[zone] = settings
+		return after, nil
+	})
+}
+
+// checkRestore refuses to restore snapshot if the runtime configuration it leaves behind would block the management
+// connection, services and ipsets the snapshot does not hold keep their current settings.
+func (c *DbusClientSerivce) checkRestore(snapshot *Snapshot) error {
+	what := "restore of the snapshot taken " + snapshot.Taken.Format(time.RFC3339)
+	return c.checkChange(what, false, func(before *Simulator) (*Simulator, error) {
+		after := &Simulator{
+			DefaultZone: before.DefaultZone,
+			Zones:       make(map[string]*Settings, len(snapshot.Zones)),
+			Interfaces:  make(map[string]string),
+			Services:    make(map[string]*ServiceSettings, len(before.Services)),
+			IPSets:      make(map[string][]string, len(before.IPSets)),
+		}
+		if snapshot.DefaultZone != "" {
+			after.DefaultZone = snapshot.DefaultZone
+		}
+		for name, zone := range snapshot.Zones {
+			if after.Zones[name] = zone.Runtime; zone.Runtime == nil {
+				after.Zones[name] = zone.Permanent
+			}
+		}
+		for name, settings := range before.Services {
+			after.Services[name] = settings
+		}
+		for name, settings := range snapshot.Services {
+			after.Services[name] = settings
+		}
+		for name, entries := range before.IPSets {
+			after.IPSets[name] = entries
+		}
+		for name, ipset := range snapshot.IPSets {
+			if after.IPSets[name] = ipset.Runtime; ipset.Runtime == nil && ipset.Permanent != nil {
+				after.IPSets[name] = ipset.Permanent.Entry
+			}
+		}
+		return after, nil
+	})
+}
+
+// mayBlock reports whether op can stop a connection from being accepted.
+func (op Operation) mayBlock() bool {
+	switch op.Kind {
+	case KIND_PORT, KIND_SERVICE:
+		return op.Action == OP_REMOVE
+	case KIND_SOURCE, KIND_FORWARDPORT:
+		return op.Action == OP_ADD
+	case KIND_INTERFACE, KIND_RICHRULE, KIND_TARGET, KIND_DEFAULTZONE, KIND_RELOAD:
+		return true
+	case KIND_ZONE:
+		return op.Action == OP_REMOVE
+	}
+	return false
+}
+
+// interfaces returns the interfaces the management connection may arrive on, "" stands for no interface binding.
+func (g *Guard) interfaces(s *Simulator, remote net.IP) []string {
+	if g.Interface != "" {
+		return []string{g.Interface}
+	}
+	// the client runs on the host, the interface holding the address is the management NIC.
+	if ifaces, err := net.Interfaces(); err == nil {
+		for _, iface := range ifaces {
+			addrs, _ := iface.Addrs()
+			for _, addr := range addrs {
+				if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.Equal(remote) {
+					return []string{iface.Name}
+				}
+			}
+		}
+	}
+	interfaces := []string{""}
+	for iface := range s.Interfaces {
+		interfaces = append(interfaces, iface)
+	}
+	for _, name := range zoneNames(s.Zones) {
+		for _, iface := range s.Zones[name].Interface {
+			if !contains(interfaces, iface.Name) {
+				interfaces = append(interfaces, iface.Name)
+			}
+		}
+	}
+	return interfaces
+}
+
+func (s *Simulator) zoneOfInterface(iface string) string {
+	if zone, ok := s.Interfaces[iface]; ok {
+		return zone
+	}
+	for _, name := range zoneNames(s.Zones) {
+		for _, value := range s.Zones[name].Interface {
+			if value.Name == iface {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// clone copies the simulator, the settings of the zones are copied so apply does not change s.
+func (s *Simulator) clone() *Simulator {
+	clone := &Simulator{
+		DefaultZone: s.DefaultZone,
+		Zones:       make(map[string]*Settings, len(s.Zones)),
+		Interfaces:  make(map[string]string, len(s.Interfaces)),
+		Services:    s.Services,
+		IPSets:      s.IPSets,
+	}
+	for name, settings := range s.Zones {
+		copied := *settings
+		copied.Service = append([]string{}, settings.Service...)
+		copied.Port = append([]Port{}, settings.Port...)
+		copied.Source = append([]Source{}, settings.Source...)
+		copied.Interface = append([]Interface{}, settings.Interface...)
+		copied.ForwardPort = append([]ForwardPort{}, settings.ForwardPort...)
+		copied.Rule = append([]Rule{}, settings.Rule...)
+		clone.Zones[name] = &copied
+	}
+	for iface, zone := range s.Interfaces {
+		clone.Interfaces[iface] = zone
+	}
+	return clone
+}
+
+// apply changes the fetched settings as op would change firewalld.
+func (s *Simulator) apply(op Operation) (err error) {
+	zone := op.Zone
+	if zone == "" {
+		zone = s.DefaultZone
+	}
+	settings, ok := s.Zones[zone]
+	if !ok && op.Kind != KIND_ZONE && op.Kind != KIND_DEFAULTZONE && op.Kind != KIND_IPSET && op.Kind != KIND_IPSETENTRY && op.Kind != KIND_RELOAD {
+		return fmt.Errorf("INVALID_ZONE: %s", zone)
+	}
+	add := op.Action == OP_ADD
+
+	switch op.Kind {
+	case KIND_ZONE:
+		if add {
+			s.Zones[zone] = &Settings{Short: zone, Targe: "default"}
+		} else {
+			delete(s.Zones, zone)
+		}
+	case KIND_PORT:
+		port, protocol := splitPortProtocol(op.Value)
+		value := Port{Port: port, Protocol: protocol}
+		settings.Port = removePort(settings.Port, value)
+		if add {
+			settings.Port = append(settings.Port, value)
+		}
+	case KIND_SERVICE:
+		var services []string
+		for _, service := range settings.Service {
+			if service != op.Value {
+				services = append(services, service)
+			}
+		}
+		if add {
+			services = append(services, op.Value)
+		}
+		settings.Service = services
+	case KIND_SOURCE:
+		var sources []Source
+		for _, source := range settings.Source {
+			if sourceString(source) != op.Value {
+				sources = append(sources, source)
+			}
+		}
+		if add {
+			sources = append(sources, stringToSource(op.Value))
+		}
+		settings.Source = sources
+	case KIND_INTERFACE:
+		for _, other := range s.Zones {
+			var interfaces []Interface
+			for _, iface := range other.Interface {
+				if iface.Name != op.Value || (!add && other != settings) {
+					interfaces = append(interfaces, iface)
+				}
+			}
+			other.Interface = interfaces
+		}
+		if bound, ok := s.Interfaces[op.Value]; ok && (add || bound == zone) {
+			delete(s.Interfaces, op.Value)
+		}
+		if add {
+			settings.Interface = append(settings.Interface, Interface{Name: op.Value})
+			s.Interfaces[op.Value] = zone
+		}
+	case KIND_FORWARDPORT:
+		var forward ForwardPort
+		if forward, err = StringToForwardPort(op.Value); err != nil {
+			return err
+		}
+		var forwards []ForwardPort
+		for _, value := range settings.ForwardPort {
+			if value != forward {
+				forwards = append(forwards, value)
+			}
+		}
+		if add {
+			forwards = append(forwards, forward)
+		}
+		settings.ForwardPort = forwards
+	case KIND_RICHRULE:
+		rule := StringToRule(op.Value)
+		var rules []Rule
+		for _, value := range settings.Rule {
+			if value.ToString() != rule.ToString() {
+				rules = append(rules, value)
+			}
+		}
+		if add {
+			rules = append(rules, *rule)
+		}
+		settings.Rule = rules
+	case KIND_TARGET:
+		settings.Targe = op.Value
+	case KIND_DEFAULTZONE:
+		if _, ok := s.Zones[op.Value]; !ok {
+			return fmt.Errorf("INVALID_ZONE: %s", op.Value)
+		}
+		s.DefaultZone = op.Value
+	}
+	return nil
+}
+
+// forwardPortOperation returns the operation of a forward port given as 80/tcp and 10.0.0.2:8080.
+func forwardPortOperation(action, zone, portProtocol, toHostPort string, permanent bool) Operation {
+	port, protocol := splitPortProtocol(portProtocol)
+	toAddr, toPort, _ := net.SplitHostPort(toHostPort)
+	forward := ForwardPort{Port: port, Protocol: protocol, ToPort: toPort, ToAddr: toAddr}
+	return Operation{Action: action, Kind: KIND_FORWARDPORT, Zone: zone, Value: ForwardPortToString(forward), Permanent: permanent}
+}
+
+func removePort(ports []Port, port Port) (list []Port) {
+	for _, value := range ports {
+		if value != port {
+			list = append(list, value)
+		}
+	}
+	return list
+}
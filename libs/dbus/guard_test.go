@@ -0,0 +1,110 @@
+package dbus_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/libs/dbustest"
+)
+
+// remoteConn makes a connection to the fake server look like one from 10.0.0.2 to the host 10.0.0.1:55557.
+type remoteConn struct {
+	net.Conn
+}
+
+func (remoteConn) LocalAddr() net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 40000}
+}
+
+func (remoteConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 55557}
+}
+
+// newRemoteClient connects a client that is guarded like one managing a remote host.
+func newRemoteClient(t *testing.T) (*dbustest.Server, *dbus.DbusClientSerivce) {
+	t.Helper()
+	server, err := dbustest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+	transport, err := net.Dial("tcp", server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := dbus.NewDbusClientServiceOf(remoteConn{transport}, "10.0.0.1:55557")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func isLockout(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "LOCKOUT")
+}
+
+func TestCheckLockoutSSH(t *testing.T) {
+	server, client := newRemoteClient(t)
+	if client.Guard == nil {
+		t.Fatal("new clients have no Guard")
+	}
+
+	if err := client.RemoveService("public", "ssh"); !isLockout(err) {
+		t.Errorf("RemoveService ssh: got %v, want LOCKOUT", err)
+	}
+	if err := client.PermanentRemoveService("public", "ssh"); !isLockout(err) {
+		t.Errorf("PermanentRemoveService ssh: got %v, want LOCKOUT", err)
+	}
+	if !server.Firewall.QueryService("public", "ssh") {
+		t.Fatal("ssh was removed from runtime although the change was refused")
+	}
+	// ssh of another zone is not on the management path.
+	if err := client.RemoveService("dmz", "ssh"); err != nil {
+		t.Errorf("RemoveService ssh of dmz: %v", err)
+	}
+	if err := client.Unguarded().RemoveService("public", "ssh"); err != nil {
+		t.Errorf("Unguarded RemoveService ssh: %v", err)
+	}
+}
+
+func TestCheckLockoutTarget(t *testing.T) {
+	_, client := newRemoteClient(t)
+	client.Guard = &dbus.Guard{Interface: "eth1"}
+
+	for _, change := range []func() error{
+		func() error { _, err := client.BindInterface("trusted", "eth1"); return err },
+		func() error { return client.PermanentBindInterface("trusted", "eth1") },
+	} {
+		if err := change(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the management connection arrives on eth1 and is only accepted by the target of trusted.
+	if err := client.PermanentSetZoneTarget("trusted", "DROP"); !isLockout(err) {
+		t.Errorf("PermanentSetZoneTarget trusted DROP: got %v, want LOCKOUT", err)
+	}
+	// services still accept connections in a zone with target DROP.
+	client.Guard = dbus.NewGuard()
+	if err := client.PermanentSetZoneTarget("public", "DROP"); err != nil {
+		t.Errorf("PermanentSetZoneTarget public DROP: %v", err)
+	}
+}
+
+func TestCheckLockoutManagementPort(t *testing.T) {
+	_, client := newRemoteClient(t)
+
+	if _, err := client.AddPort("55557/tcp", "public", 0); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.RemovePort("55557/tcp", "public")
+	if !isLockout(err) || !strings.Contains(err.Error(), "10.0.0.1:55557") {
+		t.Errorf("RemovePort 55557/tcp: got %v, want LOCKOUT of 10.0.0.1:55557", err)
+	}
+	// services off the management path can still be removed.
+	if err = client.RemoveService("public", "dhcpv6-client"); err != nil {
+		t.Errorf("RemoveService dhcpv6-client: %v", err)
+	}
+}
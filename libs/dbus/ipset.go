@@ -0,0 +1,313 @@
+package dbus
+
+import (
+	"errors"
+
+	"github.com/cylonchau/gofirewallder/object"
+	"github.com/godbus/dbus/v5"
+)
+
+/************************************************** ipset area ***********************************************************/
+
+// @title         GetIPSets
+// @description   Return list of ipset names of the runtime configuration.
+// @auth      	  author           2021-10-11
+// @return        ipsets           []string       "ipset names."
+// @return        error            error          ""
+func (c *DbusClientSerivce) GetIPSets() (ipsets []string, err error) {
+	c, end := c.span("GetIPSets", SCOPE_RUNTIME)
+	defer end(&err)
+	obj := c.object(object.PATH)
+	call := obj.Call(object.IPSET_GETIPSETS, dbus.FlagNoAutoStart)
+
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	return call.Body[0].([]string), nil
+}
+
+// @title         GetIPSetEntries
+// @description   Return list of entries of the runtime ipset.
+// @auth      	  author           2021-10-11
+// @param         ipset            string         "ipset name."
+// @return        entries          []string       "e.g. 10.0.0.1, 10.1.0.0/16"
+// @return        error            error          "Possible errors: INVALID_IPSET"
+func (c *DbusClientSerivce) GetIPSetEntries(ipset string) (entries []string, err error) {
+	c, end := c.span("GetIPSetEntries", SCOPE_RUNTIME)
+	defer end(&err)
+	obj := c.object(object.PATH)
+	call := obj.Call(object.IPSET_GETENTRIES, dbus.FlagNoAutoStart, ipset)
+
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	return call.Body[0].([]string), nil
+}
+
+// @title         AddIPSetEntry
+// @description   temporary Add a new entry to the ipset.
+// @auth      	  author           2021-10-11
+// @param         ipset            string         "ipset name."
+// @param         entry            string         "e.g. 10.0.0.1, 10.1.0.0/16"
+// @return        error            error          "Possible errors: INVALID_IPSET, IPSET_WITH_TIMEOUT, ALREADY_ENABLED"
+func (c *DbusClientSerivce) AddIPSetEntry(ipset, entry string) (err error) {
+	c, end := c.span("AddIPSetEntry", SCOPE_RUNTIME)
+	defer end(&err)
+	obj := c.object(object.PATH)
+	call := obj.Call(object.IPSET_ADDENTRY, dbus.FlagNoAutoStart, ipset, entry)
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+// @title         RemoveIPSetEntry
+// @description   temporary Remove an entry from the ipset.
+// @auth      	  author           2021-10-11
+// @param         ipset            string         "ipset name."
+// @param         entry            string         "e.g. 10.0.0.1, 10.1.0.0/16"
+// @return        error            error          "Possible errors: INVALID_IPSET, IPSET_WITH_TIMEOUT, NOT_ENABLED"
+func (c *DbusClientSerivce) RemoveIPSetEntry(ipset, entry string) (err error) {
+	c, end := c.span("RemoveIPSetEntry", SCOPE_RUNTIME)
+	defer end(&err)
+	obj := c.object(object.PATH)
+	call := obj.Call(object.IPSET_REMOVEENTRY, dbus.FlagNoAutoStart, ipset, entry)
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+// @title         QueryIPSetEntry
+// @description   temporary Return whether the entry has been added to the ipset.
+// @auth      	  author           2021-10-11
+// @param         ipset            string         "ipset name."
+// @param         entry            string         "e.g. 10.0.0.1, 10.1.0.0/16"
+// @return        b                bool           "true:enable, fales:disable."
+// @return        error            error          "Possible errors: INVALID_IPSET"
+func (c *DbusClientSerivce) QueryIPSetEntry(ipset, entry string) (b bool, err error) {
+	c, end := c.span("QueryIPSetEntry", SCOPE_RUNTIME)
+	defer end(&err)
+	obj := c.object(object.PATH)
+	call := obj.Call(object.IPSET_QUERYENTRY, dbus.FlagNoAutoStart, ipset, entry)
+
+	if len(call.Body) <= 0 || !call.Body[0].(bool) {
+		return false, call.Err
+	}
+	return true, nil
+}
+
+// @title         PermanentGetIPSets
+// @description   Return list of ipset names of the permanent configuration.
+// @auth      	  author           2021-10-11
+// @return        ipsets           []string       "ipset names."
+// @return        error            error          ""
+func (c *DbusClientSerivce) PermanentGetIPSets() (ipsets []string, err error) {
+	c, end := c.span("PermanentGetIPSets", SCOPE_PERMANENT)
+	defer end(&err)
+	obj := c.object(object.CONFIG_PATH)
+	call := obj.Call(object.CONFIG_GETIPSETNAMES, dbus.FlagNoAutoStart)
+
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	return call.Body[0].([]string), nil
+}
+
+// @title         PermanentGetIPSetSettings
+// @description   Return permanent settings of the ipset.
+// @auth      	  author           2021-10-11
+// @param         ipset            string         "ipset name."
+// @return        settings         *IPSetSettings "type, options and entries of the ipset."
+// @return        error            error          "Possible errors: INVALID_IPSET"
+func (c *DbusClientSerivce) PermanentGetIPSetSettings(ipset string) (settings *IPSetSettings, err error) {
+	c, end := c.span("PermanentGetIPSetSettings", SCOPE_PERMANENT)
+	defer end(&err)
+	var path dbus.ObjectPath
+	if path, err = c.ipsetPath(ipset); err != nil {
+		return nil, err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_IPSET_GETSETTINGS, dbus.FlagNoAutoStart)
+
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	var raw ipsetSettings
+	if err = call.Store(&raw); err != nil {
+		return nil, err
+	}
+	return raw.toIPSetSettings(), nil
+}
+
+// @title         PermanentAddIPSet
+// @description   Add ipset with given type into permanent configuration, it is available in runtime after Reload.
+// @auth      	  author           2021-10-11
+// @param         ipset            string         "ipset name."
+// @param         ipsetType        string         "e.g. hash:ip, hash:net, hash:mac"
+// @return        error            error          "Possible errors: NAME_CONFLICT, INVALID_NAME, INVALID_TYPE"
+func (c *DbusClientSerivce) PermanentAddIPSet(ipset, ipsetType string) (err error) {
+	c, end := c.span("PermanentAddIPSet", SCOPE_PERMANENT)
+	defer end(&err)
+	return c.permanentAddIPSet(ipset, &IPSetSettings{Short: ipset, Type: ipsetType})
+}
+
+func (c *DbusClientSerivce) permanentAddIPSet(ipset string, settings *IPSetSettings) (err error) {
+	obj := c.object(object.CONFIG_PATH)
+	call := obj.Call(object.CONFIG_ADDIPSET, dbus.FlagNoAutoStart, ipset, settings.toTuple())
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+// @title         PermanentSetIPSetSettings
+// @description   Replace the permanent settings of the ipset, including its entries, in a single update.
+// @auth      	  author           2021-10-15
+// @param         ipset            string         "ipset name."
+// @param         settings         *IPSetSettings "the complete settings, e.g. returned by PermanentGetIPSetSettings and modified."
+// @return        error            error          "Possible errors: INVALID_IPSET, INVALID_TYPE, INVALID_ENTRY"
+func (c *DbusClientSerivce) PermanentSetIPSetSettings(ipset string, settings *IPSetSettings) (err error) {
+	c, end := c.span("PermanentSetIPSetSettings", SCOPE_PERMANENT)
+	defer end(&err)
+	var path dbus.ObjectPath
+	if path, err = c.ipsetPath(ipset); err != nil {
+		return err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_IPSET_UPDATE, dbus.FlagNoAutoStart, settings.toTuple())
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+// @title         PermanentRemoveIPSet
+// @description   Remove ipset from permanent configuration.
+// @auth      	  author           2021-10-11
+// @param         ipset            string         "ipset name."
+// @return        error            error          "Possible errors: INVALID_IPSET, BUILTIN_IPSET"
+func (c *DbusClientSerivce) PermanentRemoveIPSet(ipset string) (err error) {
+	c, end := c.span("PermanentRemoveIPSet", SCOPE_PERMANENT)
+	defer end(&err)
+	var path dbus.ObjectPath
+	if path, err = c.ipsetPath(ipset); err != nil {
+		return err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_IPSET_REMOVE, dbus.FlagNoAutoStart)
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+// @title         PermanentGetIPSetEntries
+// @description   Return list of entries of the permanent ipset.
+// @auth      	  author           2021-10-11
+// @param         ipset            string         "ipset name."
+// @return        entries          []string       "e.g. 10.0.0.1, 10.1.0.0/16"
+// @return        error            error          "Possible errors: INVALID_IPSET"
+func (c *DbusClientSerivce) PermanentGetIPSetEntries(ipset string) (entries []string, err error) {
+	c, end := c.span("PermanentGetIPSetEntries", SCOPE_PERMANENT)
+	defer end(&err)
+	var path dbus.ObjectPath
+	if path, err = c.ipsetPath(ipset); err != nil {
+		return nil, err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_IPSET_GETENTRIES, dbus.FlagNoAutoStart)
+
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	return call.Body[0].([]string), nil
+}
+
+// @title         PermanentAddIPSetEntry
+// @description   Permanently add a new entry to the ipset.
+// @auth      	  author           2021-10-11
+// @param         ipset            string         "ipset name."
+// @param         entry            string         "e.g. 10.0.0.1, 10.1.0.0/16"
+// @return        error            error          "Possible errors: INVALID_IPSET, ALREADY_ENABLED"
+func (c *DbusClientSerivce) PermanentAddIPSetEntry(ipset, entry string) (err error) {
+	c, end := c.span("PermanentAddIPSetEntry", SCOPE_PERMANENT)
+	defer end(&err)
+	var path dbus.ObjectPath
+	if path, err = c.ipsetPath(ipset); err != nil {
+		return err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_IPSET_ADDENTRY, dbus.FlagNoAutoStart, entry)
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+// @title         PermanentRemoveIPSetEntry
+// @description   Permanently remove an entry from the ipset.
+// @auth      	  author           2021-10-11
+// @param         ipset            string         "ipset name."
+// @param         entry            string         "e.g. 10.0.0.1, 10.1.0.0/16"
+// @return        error            error          "Possible errors: INVALID_IPSET, NOT_ENABLED"
+func (c *DbusClientSerivce) PermanentRemoveIPSetEntry(ipset, entry string) (err error) {
+	c, end := c.span("PermanentRemoveIPSetEntry", SCOPE_PERMANENT)
+	defer end(&err)
+	var path dbus.ObjectPath
+	if path, err = c.ipsetPath(ipset); err != nil {
+		return err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_IPSET_REMOVEENTRY, dbus.FlagNoAutoStart, entry)
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+// @title         PermanentQueryIPSetEntry
+// @description   Permanently return whether the entry has been added to the ipset.
+// @auth      	  author           2021-10-11
+// @param         ipset            string         "ipset name."
+// @param         entry            string         "e.g. 10.0.0.1, 10.1.0.0/16"
+// @return        b                bool           "true:enable, fales:disable."
+// @return        error            error          "Possible errors: INVALID_IPSET"
+func (c *DbusClientSerivce) PermanentQueryIPSetEntry(ipset, entry string) (b bool, err error) {
+	c, end := c.span("PermanentQueryIPSetEntry", SCOPE_PERMANENT)
+	defer end(&err)
+	var path dbus.ObjectPath
+	if path, err = c.ipsetPath(ipset); err != nil {
+		return false, err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_IPSET_QUERYENTRY, dbus.FlagNoAutoStart, entry)
+
+	if len(call.Body) <= 0 || !call.Body[0].(bool) {
+		return false, call.Err
+	}
+	return true, nil
+}
+
+// ipsetPath returns the object path of the permanent ipset.
+func (c *DbusClientSerivce) ipsetPath(ipset string) (path dbus.ObjectPath, err error) {
+	c, end := c.span("ipsetPath", 0)
+	defer end(&err)
+	obj := c.object(object.CONFIG_PATH)
+	call := obj.Call(object.CONFIG_GETIPSETBYNAME, dbus.FlagNoAutoStart, ipset)
+
+	if call.Err != nil {
+		return "", call.Err
+	}
+	if len(call.Body) <= 0 {
+		return "", errors.New("invalid ipset.")
+	}
+	return call.Body[0].(dbus.ObjectPath), nil
+}
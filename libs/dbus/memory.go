@@ -0,0 +1,960 @@
+package dbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cylonchau/gofirewallder/object"
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+ * MemoryFirewall is a Firewall kept in memory for unit tests of code using Firewall, e.g.
+ *
+ *   fw := NewMemoryFirewall()
+ *   fw.AddPort("8080/tcp", "public", 30)
+ *   fw.Now = func() time.Time { return time.Now().Add(time.Minute) }  // the port has expired
+ *
+ * It starts with the predefined zones and common services of firewalld, public is the default zone.
+ * Runtime and permanent configuration are separate, Reload copies permanent to runtime.
+ * Errors are dbus.Error of org.fedoraproject.FirewallD1.Exception with the firewalld code, e.g. "ALREADY_ENABLED: ...".
+ *   Now  the clock of timeouts, items added with a timeout are removed once it has passed.
+ */
+type MemoryFirewall struct {
+	Now func() time.Time
+
+	lock        sync.Mutex
+	defaultZone string
+	runtime     *memoryConfig
+	permanent   *memoryConfig
+	defaults    *memoryConfig
+	expiries    []memoryExpiry
+	events      []Event
+
+	sendLock    sync.Mutex
+	subscribers []*memorySubscriber
+}
+
+type memoryConfig struct {
+	Zones    map[string]*Settings        `json:"zones"`
+	IPSets   map[string]*IPSetSettings   `json:"ipsets"`
+	Services map[string]*ServiceSettings `json:"services"`
+	Policies map[string]*PolicySettings  `json:"policies"`
+	Direct   *DirectSettings             `json:"direct"`
+}
+
+// memoryExpiry is the removal of a runtime item added with a timeout.
+type memoryExpiry struct {
+	at time.Time
+	op Operation
+}
+
+// memorySubscriber is a reader of Events, overflow is set once an event was dropped because its buffer was full.
+type memorySubscriber struct {
+	ctx      context.Context
+	events   chan Event
+	overflow bool
+}
+
+// NewMemoryFirewall returns a firewall with the predefined zones and services of firewalld.
+func NewMemoryFirewall() *MemoryFirewall {
+	m := &MemoryFirewall{
+		Now:         time.Now,
+		defaultZone: "public",
+		permanent: &memoryConfig{
+			Zones:    map[string]*Settings{},
+			IPSets:   map[string]*IPSetSettings{},
+			Services: map[string]*ServiceSettings{},
+			Policies: map[string]*PolicySettings{},
+			Direct:   &DirectSettings{},
+		},
+	}
+	zones := []*Settings{
+		{Short: "Block", Targe: "%%REJECT%%"},
+		{Short: "Drop", Targe: "DROP"},
+		{Short: "Trusted", Targe: "ACCEPT"},
+		{Short: "Public", Service: []string{"ssh", "dhcpv6-client"}},
+		{Short: "External", Service: []string{"ssh"}, Masquerade: true},
+		{Short: "DMZ", Service: []string{"ssh"}},
+		{Short: "Work", Service: []string{"ssh", "dhcpv6-client"}},
+		{Short: "Home", Service: []string{"ssh", "mdns", "samba-client", "dhcpv6-client"}},
+		{Short: "Internal", Service: []string{"ssh", "mdns", "samba-client", "dhcpv6-client"}},
+	}
+	for _, settings := range zones {
+		if settings.Targe == "" {
+			settings.Targe = "default"
+		}
+		name := strings.ToLower(settings.Short)
+		m.permanent.Zones[name] = settings
+	}
+	services := map[string][]Port{
+		"ssh":           {{Port: "22", Protocol: "tcp"}},
+		"http":          {{Port: "80", Protocol: "tcp"}},
+		"https":         {{Port: "443", Protocol: "tcp"}},
+		"dns":           {{Port: "53", Protocol: "tcp"}, {Port: "53", Protocol: "udp"}},
+		"dhcpv6-client": {{Port: "546", Protocol: "udp"}},
+		"mdns":          {{Port: "5353", Protocol: "udp"}},
+		"samba-client":  {{Port: "137", Protocol: "udp"}, {Port: "138", Protocol: "udp"}},
+		"cockpit":       {{Port: "9090", Protocol: "tcp"}},
+		"mysql":         {{Port: "3306", Protocol: "tcp"}},
+		"postgresql":    {{Port: "5432", Protocol: "tcp"}},
+	}
+	for name, ports := range services {
+		m.permanent.Services[name] = &ServiceSettings{Short: strings.ToUpper(name), Port: ports}
+	}
+	m.runtime = m.permanent.copy()
+	// the predefined objects are builtin, see PermanentGetBuiltin.
+	m.defaults = m.permanent.copy()
+	return m
+}
+
+// memoryError returns the error firewalld reports, e.g. ALREADY_ENABLED: 80/tcp in public
+func memoryError(code, format string, args ...interface{}) error {
+	return dbus.Error{Name: object.EXCEPTION, Body: []interface{}{code + ": " + fmt.Sprintf(format, args...)}}
+}
+
+// copyJSON deep copies src into dest, the types are plain settings.
+func copyJSON(src, dest interface{}) {
+	data, _ := json.Marshal(src)
+	json.Unmarshal(data, dest)
+}
+
+func (config *memoryConfig) copy() *memoryConfig {
+	copied := &memoryConfig{}
+	copyJSON(config, copied)
+	return copied
+}
+
+func (m *MemoryFirewall) config(permanent bool) *memoryConfig {
+	if permanent {
+		return m.permanent
+	}
+	return m.runtime
+}
+
+// begin locks m and removes the expired runtime items, end unlocks it and sends the events of the call.
+func (m *MemoryFirewall) begin() {
+	m.lock.Lock()
+	now := m.Now()
+	var expiries []memoryExpiry
+	for _, expiry := range m.expiries {
+		if now.Before(expiry.at) {
+			expiries = append(expiries, expiry)
+			continue
+		}
+		m.changeZone(expiry.op)
+	}
+	m.expiries = expiries
+}
+
+// dropExpiry forgets the pending removal of the runtime item value of kind in zone, the lock is held by the caller.
+func (m *MemoryFirewall) dropExpiry(kind, zone, value string) {
+	var expiries []memoryExpiry
+	for _, expiry := range m.expiries {
+		if expiry.op.Kind != kind || expiry.op.Zone != zone || expiry.op.Value != value {
+			expiries = append(expiries, expiry)
+		}
+	}
+	m.expiries = expiries
+}
+
+func (m *MemoryFirewall) end() {
+	events := m.events
+	m.events = nil
+	m.lock.Unlock()
+
+	m.sendLock.Lock()
+	defer m.sendLock.Unlock()
+	for _, event := range events {
+		for _, subscriber := range m.subscribers {
+			subscriber.send(event)
+		}
+	}
+}
+
+// send never blocks, a full buffer drops event and the reader gets EVENT_OVERFLOW once there is room again.
+func (s *memorySubscriber) send(event Event) {
+	if s.ctx.Err() != nil {
+		return
+	}
+	if s.overflow {
+		select {
+		case s.events <- Event{Name: EVENT_OVERFLOW, Interface: object.INTERFACE}:
+			s.overflow = false
+		default:
+			return
+		}
+	}
+	select {
+	case s.events <- event:
+	default:
+		s.overflow = true
+	}
+}
+
+func (m *MemoryFirewall) emit(event Event) {
+	m.events = append(m.events, event)
+}
+
+// emitUpdated records the config signal of a permanent object, e.g. Updated of config.zone.
+func (m *MemoryFirewall) emitUpdated(iface, name, signal string) {
+	m.emit(Event{Name: signal, Interface: iface, Item: name, Zone: zoneOfConfig(iface, name), Permanent: true})
+}
+
+func zoneOfConfig(iface, name string) string {
+	if iface == object.CONFIG_ZONE {
+		return name
+	}
+	return ""
+}
+
+// Close stops nothing, the firewall stays usable.
+func (m *MemoryFirewall) Close() error {
+	return nil
+}
+
+// Events returns the signals firewalld would emit for the changes made through m, a reader falling behind by
+// DEFAULT_EVENTS_BUFFER events misses the following ones and receives EVENT_OVERFLOW instead.
+func (m *MemoryFirewall) Events(ctx context.Context) (<-chan Event, error) {
+	subscriber := &memorySubscriber{ctx: ctx, events: make(chan Event, DEFAULT_EVENTS_BUFFER)}
+	m.sendLock.Lock()
+	m.subscribers = append(m.subscribers, subscriber)
+	m.sendLock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		m.sendLock.Lock()
+		defer m.sendLock.Unlock()
+		for index, value := range m.subscribers {
+			if value == subscriber {
+				m.subscribers = append(m.subscribers[:index], m.subscribers[index+1:]...)
+				break
+			}
+		}
+		close(subscriber.events)
+	}()
+	return subscriber.events, nil
+}
+
+func (m *MemoryFirewall) Execute(op Operation) error {
+	return execute(m, op)
+}
+
+func (m *MemoryFirewall) NewTx(ops ...Operation) *Tx {
+	return &Tx{Operations: ops, client: m}
+}
+
+func (m *MemoryFirewall) Zone(zone string) *Zone {
+	return &Zone{Name: zone, client: m}
+}
+
+/************************************************** zone area ***********************************************************/
+
+func (m *MemoryFirewall) GetDefaultZone() string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.defaultZone
+}
+
+func (m *MemoryFirewall) SetDefaultZone(zone string) error {
+	m.begin()
+	defer m.end()
+	if _, ok := m.runtime.Zones[zone]; !ok {
+		return memoryError("INVALID_ZONE", "%s", zone)
+	}
+	if zone == m.defaultZone {
+		return memoryError("ZONE_ALREADY_SET", "%s", zone)
+	}
+	m.defaultZone = zone
+	m.emit(Event{Name: EVENT_DEFAULTZONECHANGED, Interface: object.INTERFACE, Zone: zone})
+	return nil
+}
+
+func (m *MemoryFirewall) GetZones() ([]string, error) {
+	m.begin()
+	defer m.end()
+	return zoneNames(m.runtime.Zones), nil
+}
+
+func (m *MemoryFirewall) PermanentGetZones() ([]string, error) {
+	m.begin()
+	defer m.end()
+	return zoneNames(m.permanent.Zones), nil
+}
+
+func (m *MemoryFirewall) GetZoneSettings(zone string) (*Settings, error) {
+	return m.getZoneSettings(zone, false)
+}
+
+func (m *MemoryFirewall) PermanentGetZoneSettings(zone string) (*Settings, error) {
+	return m.getZoneSettings(zone, true)
+}
+
+func (m *MemoryFirewall) getZoneSettings(zone string, permanent bool) (*Settings, error) {
+	m.begin()
+	defer m.end()
+	settings, zone, err := m.zone(zone, permanent)
+	if err != nil {
+		return nil, err
+	}
+	copied := &Settings{}
+	copyJSON(settings, copied)
+	return copied, nil
+}
+
+// zone returns the settings of zone, the empty string is usage default zone.
+func (m *MemoryFirewall) zone(zone string, permanent bool) (*Settings, string, error) {
+	if zone == "" {
+		zone = m.defaultZone
+	}
+	settings, ok := m.config(permanent).Zones[zone]
+	if !ok {
+		return nil, zone, memoryError("INVALID_ZONE", "%s", zone)
+	}
+	return settings, zone, nil
+}
+
+func (m *MemoryFirewall) GetZoneOfInterface(iface string) string {
+	m.begin()
+	defer m.end()
+	return m.zoneOf(m.runtime, KIND_INTERFACE, iface)
+}
+
+// zoneOf returns the zone of config holding the interface or source value, or the empty string.
+func (m *MemoryFirewall) zoneOf(config *memoryConfig, kind, value string) string {
+	for _, name := range zoneNames(config.Zones) {
+		state := zoneStateOf(config.Zones[name])
+		if (kind == KIND_INTERFACE && contains(state.Interfaces, value)) || (kind == KIND_SOURCE && contains(state.Sources, value)) {
+			return name
+		}
+	}
+	return ""
+}
+
+func (m *MemoryFirewall) AddZone(name string) error {
+	m.begin()
+	defer m.end()
+	if len(name) > 17 {
+		return memoryError("INVALID_NAME", "zone name %s is limited to 17 chars", name)
+	}
+	if _, ok := m.permanent.Zones[name]; ok {
+		return memoryError("NAME_CONFLICT", "zone %s already exists", name)
+	}
+	m.permanent.Zones[name] = &Settings{Short: name, Targe: "default"}
+	m.emit(Event{Name: "ZoneAdded", Interface: object.CONFIG_INTERFACE, Item: name, Permanent: true})
+	return nil
+}
+
+func (m *MemoryFirewall) PermanentSetZoneSettings(zone string, settings *Settings) error {
+	m.begin()
+	defer m.end()
+	_, zone, err := m.zone(zone, true)
+	if err != nil {
+		return err
+	}
+	if err = checkTarget(settings.Targe); err != nil {
+		return err
+	}
+	copied := &Settings{}
+	copyJSON(settings, copied)
+	if copied.Targe == "" {
+		copied.Targe = "default"
+	}
+	m.permanent.Zones[zone] = copied
+	m.emitUpdated(object.CONFIG_ZONE, zone, EVENT_UPDATED)
+	return nil
+}
+
+func (m *MemoryFirewall) PermanentRemoveZone(zone string) error {
+	m.begin()
+	defer m.end()
+	if _, ok := m.permanent.Zones[zone]; !ok {
+		return memoryError("INVALID_ZONE", "%s", zone)
+	}
+	if _, ok := m.defaults.Zones[zone]; ok {
+		return memoryError("BUILTIN_ZONE", "%s", zone)
+	}
+	delete(m.permanent.Zones, zone)
+	m.emitUpdated(object.CONFIG_ZONE, zone, EVENT_REMOVED)
+	return nil
+}
+
+func (m *MemoryFirewall) PermanentSetZoneTarget(zone, target string) error {
+	m.begin()
+	defer m.end()
+	settings, zone, err := m.zone(zone, true)
+	if err != nil {
+		return err
+	}
+	if err = checkTarget(target); err != nil {
+		return err
+	}
+	settings.Targe = target
+	m.emitUpdated(object.CONFIG_ZONE, zone, EVENT_UPDATED)
+	return nil
+}
+
+func checkTarget(target string) error {
+	switch target {
+	case "", "default", "ACCEPT", "REJECT", "%%REJECT%%", "DROP":
+		return nil
+	}
+	return memoryError("INVALID_TARGET", "%s", target)
+}
+
+/************************************************** zone item area ***********************************************************/
+
+// changeZone adds or removes an item of a zone as op describes, it returns the name of the zone.
+func (m *MemoryFirewall) changeZone(op Operation) (zone string, err error) {
+	config := m.config(op.Permanent)
+	var settings *Settings
+	if settings, zone, err = m.zone(op.Zone, op.Permanent); err != nil {
+		return zone, err
+	}
+	add := op.Action == OP_ADD
+
+	value, err := op.normalizedValue()
+	if err != nil {
+		return zone, memoryError("INVALID_FORWARD", "%s", op.Value)
+	}
+	present := memoryHas(settings, op.Kind, value)
+	switch {
+	case add && present:
+		return zone, memoryError("ALREADY_ENABLED", "%s in %s", memoryItem(op.Kind, value), zone)
+	case !add && !present:
+		return zone, memoryError("NOT_ENABLED", "%s not in %s", memoryItem(op.Kind, value), zone)
+	}
+
+	if add {
+		switch op.Kind {
+		case KIND_PORT:
+			if checkPort(value) != nil {
+				return zone, memoryError("INVALID_PORT", "%s", value)
+			}
+		case KIND_SERVICE:
+			if _, ok := config.Services[value]; !ok {
+				return zone, memoryError("INVALID_SERVICE", "%s", value)
+			}
+		case KIND_INTERFACE, KIND_SOURCE:
+			if other := m.zoneOf(config, op.Kind, value); other != "" {
+				return zone, memoryError("ZONE_CONFLICT", "%s is already bound to zone %s", value, other)
+			}
+			if ipset := strings.TrimPrefix(value, "ipset:"); op.Kind == KIND_SOURCE && ipset != value {
+				if _, ok := config.IPSets[ipset]; !ok {
+					return zone, memoryError("INVALID_IPSET", "%s", ipset)
+				}
+			} else if op.Kind == KIND_SOURCE {
+				if _, _, err := net.ParseCIDR(value); err != nil && net.ParseIP(value) == nil {
+					if _, err := net.ParseMAC(value); err != nil {
+						return zone, memoryError("INVALID_ADDR", "%s", value)
+					}
+				}
+			}
+		}
+	}
+
+	switch op.Kind {
+	case KIND_PORT:
+		port, protocol := splitPortProtocol(value)
+		settings.Port = removePort(settings.Port, Port{Port: port, Protocol: protocol})
+		if add {
+			settings.Port = append(settings.Port, Port{Port: port, Protocol: protocol})
+		}
+	case KIND_SERVICE:
+		settings.Service = memoryToggle(settings.Service, value, add)
+	case KIND_SOURCE:
+		var sources []Source
+		for _, source := range settings.Source {
+			if sourceString(source) != value {
+				sources = append(sources, source)
+			}
+		}
+		if add {
+			sources = append(sources, stringToSource(value))
+		}
+		settings.Source = sources
+	case KIND_INTERFACE:
+		var interfaces []Interface
+		for _, iface := range settings.Interface {
+			if iface.Name != value {
+				interfaces = append(interfaces, iface)
+			}
+		}
+		if add {
+			interfaces = append(interfaces, Interface{Name: value})
+		}
+		settings.Interface = interfaces
+	case KIND_FORWARDPORT:
+		forward, _ := StringToForwardPort(value)
+		var forwards []ForwardPort
+		for _, item := range settings.ForwardPort {
+			if item != forward {
+				forwards = append(forwards, item)
+			}
+		}
+		if add {
+			forwards = append(forwards, forward)
+		}
+		settings.ForwardPort = forwards
+	case KIND_MASQUERADE:
+		settings.Masquerade = add
+	case KIND_RICHRULE:
+		var rules []Rule
+		for index := range settings.Rule {
+			if settings.Rule[index].ToString() != value {
+				rules = append(rules, settings.Rule[index])
+			}
+		}
+		if add {
+			rules = append(rules, *StringToRule(value))
+		}
+		settings.Rule = rules
+	case KIND_PROTOCOL:
+		var protocols []Protocol
+		for _, protocol := range settings.Protocol {
+			if protocol.Value != value {
+				protocols = append(protocols, protocol)
+			}
+		}
+		if add {
+			protocols = append(protocols, Protocol{Value: value})
+		}
+		settings.Protocol = protocols
+	default:
+		return zone, memoryError("INVALID_COMMAND", "unknown kind '%s'", op.Kind)
+	}
+
+	if op.Permanent {
+		m.emitUpdated(object.CONFIG_ZONE, zone, EVENT_UPDATED)
+		return zone, nil
+	}
+	// the timeout of an earlier add ends with the item, a re-added item gets the timeout of the new add.
+	m.dropExpiry(op.Kind, zone, value)
+	timeout := 0
+	if add && op.Timeout > 0 {
+		timeout = op.Timeout
+		removal := op
+		removal.Action, removal.Zone, removal.Value, removal.Timeout = OP_REMOVE, zone, value, 0
+		m.expiries = append(m.expiries, memoryExpiry{at: m.Now().Add(time.Duration(op.Timeout) * time.Second), op: removal})
+	}
+	suffix := "Removed"
+	if add {
+		suffix = "Added"
+	}
+	m.emit(Event{Name: memorySignals[op.Kind] + suffix, Interface: object.ZONE, Zone: zone, Item: value, Timeout: timeout})
+	return zone, nil
+}
+
+// KIND_PROTOCOL is the zone item of AddProtocol, it has no Operation of Execute.
+const KIND_PROTOCOL = "protocol"
+
+var memorySignals = map[string]string{
+	KIND_PORT:        "Port",
+	KIND_SERVICE:     "Service",
+	KIND_SOURCE:      "Source",
+	KIND_INTERFACE:   "Interface",
+	KIND_FORWARDPORT: "ForwardPort",
+	KIND_MASQUERADE:  "Masquerade",
+	KIND_RICHRULE:    "RichRule",
+	KIND_PROTOCOL:    "Protocol",
+}
+
+func memoryHas(settings *Settings, kind, value string) bool {
+	state := zoneStateOf(settings)
+	switch kind {
+	case KIND_PORT:
+		return contains(state.Ports, value)
+	case KIND_SERVICE:
+		return contains(state.Services, value)
+	case KIND_SOURCE:
+		return contains(state.Sources, value)
+	case KIND_INTERFACE:
+		return contains(state.Interfaces, value)
+	case KIND_FORWARDPORT:
+		return contains(state.ForwardPorts, value)
+	case KIND_MASQUERADE:
+		return settings.Masquerade
+	case KIND_RICHRULE:
+		return contains(state.RichRules, value)
+	case KIND_PROTOCOL:
+		for _, protocol := range settings.Protocol {
+			if protocol.Value == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func memoryItem(kind, value string) string {
+	if kind == KIND_MASQUERADE {
+		return "masquerade"
+	}
+	return value
+}
+
+func memoryToggle(list []string, value string, add bool) (toggled []string) {
+	for _, item := range list {
+		if item != value {
+			toggled = append(toggled, item)
+		}
+	}
+	if add {
+		toggled = append(toggled, value)
+	}
+	return toggled
+}
+
+// zoneItem runs changeZone for a method of the Firewall API.
+func (m *MemoryFirewall) zoneItem(action, kind, zone, value string, timeout int, permanent bool) (string, error) {
+	m.begin()
+	defer m.end()
+	return m.changeZone(Operation{Action: action, Kind: kind, Zone: zone, Value: value, Timeout: timeout, Permanent: permanent})
+}
+
+// queryItem reports whether zone holds the item.
+func (m *MemoryFirewall) queryItem(kind, zone, value string, permanent bool) (bool, error) {
+	m.begin()
+	defer m.end()
+	settings, _, err := m.zone(zone, permanent)
+	if err != nil {
+		return false, err
+	}
+	if value, err = (Operation{Kind: kind, Value: value}).normalizedValue(); err != nil {
+		return false, memoryError("INVALID_FORWARD", "%s", value)
+	}
+	return memoryHas(settings, kind, value), nil
+}
+
+func (m *MemoryFirewall) AddPort(port, zone string, timeout int) (string, error) {
+	return m.zoneItem(OP_ADD, KIND_PORT, zone, port, timeout, false)
+}
+
+func (m *MemoryFirewall) PermanentAddPort(port, zone string) error {
+	_, err := m.zoneItem(OP_ADD, KIND_PORT, zone, port, 0, true)
+	return err
+}
+
+func (m *MemoryFirewall) GetPort(zone string) ([]*Port, error) {
+	return m.getPort(zone, false)
+}
+
+func (m *MemoryFirewall) PermanentGetPort(zone string) ([]*Port, error) {
+	return m.getPort(zone, true)
+}
+
+func (m *MemoryFirewall) getPort(zone string, permanent bool) (list []*Port, err error) {
+	m.begin()
+	defer m.end()
+	settings, _, err := m.zone(zone, permanent)
+	if err != nil {
+		return nil, err
+	}
+	for _, port := range settings.Port {
+		list = append(list, &Port{Port: port.Port, Protocol: port.Protocol})
+	}
+	return list, nil
+}
+
+func (m *MemoryFirewall) RemovePort(port, zone string) (bool, error) {
+	if _, err := m.zoneItem(OP_REMOVE, KIND_PORT, zone, port, 0, false); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *MemoryFirewall) PermanentRemovePort(port, zone string) (bool, error) {
+	if _, err := m.zoneItem(OP_REMOVE, KIND_PORT, zone, port, 0, true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *MemoryFirewall) PermanentAddPorts(zone string, ports []Port) ([]Conflict, error) {
+	return permanentAddPorts(m, zone, ports)
+}
+
+func (m *MemoryFirewall) PermanentRemovePorts(zone string, ports []Port) ([]Conflict, error) {
+	return permanentRemovePorts(m, zone, ports)
+}
+
+func (m *MemoryFirewall) AddProtocol(zone, protocol string, timeout int) (string, error) {
+	return m.zoneItem(OP_ADD, KIND_PROTOCOL, zone, protocol, timeout, false)
+}
+
+func (m *MemoryFirewall) AddService(zone, service string, timeout int) (string, error) {
+	return m.zoneItem(OP_ADD, KIND_SERVICE, zone, service, timeout, false)
+}
+
+func (m *MemoryFirewall) PermanentAddService(zone, service string) error {
+	_, err := m.zoneItem(OP_ADD, KIND_SERVICE, zone, service, 0, true)
+	return err
+}
+
+func (m *MemoryFirewall) QueryService(zone, service string) bool {
+	b, _ := m.queryItem(KIND_SERVICE, zone, service, false)
+	return b
+}
+
+func (m *MemoryFirewall) PermanentQueryService(zone, service string) bool {
+	b, _ := m.queryItem(KIND_SERVICE, zone, service, true)
+	return b
+}
+
+func (m *MemoryFirewall) RemoveService(zone, service string) error {
+	_, err := m.zoneItem(OP_REMOVE, KIND_SERVICE, zone, service, 0, false)
+	return err
+}
+
+func (m *MemoryFirewall) PermanentRemoveService(zone, service string) error {
+	_, err := m.zoneItem(OP_REMOVE, KIND_SERVICE, zone, service, 0, true)
+	return err
+}
+
+func (m *MemoryFirewall) PermanentSetServices(zone string, services []string) ([]Conflict, error) {
+	return permanentSetServices(m, zone, services)
+}
+
+func (m *MemoryFirewall) EnableMasquerade(zone string, timeout int) error {
+	_, err := m.zoneItem(OP_ADD, KIND_MASQUERADE, zone, "", timeout, false)
+	return err
+}
+
+func (m *MemoryFirewall) PermanentEnableMasquerade(zone string) error {
+	_, err := m.zoneItem(OP_ADD, KIND_MASQUERADE, zone, "", 0, true)
+	return err
+}
+
+func (m *MemoryFirewall) DisableMasquerade(zone string) error {
+	_, err := m.zoneItem(OP_REMOVE, KIND_MASQUERADE, zone, "", 0, false)
+	return err
+}
+
+func (m *MemoryFirewall) PermanentDisableMasquerade(zone string) error {
+	_, err := m.zoneItem(OP_REMOVE, KIND_MASQUERADE, zone, "", 0, true)
+	return err
+}
+
+func (m *MemoryFirewall) QueryMasquerade(zone string) (bool, error) {
+	return m.queryItem(KIND_MASQUERADE, zone, "", false)
+}
+
+func (m *MemoryFirewall) PermanentQueryMasquerade(zone string) (bool, error) {
+	return m.queryItem(KIND_MASQUERADE, zone, "", true)
+}
+
+func (m *MemoryFirewall) BindInterface(zone, iface string) (string, error) {
+	return m.zoneItem(OP_ADD, KIND_INTERFACE, zone, iface, 0, false)
+}
+
+func (m *MemoryFirewall) PermanentBindInterface(zone, iface string) error {
+	_, err := m.zoneItem(OP_ADD, KIND_INTERFACE, zone, iface, 0, true)
+	return err
+}
+
+func (m *MemoryFirewall) QueryInterface(zone, iface string) (bool, error) {
+	return m.queryItem(KIND_INTERFACE, zone, iface, false)
+}
+
+// PermanentQueryInterface returns nil if iface is bound to zone, otherwise NOT_ENABLED.
+func (m *MemoryFirewall) PermanentQueryInterface(zone, iface string) error {
+	b, err := m.queryItem(KIND_INTERFACE, zone, iface, true)
+	if err == nil && !b {
+		err = memoryError("NOT_ENABLED", "%s not in %s", iface, zone)
+	}
+	return err
+}
+
+func (m *MemoryFirewall) RemoveInterface(zone, iface string) error {
+	_, err := m.zoneItem(OP_REMOVE, KIND_INTERFACE, zone, iface, 0, false)
+	return err
+}
+
+func (m *MemoryFirewall) PermanentRemoveInterface(zone, iface string) error {
+	_, err := m.zoneItem(OP_REMOVE, KIND_INTERFACE, zone, iface, 0, true)
+	return err
+}
+
+func (m *MemoryFirewall) AddSource(zone, source string) (string, error) {
+	return m.zoneItem(OP_ADD, KIND_SOURCE, zone, source, 0, false)
+}
+
+func (m *MemoryFirewall) PermanentAddSource(zone, source string) error {
+	_, err := m.zoneItem(OP_ADD, KIND_SOURCE, zone, source, 0, true)
+	return err
+}
+
+func (m *MemoryFirewall) QuerySource(zone, source string) (bool, error) {
+	return m.queryItem(KIND_SOURCE, zone, source, false)
+}
+
+func (m *MemoryFirewall) PermanentQuerySource(zone, source string) (bool, error) {
+	return m.queryItem(KIND_SOURCE, zone, source, true)
+}
+
+func (m *MemoryFirewall) RemoveSource(zone, source string) error {
+	_, err := m.zoneItem(OP_REMOVE, KIND_SOURCE, zone, source, 0, false)
+	return err
+}
+
+func (m *MemoryFirewall) PermanentRemoveSource(zone, source string) error {
+	_, err := m.zoneItem(OP_REMOVE, KIND_SOURCE, zone, source, 0, true)
+	return err
+}
+
+func (m *MemoryFirewall) AddForwardPort(zone string, portProtocol, toHostPort string, timeout int) error {
+	return m.forwardPort(OP_ADD, zone, portProtocol, toHostPort, timeout, false)
+}
+
+func (m *MemoryFirewall) PermanentAddForwardPort(zone string, portProtocol, toHostPort string) error {
+	return m.forwardPort(OP_ADD, zone, portProtocol, toHostPort, 0, true)
+}
+
+func (m *MemoryFirewall) RemoveForwardPort(zone string, portProtocol, toHostPort string) error {
+	return m.forwardPort(OP_REMOVE, zone, portProtocol, toHostPort, 0, false)
+}
+
+func (m *MemoryFirewall) PermanentRemoveForwardPort(zone string, portProtocol, toHostPort string) error {
+	return m.forwardPort(OP_REMOVE, zone, portProtocol, toHostPort, 0, true)
+}
+
+func (m *MemoryFirewall) QueryForwardPort(zone string, portProtocol, toHostPort string) bool {
+	if _, _, err := net.SplitHostPort(toHostPort); err != nil {
+		return false
+	}
+	op := forwardPortOperation("", zone, portProtocol, toHostPort, false)
+	b, _ := m.queryItem(KIND_FORWARDPORT, zone, op.Value, false)
+	return b
+}
+
+func (m *MemoryFirewall) PermanentQueryForwardPort(zone string, portProtocol, toHostPort string) (bool, error) {
+	if _, _, err := net.SplitHostPort(toHostPort); err != nil {
+		return false, err
+	}
+	op := forwardPortOperation("", zone, portProtocol, toHostPort, true)
+	return m.queryItem(KIND_FORWARDPORT, zone, op.Value, true)
+}
+
+func (m *MemoryFirewall) forwardPort(action, zone, portProtocol, toHostPort string, timeout int, permanent bool) error {
+	if _, _, err := net.SplitHostPort(toHostPort); err != nil {
+		return memoryError("INVALID_FORWARD", "%s", toHostPort)
+	}
+	op := forwardPortOperation(action, zone, portProtocol, toHostPort, permanent)
+	_, err := m.zoneItem(action, KIND_FORWARDPORT, zone, op.Value, timeout, permanent)
+	return err
+}
+
+func (m *MemoryFirewall) GetRichRules(zone string) (list []*Rule, err error) {
+	m.begin()
+	defer m.end()
+	settings, _, err := m.zone(zone, false)
+	if err != nil {
+		return nil, err
+	}
+	for index := range settings.Rule {
+		list = append(list, StringToRule(settings.Rule[index].ToString()))
+	}
+	return list, nil
+}
+
+func (m *MemoryFirewall) AddRichRule(zone string, rule *Rule, timeout int) error {
+	_, err := m.zoneItem(OP_ADD, KIND_RICHRULE, zone, rule.ToString(), timeout, false)
+	return err
+}
+
+func (m *MemoryFirewall) PermanentAddRichRule(zone string, rule *Rule) error {
+	_, err := m.zoneItem(OP_ADD, KIND_RICHRULE, zone, rule.ToString(), 0, true)
+	return err
+}
+
+func (m *MemoryFirewall) RemoveRichRule(zone string, rule *Rule) error {
+	_, err := m.zoneItem(OP_REMOVE, KIND_RICHRULE, zone, rule.ToString(), 0, false)
+	return err
+}
+
+func (m *MemoryFirewall) PermanentRemoveRichRule(zone string, rule *Rule) error {
+	_, err := m.zoneItem(OP_REMOVE, KIND_RICHRULE, zone, rule.ToString(), 0, true)
+	return err
+}
+
+func (m *MemoryFirewall) QueryRichRule(zone string, rule *Rule) bool {
+	b, _ := m.queryItem(KIND_RICHRULE, zone, rule.ToString(), false)
+	return b
+}
+
+func (m *MemoryFirewall) PermanentQueryRichRule(zone string, rule *Rule) bool {
+	b, _ := m.queryItem(KIND_RICHRULE, zone, rule.ToString(), true)
+	return b
+}
+
+func (m *MemoryFirewall) PermanentReplaceRichRules(zone string, rules []string) ([]Conflict, error) {
+	return permanentReplaceRichRules(m, zone, rules)
+}
+
+/************************************************** desired state area ***********************************************************/
+
+func (m *MemoryFirewall) Plan(desired *DesiredState) (*Plan, error) {
+	return computePlan(m, desired)
+}
+
+func (m *MemoryFirewall) Apply(plan *Plan) error {
+	return applyPlan(m, plan)
+}
+
+func (m *MemoryFirewall) Drift(zone string) (*DriftReport, error) {
+	return zoneDrift(m, zone)
+}
+
+func (m *MemoryFirewall) PromoteDrift(report *DriftReport, items ...DriftItem) error {
+	return promoteDrift(m, report, items...)
+}
+
+func (m *MemoryFirewall) RevertDrift(report *DriftReport, items ...DriftItem) error {
+	return revertDrift(m, report, items...)
+}
+
+func (m *MemoryFirewall) NewSimulator() (*Simulator, error) {
+	return loadSimulator(m, false)
+}
+
+func (m *MemoryFirewall) Simulate(flow Flow) (*Verdict, error) {
+	return simulateFlow(m, flow)
+}
+
+func zoneNames(m map[string]*Settings) (names []string) {
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func ipsetNames(m map[string]*IPSetSettings) (names []string) {
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func serviceNames(m map[string]*ServiceSettings) (names []string) {
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func policyNames(m map[string]*PolicySettings) (names []string) {
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,565 @@
+package dbus
+
+import (
+	"net"
+	"reflect"
+	"strings"
+
+	"github.com/cylonchau/gofirewallder/object"
+)
+
+/************************************************** service area ***********************************************************/
+
+func (m *MemoryFirewall) GetServiceSettings(service string) (*ServiceSettings, error) {
+	return m.getServiceSettings(service, false)
+}
+
+func (m *MemoryFirewall) PermanentGetServiceSettings(service string) (*ServiceSettings, error) {
+	return m.getServiceSettings(service, true)
+}
+
+func (m *MemoryFirewall) getServiceSettings(service string, permanent bool) (*ServiceSettings, error) {
+	m.begin()
+	defer m.end()
+	settings, ok := m.config(permanent).Services[service]
+	if !ok {
+		return nil, memoryError("INVALID_SERVICE", "%s", service)
+	}
+	copied := &ServiceSettings{}
+	copyJSON(settings, copied)
+	return copied, nil
+}
+
+func (m *MemoryFirewall) PermanentGetServices() ([]string, error) {
+	m.begin()
+	defer m.end()
+	return serviceNames(m.permanent.Services), nil
+}
+
+// PermanentAddServiceConfig adds the definition of service to permanent configuration, like config addService2.
+func (m *MemoryFirewall) PermanentAddServiceConfig(service string, settings *ServiceSettings) error {
+	m.begin()
+	defer m.end()
+	if _, ok := m.permanent.Services[service]; ok {
+		return memoryError("NAME_CONFLICT", "service %s already exists", service)
+	}
+	copied := &ServiceSettings{}
+	copyJSON(settings, copied)
+	m.permanent.Services[service] = copied
+	m.emit(Event{Name: "ServiceAdded", Interface: object.CONFIG_INTERFACE, Item: service, Permanent: true})
+	return nil
+}
+
+// PermanentSetServiceSettings replaces the permanent definition of service, like config.service update2.
+func (m *MemoryFirewall) PermanentSetServiceSettings(service string, settings *ServiceSettings) error {
+	m.begin()
+	defer m.end()
+	if _, ok := m.permanent.Services[service]; !ok {
+		return memoryError("INVALID_SERVICE", "%s", service)
+	}
+	copied := &ServiceSettings{}
+	copyJSON(settings, copied)
+	m.permanent.Services[service] = copied
+	m.emitUpdated(object.CONFIG_SERVICE, service, EVENT_UPDATED)
+	return nil
+}
+
+// PermanentRemoveServiceConfig removes the definition of service from permanent configuration.
+func (m *MemoryFirewall) PermanentRemoveServiceConfig(service string) error {
+	m.begin()
+	defer m.end()
+	if _, ok := m.permanent.Services[service]; !ok {
+		return memoryError("INVALID_SERVICE", "%s", service)
+	}
+	if _, ok := m.defaults.Services[service]; ok {
+		return memoryError("BUILTIN_SERVICE", "%s", service)
+	}
+	delete(m.permanent.Services, service)
+	m.emitUpdated(object.CONFIG_SERVICE, service, EVENT_REMOVED)
+	return nil
+}
+
+// PermanentGetBuiltin reports whether the object of kind, KIND_ZONE, KIND_IPSET, KIND_SERVICE or KIND_POLICY, is one
+// of the predefined objects and whether it still has its predefined settings, the builtin and default properties of firewalld.
+func (m *MemoryFirewall) PermanentGetBuiltin(kind, name string) (builtin, isDefault bool, err error) {
+	m.begin()
+	defer m.end()
+	current, shipped, err := m.configPair(kind, name)
+	if err != nil || shipped == nil {
+		return false, false, err
+	}
+	return true, sameJSON(current, shipped), nil
+}
+
+// PermanentLoadDefaults resets a predefined object of kind to its predefined settings.
+func (m *MemoryFirewall) PermanentLoadDefaults(kind, name string) error {
+	m.begin()
+	defer m.end()
+	_, shipped, err := m.configPair(kind, name)
+	if err != nil {
+		return err
+	}
+	if shipped == nil {
+		return memoryError("NOT_APPLICABLE", "%s %s is not builtin", kind, name)
+	}
+	var iface string
+	switch kind {
+	case KIND_ZONE:
+		settings := &Settings{}
+		copyJSON(shipped, settings)
+		m.permanent.Zones[name], iface = settings, object.CONFIG_ZONE
+	case KIND_IPSET:
+		settings := &IPSetSettings{}
+		copyJSON(shipped, settings)
+		m.permanent.IPSets[name], iface = settings, object.CONFIG_IPSET
+	case KIND_SERVICE:
+		settings := &ServiceSettings{}
+		copyJSON(shipped, settings)
+		m.permanent.Services[name], iface = settings, object.CONFIG_SERVICE
+	case KIND_POLICY:
+		settings := &PolicySettings{}
+		copyJSON(shipped, settings)
+		m.permanent.Policies[name], iface = settings, object.CONFIG_POLICY
+	}
+	m.emitUpdated(iface, name, EVENT_UPDATED)
+	return nil
+}
+
+// configPair returns the permanent settings of the object of kind and its predefined settings, nil if it is not predefined.
+func (m *MemoryFirewall) configPair(kind, name string) (current, shipped interface{}, err error) {
+	var ok bool
+	switch kind {
+	case KIND_ZONE:
+		if current, ok = m.permanent.Zones[name]; !ok {
+			return nil, nil, memoryError("INVALID_ZONE", "%s", name)
+		}
+		if settings, ok := m.defaults.Zones[name]; ok {
+			shipped = settings
+		}
+	case KIND_IPSET:
+		if current, ok = m.permanent.IPSets[name]; !ok {
+			return nil, nil, memoryError("INVALID_IPSET", "%s", name)
+		}
+		if settings, ok := m.defaults.IPSets[name]; ok {
+			shipped = settings
+		}
+	case KIND_SERVICE:
+		if current, ok = m.permanent.Services[name]; !ok {
+			return nil, nil, memoryError("INVALID_SERVICE", "%s", name)
+		}
+		if settings, ok := m.defaults.Services[name]; ok {
+			shipped = settings
+		}
+	case KIND_POLICY:
+		if current, ok = m.permanent.Policies[name]; !ok {
+			return nil, nil, memoryError("INVALID_POLICY", "%s", name)
+		}
+		if settings, ok := m.defaults.Policies[name]; ok {
+			shipped = settings
+		}
+	default:
+		return nil, nil, memoryError("INVALID_COMMAND", "unknown kind '%s'", kind)
+	}
+	return current, shipped, nil
+}
+
+/************************************************** ipset area ***********************************************************/
+
+var memoryIPSetTypes = []string{
+	"hash:ip", "hash:ip,mark", "hash:ip,port", "hash:ip,port,ip", "hash:ip,port,net",
+	"hash:mac", "hash:net", "hash:net,iface", "hash:net,net", "hash:net,port", "hash:net,port,net",
+}
+
+func (m *MemoryFirewall) GetIPSets() ([]string, error) {
+	m.begin()
+	defer m.end()
+	return ipsetNames(m.runtime.IPSets), nil
+}
+
+func (m *MemoryFirewall) PermanentGetIPSets() ([]string, error) {
+	m.begin()
+	defer m.end()
+	return ipsetNames(m.permanent.IPSets), nil
+}
+
+func (m *MemoryFirewall) GetIPSetEntries(ipset string) ([]string, error) {
+	return m.getIPSetEntries(ipset, false)
+}
+
+func (m *MemoryFirewall) PermanentGetIPSetEntries(ipset string) ([]string, error) {
+	return m.getIPSetEntries(ipset, true)
+}
+
+func (m *MemoryFirewall) getIPSetEntries(ipset string, permanent bool) ([]string, error) {
+	m.begin()
+	defer m.end()
+	settings, ok := m.config(permanent).IPSets[ipset]
+	if !ok {
+		return nil, memoryError("INVALID_IPSET", "%s", ipset)
+	}
+	return append([]string{}, settings.Entry...), nil
+}
+
+func (m *MemoryFirewall) PermanentGetIPSetSettings(ipset string) (*IPSetSettings, error) {
+	m.begin()
+	defer m.end()
+	settings, ok := m.permanent.IPSets[ipset]
+	if !ok {
+		return nil, memoryError("INVALID_IPSET", "%s", ipset)
+	}
+	copied := &IPSetSettings{}
+	copyJSON(settings, copied)
+	return copied, nil
+}
+
+func (m *MemoryFirewall) PermanentAddIPSet(ipset, ipsetType string) error {
+	m.begin()
+	defer m.end()
+	if _, ok := m.permanent.IPSets[ipset]; ok {
+		return memoryError("NAME_CONFLICT", "ipset %s already exists", ipset)
+	}
+	if !contains(memoryIPSetTypes, ipsetType) {
+		return memoryError("INVALID_TYPE", "%s", ipsetType)
+	}
+	m.permanent.IPSets[ipset] = &IPSetSettings{Short: ipset, Type: ipsetType}
+	m.emit(Event{Name: "IPSetAdded", Interface: object.CONFIG_INTERFACE, Item: ipset, Permanent: true})
+	return nil
+}
+
+func (m *MemoryFirewall) PermanentSetIPSetSettings(ipset string, settings *IPSetSettings) error {
+	m.begin()
+	defer m.end()
+	if _, ok := m.permanent.IPSets[ipset]; !ok {
+		return memoryError("INVALID_IPSET", "%s", ipset)
+	}
+	if !contains(memoryIPSetTypes, settings.Type) {
+		return memoryError("INVALID_TYPE", "%s", settings.Type)
+	}
+	copied := &IPSetSettings{}
+	copyJSON(settings, copied)
+	m.permanent.IPSets[ipset] = copied
+	m.emitUpdated(object.CONFIG_IPSET, ipset, EVENT_UPDATED)
+	return nil
+}
+
+func (m *MemoryFirewall) PermanentRemoveIPSet(ipset string) error {
+	m.begin()
+	defer m.end()
+	if _, ok := m.permanent.IPSets[ipset]; !ok {
+		return memoryError("INVALID_IPSET", "%s", ipset)
+	}
+	delete(m.permanent.IPSets, ipset)
+	m.emitUpdated(object.CONFIG_IPSET, ipset, EVENT_REMOVED)
+	return nil
+}
+
+func (m *MemoryFirewall) AddIPSetEntry(ipset, entry string) error {
+	return m.changeIPSetEntry(ipset, entry, true, false)
+}
+
+func (m *MemoryFirewall) PermanentAddIPSetEntry(ipset, entry string) error {
+	return m.changeIPSetEntry(ipset, entry, true, true)
+}
+
+func (m *MemoryFirewall) RemoveIPSetEntry(ipset, entry string) error {
+	return m.changeIPSetEntry(ipset, entry, false, false)
+}
+
+func (m *MemoryFirewall) PermanentRemoveIPSetEntry(ipset, entry string) error {
+	return m.changeIPSetEntry(ipset, entry, false, true)
+}
+
+func (m *MemoryFirewall) changeIPSetEntry(ipset, entry string, add, permanent bool) error {
+	m.begin()
+	defer m.end()
+	settings, ok := m.config(permanent).IPSets[ipset]
+	if !ok {
+		return memoryError("INVALID_IPSET", "%s", ipset)
+	}
+	present := contains(settings.Entry, entry)
+	switch {
+	case add && present:
+		return memoryError("ALREADY_ENABLED", "%s in %s", entry, ipset)
+	case !add && !present:
+		return memoryError("NOT_ENABLED", "%s not in %s", entry, ipset)
+	case add && !validIPSetEntry(settings.Type, entry):
+		return memoryError("INVALID_ENTRY", "%s for type %s", entry, settings.Type)
+	}
+	settings.Entry = memoryToggle(settings.Entry, entry, add)
+
+	if permanent {
+		m.emitUpdated(object.CONFIG_IPSET, ipset, EVENT_UPDATED)
+		return nil
+	}
+	name := EVENT_ENTRYREMOVED
+	if add {
+		name = EVENT_ENTRYADDED
+	}
+	m.emit(Event{Name: name, Interface: object.IPSET, IPSet: ipset, Item: entry})
+	return nil
+}
+
+// validIPSetEntry checks the first field of entry against the type, e.g. an address for hash:ip.
+func validIPSetEntry(ipsetType, entry string) bool {
+	first := strings.Split(entry, ",")[0]
+	switch {
+	case strings.HasPrefix(ipsetType, "hash:ip"):
+		return net.ParseIP(first) != nil || strings.Contains(first, "-")
+	case strings.HasPrefix(ipsetType, "hash:net"):
+		_, _, err := net.ParseCIDR(first)
+		return err == nil || net.ParseIP(first) != nil
+	case ipsetType == "hash:mac":
+		_, err := net.ParseMAC(first)
+		return err == nil
+	}
+	return true
+}
+
+func (m *MemoryFirewall) QueryIPSetEntry(ipset, entry string) (bool, error) {
+	entries, err := m.GetIPSetEntries(ipset)
+	return contains(entries, entry), err
+}
+
+func (m *MemoryFirewall) PermanentQueryIPSetEntry(ipset, entry string) (bool, error) {
+	entries, err := m.PermanentGetIPSetEntries(ipset)
+	return contains(entries, entry), err
+}
+
+/************************************************** policy area ***********************************************************/
+
+func (m *MemoryFirewall) GetPolicies() ([]string, error) {
+	m.begin()
+	defer m.end()
+	return policyNames(m.runtime.Policies), nil
+}
+
+func (m *MemoryFirewall) PermanentGetPolicies() ([]string, error) {
+	m.begin()
+	defer m.end()
+	return policyNames(m.permanent.Policies), nil
+}
+
+func (m *MemoryFirewall) GetPolicySettings(policy string) (*PolicySettings, error) {
+	return m.getPolicySettings(policy, false)
+}
+
+func (m *MemoryFirewall) PermanentGetPolicySettings(policy string) (*PolicySettings, error) {
+	return m.getPolicySettings(policy, true)
+}
+
+func (m *MemoryFirewall) getPolicySettings(policy string, permanent bool) (*PolicySettings, error) {
+	m.begin()
+	defer m.end()
+	settings, ok := m.config(permanent).Policies[policy]
+	if !ok {
+		return nil, memoryError("INVALID_POLICY", "%s", policy)
+	}
+	copied := &PolicySettings{}
+	copyJSON(settings, copied)
+	return copied, nil
+}
+
+func (m *MemoryFirewall) SetPolicySettings(policy string, settings *PolicySettings) error {
+	return m.setPolicySettings(policy, settings, false)
+}
+
+func (m *MemoryFirewall) PermanentSetPolicySettings(policy string, settings *PolicySettings) error {
+	return m.setPolicySettings(policy, settings, true)
+}
+
+func (m *MemoryFirewall) setPolicySettings(policy string, settings *PolicySettings, permanent bool) error {
+	m.begin()
+	defer m.end()
+	config := m.config(permanent)
+	if _, ok := config.Policies[policy]; !ok {
+		return memoryError("INVALID_POLICY", "%s", policy)
+	}
+	if err := m.checkPolicy(config, settings); err != nil {
+		return err
+	}
+	copied := &PolicySettings{}
+	copyJSON(settings, copied)
+	config.Policies[policy] = copied
+	if permanent {
+		m.emitUpdated(object.CONFIG_POLICY, policy, EVENT_UPDATED)
+	}
+	return nil
+}
+
+func (m *MemoryFirewall) PermanentAddPolicy(policy string, settings *PolicySettings) error {
+	m.begin()
+	defer m.end()
+	if _, ok := m.permanent.Policies[policy]; ok {
+		return memoryError("NAME_CONFLICT", "policy %s already exists", policy)
+	}
+	if err := m.checkPolicy(m.permanent, settings); err != nil {
+		return err
+	}
+	copied := &PolicySettings{}
+	copyJSON(settings, copied)
+	m.permanent.Policies[policy] = copied
+	m.emit(Event{Name: "PolicyAdded", Interface: object.CONFIG_INTERFACE, Item: policy, Permanent: true})
+	return nil
+}
+
+func (m *MemoryFirewall) PermanentRemovePolicy(policy string) error {
+	m.begin()
+	defer m.end()
+	if _, ok := m.permanent.Policies[policy]; !ok {
+		return memoryError("INVALID_POLICY", "%s", policy)
+	}
+	delete(m.permanent.Policies, policy)
+	m.emitUpdated(object.CONFIG_POLICY, policy, EVENT_REMOVED)
+	return nil
+}
+
+// checkPolicy checks the target and the zones of settings, HOST and ANY are the symbolic zones of firewalld.
+func (m *MemoryFirewall) checkPolicy(config *memoryConfig, settings *PolicySettings) error {
+	switch settings.Target {
+	case "", "CONTINUE", "ACCEPT", "REJECT", "DROP":
+	default:
+		return memoryError("INVALID_TARGET", "%s", settings.Target)
+	}
+	for _, zone := range append(append([]string{}, settings.IngressZones...), settings.EgressZones...) {
+		if _, ok := config.Zones[zone]; !ok && zone != "HOST" && zone != "ANY" {
+			return memoryError("INVALID_ZONE", "%s", zone)
+		}
+	}
+	return nil
+}
+
+/************************************************** direct area ***********************************************************/
+
+func (m *MemoryFirewall) GetDirectSettings() (*DirectSettings, error) {
+	return m.getDirectSettings(false)
+}
+
+func (m *MemoryFirewall) PermanentGetDirectSettings() (*DirectSettings, error) {
+	return m.getDirectSettings(true)
+}
+
+func (m *MemoryFirewall) getDirectSettings(permanent bool) (*DirectSettings, error) {
+	m.begin()
+	defer m.end()
+	copied := &DirectSettings{}
+	copyJSON(m.config(permanent).Direct, copied)
+	return copied, nil
+}
+
+func (m *MemoryFirewall) PermanentSetDirectSettings(settings *DirectSettings) error {
+	m.begin()
+	defer m.end()
+	copied := &DirectSettings{}
+	copyJSON(settings, copied)
+	m.permanent.Direct = copied
+	m.emitUpdated(object.CONFIG_DIRECT_INTERFACE, "", EVENT_UPDATED)
+	return nil
+}
+
+func (m *MemoryFirewall) AddDirectChain(chain DirectChain) error {
+	return m.changeDirect(chain, true)
+}
+
+func (m *MemoryFirewall) RemoveDirectChain(chain DirectChain) error {
+	return m.changeDirect(chain, false)
+}
+
+func (m *MemoryFirewall) AddDirectRule(rule DirectRule) error {
+	return m.changeDirect(rule, true)
+}
+
+func (m *MemoryFirewall) RemoveDirectRule(rule DirectRule) error {
+	return m.changeDirect(rule, false)
+}
+
+func (m *MemoryFirewall) AddDirectPassthrough(passthrough DirectPassthrough) error {
+	return m.changeDirect(passthrough, true)
+}
+
+func (m *MemoryFirewall) RemoveDirectPassthrough(passthrough DirectPassthrough) error {
+	return m.changeDirect(passthrough, false)
+}
+
+// changeDirect adds or removes a chain, rule or passthrough of the runtime direct configuration.
+func (m *MemoryFirewall) changeDirect(item interface{}, add bool) error {
+	m.begin()
+	defer m.end()
+	direct := m.runtime.Direct
+	var list interface{}
+	switch item.(type) {
+	case DirectChain:
+		list = &direct.Chain
+	case DirectRule:
+		list = &direct.Rule
+	case DirectPassthrough:
+		list = &direct.Passthrough
+	}
+
+	slice := reflect.ValueOf(list).Elem()
+	index := -1
+	for i := 0; i < slice.Len(); i++ {
+		if reflect.DeepEqual(slice.Index(i).Interface(), item) {
+			index = i
+			break
+		}
+	}
+	switch {
+	case add && index >= 0:
+		return memoryError("ALREADY_ENABLED", "%v", item)
+	case !add && index < 0:
+		return memoryError("NOT_ENABLED", "%v", item)
+	case add:
+		slice.Set(reflect.Append(slice, reflect.ValueOf(item)))
+	default:
+		slice.Set(reflect.AppendSlice(slice.Slice(0, index), slice.Slice(index+1, slice.Len())))
+	}
+	return nil
+}
+
+/************************************************** fw service area ***********************************************************/
+
+// Reload replaces the runtime configuration with the permanent one, items with a timeout are dropped.
+func (m *MemoryFirewall) Reload() error {
+	m.begin()
+	defer m.end()
+	m.runtime = m.permanent.copy()
+	m.expiries = nil
+	if _, ok := m.runtime.Zones[m.defaultZone]; !ok {
+		m.defaultZone = "public"
+	}
+	m.emit(Event{Name: EVENT_RELOADED, Interface: object.INTERFACE})
+	return nil
+}
+
+func (m *MemoryFirewall) RuntimeToPermanent() error {
+	m.begin()
+	defer m.end()
+	m.permanent = m.runtime.copy()
+	for _, name := range zoneNames(m.permanent.Zones) {
+		m.emitUpdated(object.CONFIG_ZONE, name, EVENT_UPDATED)
+	}
+	return nil
+}
+
+// QueryPanicMode is false, panic mode is not modelled.
+func (m *MemoryFirewall) QueryPanicMode() (bool, error) {
+	return false, nil
+}
+
+// GetState is STATE_RUNNING.
+func (m *MemoryFirewall) GetState() (string, error) {
+	return STATE_RUNNING, nil
+}
+
+// RuntimeFlush replaces the permanent settings of zone like DbusClientSerivce.RuntimeFlush.
+func (m *MemoryFirewall) RuntimeFlush(zone string) error {
+	if zone == "" {
+		zone = m.GetDefaultZone()
+	}
+	return m.PermanentSetZoneSettings(zone, &Settings{
+		Targe:       "default",
+		Description: "reset to firewalld-api",
+		Short:       zone,
+		Service:     []string{"ssh", "dhcpv6-client"},
+	})
+}
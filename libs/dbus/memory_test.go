@@ -0,0 +1,47 @@
+package dbus_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+)
+
+func TestMemoryExpiryOfReaddedItem(t *testing.T) {
+	now := time.Now()
+	fw := dbus.NewMemoryFirewall()
+	fw.Now = func() time.Time { return now }
+
+	if _, err := fw.AddService("public", "http", 30); err != nil {
+		t.Fatal(err)
+	}
+	if err := fw.RemoveService("public", "http"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.AddService("public", "http", 0); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	if !fw.QueryService("public", "http") {
+		t.Error("http added without timeout was removed by the timeout of the earlier add")
+	}
+
+	// a re-add with a longer timeout keeps the item until the new timeout.
+	if _, err := fw.AddService("public", "https", 30); err != nil {
+		t.Fatal(err)
+	}
+	if err := fw.RemoveService("public", "https"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.AddService("public", "https", 90); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	if !fw.QueryService("public", "https") {
+		t.Error("https was removed by the timeout of the earlier add")
+	}
+	now = now.Add(time.Minute)
+	if fw.QueryService("public", "https") {
+		t.Error("https was not removed after its timeout")
+	}
+}
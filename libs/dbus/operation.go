@@ -0,0 +1,317 @@
+package dbus
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	OP_ADD    = "add"
+	OP_REMOVE = "remove"
+	OP_SET    = "set"
+
+	KIND_PORT        = "port"
+	KIND_SERVICE     = "service"
+	KIND_SOURCE      = "source"
+	KIND_INTERFACE   = "interface"
+	KIND_FORWARDPORT = "forward-port"
+	KIND_MASQUERADE  = "masquerade"
+	KIND_RICHRULE    = "rich-rule"
+	KIND_ZONE        = "zone"
+	KIND_IPSET       = "ipset"
+	KIND_IPSETENTRY  = "ipset-entry"
+	KIND_DEFAULTZONE = "default-zone"
+	KIND_TARGET      = "target"
+	KIND_RELOAD      = "reload"
+
+	// KIND_POLICY names policies in errors and for the config objects, policies have no Operation.
+	KIND_POLICY = "policy"
+)
+
+/*
+ * Operation is a single firewalld change, it is serializable and has an inverse.
+ *   Value by kind:
+ *     port          e.g. 80/tcp, 1000-1100/udp
+ *     service       e.g. ssh
+ *     source        e.g. 10.0.0.0/8, ipset:blocklist
+ *     interface     e.g. eth0
+ *     forward-port  e.g. port=80:proto=tcp:toport=8080:toaddr=10.0.0.2
+ *     rich-rule     e.g. rule family="ipv4" source address="10.0.0.0/8" accept
+ *     zone          empty, the zone name is in Zone, permanent only
+ *     ipset         ipset type, e.g. hash:ip, the ipset name is in IPSet
+ *     ipset-entry   e.g. 10.0.0.1, the ipset name is in IPSet
+ *     default-zone  the new default zone, Previous is the old one
+ *     target        the new zone target, e.g. DROP, Previous is the old one, permanent only
+ */
+type Operation struct {
+	Action    string `json:"action" yaml:"action"`
+	Kind      string `json:"kind" yaml:"kind"`
+	Zone      string `json:"zone,omitempty" yaml:"zone,omitempty"`
+	IPSet     string `json:"ipset,omitempty" yaml:"ipset,omitempty"`
+	Value     string `json:"value,omitempty" yaml:"value,omitempty"`
+	Previous  string `json:"previous,omitempty" yaml:"previous,omitempty"`
+	Permanent bool   `json:"permanent" yaml:"permanent"`
+	Timeout   int    `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// String renders the operation for humans, e.g. "+ permanent zone=public port 80/tcp"
+func (op Operation) String() string {
+	var sign = "~"
+	switch op.Action {
+	case OP_ADD:
+		sign = "+"
+	case OP_REMOVE:
+		sign = "-"
+	}
+	scope := "runtime"
+	if op.Permanent {
+		scope = "permanent"
+	}
+
+	switch op.Kind {
+	case KIND_RELOAD:
+		return sign + " reload"
+	case KIND_DEFAULTZONE:
+		return fmt.Sprintf("%s default-zone %s -> %s", sign, op.Previous, op.Value)
+	case KIND_TARGET:
+		return fmt.Sprintf("%s %s zone=%s target %s -> %s", sign, scope, op.Zone, op.Previous, op.Value)
+	case KIND_ZONE:
+		return fmt.Sprintf("%s %s zone %s", sign, scope, op.Zone)
+	case KIND_IPSET:
+		return fmt.Sprintf("%s %s ipset %s type=%s", sign, scope, op.IPSet, op.Value)
+	case KIND_IPSETENTRY:
+		return fmt.Sprintf("%s %s ipset=%s entry %s", sign, scope, op.IPSet, op.Value)
+	case KIND_MASQUERADE:
+		return fmt.Sprintf("%s %s zone=%s masquerade", sign, scope, op.Zone)
+	}
+	str := fmt.Sprintf("%s %s zone=%s %s %s", sign, scope, op.Zone, op.Kind, op.Value)
+	if op.Timeout > 0 {
+		str += fmt.Sprintf(" timeout=%ds", op.Timeout)
+	}
+	return str
+}
+
+// Inverse returns the operation undoing op, ok is false if op can not be undone, e.g. reload or the removal of a zone
+// or ipset, an operation can not hold their settings, see Tx.
+func (op Operation) Inverse() (inverse Operation, ok bool) {
+	inverse = op
+	inverse.Timeout = 0
+	switch op.Action {
+	case OP_ADD:
+		inverse.Action = OP_REMOVE
+	case OP_REMOVE:
+		inverse.Action = OP_ADD
+	case OP_SET:
+		if op.Previous == "" {
+			return inverse, false
+		}
+		inverse.Value, inverse.Previous = op.Previous, op.Value
+	default:
+		return inverse, false
+	}
+	if op.Kind == KIND_RELOAD || (op.Action == OP_REMOVE && (op.Kind == KIND_ZONE || op.Kind == KIND_IPSET)) {
+		return inverse, false
+	}
+	return inverse, true
+}
+
+// @title         Execute
+// @description   execute the operation with the corresponding runtime or Permanent method.
+// @auth      	  author           2021-10-11
+// @param         op               Operation      "the change to execute."
+// @return        error            error          "Possible errors: the errors of the called method, INVALID_COMMAND"
+func (c *DbusClientSerivce) Execute(op Operation) (err error) {
+	c, end := c.span("Execute", 0)
+	defer end(&err)
+	return execute(c, op)
+}
+
+func execute(c Firewall, op Operation) (err error) {
+	add := op.Action == OP_ADD
+	if op.Action != OP_ADD && op.Action != OP_REMOVE && op.Action != OP_SET && op.Kind != KIND_RELOAD {
+		return fmt.Errorf("INVALID_COMMAND: unknown action '%s'", op.Action)
+	}
+
+	switch op.Kind {
+	case KIND_PORT:
+		switch {
+		case add && op.Permanent:
+			return c.PermanentAddPort(op.Value, op.Zone)
+		case add:
+			_, err = c.AddPort(op.Value, op.Zone, op.Timeout)
+		case op.Permanent:
+			_, err = c.PermanentRemovePort(op.Value, op.Zone)
+		default:
+			_, err = c.RemovePort(op.Value, op.Zone)
+		}
+	case KIND_SERVICE:
+		switch {
+		case add && op.Permanent:
+			return c.PermanentAddService(op.Zone, op.Value)
+		case add:
+			_, err = c.AddService(op.Zone, op.Value, op.Timeout)
+		case op.Permanent:
+			return c.PermanentRemoveService(op.Zone, op.Value)
+		default:
+			return c.RemoveService(op.Zone, op.Value)
+		}
+	case KIND_SOURCE:
+		switch {
+		case add && op.Permanent:
+			return c.PermanentAddSource(op.Zone, op.Value)
+		case add:
+			_, err = c.AddSource(op.Zone, op.Value)
+		case op.Permanent:
+			return c.PermanentRemoveSource(op.Zone, op.Value)
+		default:
+			return c.RemoveSource(op.Zone, op.Value)
+		}
+	case KIND_INTERFACE:
+		switch {
+		case add && op.Permanent:
+			return c.PermanentBindInterface(op.Zone, op.Value)
+		case add:
+			_, err = c.BindInterface(op.Zone, op.Value)
+		case op.Permanent:
+			return c.PermanentRemoveInterface(op.Zone, op.Value)
+		default:
+			return c.RemoveInterface(op.Zone, op.Value)
+		}
+	case KIND_FORWARDPORT:
+		var forward ForwardPort
+		if forward, err = StringToForwardPort(op.Value); err != nil {
+			return err
+		}
+		portProtocol := forward.Port + "/" + forward.Protocol
+		toHostPort := net.JoinHostPort(forward.ToAddr, forward.ToPort)
+		switch {
+		case add && op.Permanent:
+			return c.PermanentAddForwardPort(op.Zone, portProtocol, toHostPort)
+		case add:
+			return c.AddForwardPort(op.Zone, portProtocol, toHostPort, op.Timeout)
+		case op.Permanent:
+			return c.PermanentRemoveForwardPort(op.Zone, portProtocol, toHostPort)
+		default:
+			return c.RemoveForwardPort(op.Zone, portProtocol, toHostPort)
+		}
+	case KIND_MASQUERADE:
+		switch {
+		case add && op.Permanent:
+			return c.PermanentEnableMasquerade(op.Zone)
+		case add:
+			return c.EnableMasquerade(op.Zone, op.Timeout)
+		case op.Permanent:
+			return c.PermanentDisableMasquerade(op.Zone)
+		default:
+			return c.DisableMasquerade(op.Zone)
+		}
+	case KIND_RICHRULE:
+		rule := StringToRule(op.Value)
+		switch {
+		case add && op.Permanent:
+			return c.PermanentAddRichRule(op.Zone, rule)
+		case add:
+			return c.AddRichRule(op.Zone, rule, op.Timeout)
+		case op.Permanent:
+			return c.PermanentRemoveRichRule(op.Zone, rule)
+		default:
+			return c.RemoveRichRule(op.Zone, rule)
+		}
+	case KIND_ZONE:
+		switch {
+		case !op.Permanent:
+			return fmt.Errorf("INVALID_COMMAND: zone %s can only be changed in permanent configuration", op.Zone)
+		case add:
+			return c.AddZone(op.Zone)
+		default:
+			return c.PermanentRemoveZone(op.Zone)
+		}
+	case KIND_IPSET:
+		switch {
+		case !op.Permanent:
+			return fmt.Errorf("INVALID_COMMAND: ipset %s can only be changed in permanent configuration", op.IPSet)
+		case add:
+			return c.PermanentAddIPSet(op.IPSet, op.Value)
+		default:
+			return c.PermanentRemoveIPSet(op.IPSet)
+		}
+	case KIND_IPSETENTRY:
+		switch {
+		case add && op.Permanent:
+			return c.PermanentAddIPSetEntry(op.IPSet, op.Value)
+		case add:
+			return c.AddIPSetEntry(op.IPSet, op.Value)
+		case op.Permanent:
+			return c.PermanentRemoveIPSetEntry(op.IPSet, op.Value)
+		default:
+			return c.RemoveIPSetEntry(op.IPSet, op.Value)
+		}
+	case KIND_DEFAULTZONE:
+		return c.SetDefaultZone(op.Value)
+	case KIND_TARGET:
+		if !op.Permanent {
+			return fmt.Errorf("INVALID_COMMAND: target of zone %s can only be changed in permanent configuration", op.Zone)
+		}
+		return c.PermanentSetZoneTarget(op.Zone, op.Value)
+	case KIND_RELOAD:
+		return c.Reload()
+	default:
+		return fmt.Errorf("INVALID_COMMAND: unknown kind '%s'", op.Kind)
+	}
+	return err
+}
+
+// ForwardPortToString renders forward port in firewall-cmd form, e.g. port=80:proto=tcp:toport=8080:toaddr=10.0.0.2
+func ForwardPortToString(forward ForwardPort) string {
+	str := "port=" + forward.Port + ":proto=" + forward.Protocol
+	if forward.ToPort != "" {
+		str += ":toport=" + forward.ToPort
+	}
+	if forward.ToAddr != "" {
+		str += ":toaddr=" + forward.ToAddr
+	}
+	return str
+}
+
+// StringToForwardPort parses forward port in firewall-cmd form, e.g. port=80:proto=tcp:toport=8080:toaddr=10.0.0.2
+func StringToForwardPort(str string) (forward ForwardPort, err error) {
+	fields := str
+	// toaddr is the last field and an ipv6 address contains colons.
+	if index := strings.Index(str, "toaddr="); index >= 0 {
+		forward.ToAddr = str[index+len("toaddr="):]
+		fields = strings.TrimSuffix(str[:index], ":")
+	}
+	for _, field := range strings.Split(fields, ":") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return forward, fmt.Errorf("INVALID_FORWARD: %s", str)
+		}
+		switch kv[0] {
+		case "port":
+			forward.Port = kv[1]
+		case "proto":
+			forward.Protocol = kv[1]
+		case "toport":
+			forward.ToPort = kv[1]
+		case "toaddr":
+			forward.ToAddr = kv[1]
+		default:
+			return forward, fmt.Errorf("INVALID_FORWARD: %s", str)
+		}
+	}
+	if forward.Port == "" || forward.Protocol == "" || (forward.ToPort == "" && forward.ToAddr == "") {
+		return forward, fmt.Errorf("INVALID_FORWARD: %s", str)
+	}
+	return forward, nil
+}
+
+// Inverse returns the operations undoing ops, in reverse order, operations that can not be undone are skipped.
+func Inverse(ops []Operation) (inverse []Operation) {
+	for index := len(ops) - 1; index >= 0; index-- {
+		if op, ok := ops[index].Inverse(); ok {
+			inverse = append(inverse, op)
+		}
+	}
+	return inverse
+}
@@ -1,8 +1,13 @@
 package dbus
 
 import (
+	"fmt"
+	"net"
 	"reflect"
+	"strconv"
 	"strings"
+
+	"github.com/godbus/dbus/v5"
 )
 
 type Source struct {
@@ -44,14 +49,24 @@ type ForwardPort struct {
 }
 
 type Log struct {
+	Flag   bool
 	Prefix string `json:"prefix"`
 	Level  string `json:"level"`
 	Limit  Limit  `json:"limit"`
 }
+type NfLog struct {
+	Flag      bool
+	Group     string `json:"group"`
+	Prefix    string `json:"prefix"`
+	QueueSize string `json:"queuesize"`
+	Limit     Limit  `json:"limit"`
+}
 type Limit struct {
 	Value string `json:"value"`
+	Burst string `json:"burst"`
 }
 type Audit struct {
+	Flag  bool
 	Limit Limit `json:"limit"`
 }
 type Accept struct {
@@ -59,6 +74,7 @@ type Accept struct {
 	Limit Limit `json:"limit"`
 }
 type Reject struct {
+	Flag  bool
 	Type  string `json:"type"`
 	Limit Limit  `json:"limit"`
 }
@@ -77,8 +93,15 @@ type SourcePort struct {
 	Protocol string `json:"protocol"`
 }
 
+// TcpMssClamp without Value clamps to the path mtu.
+type TcpMssClamp struct {
+	Flag  bool
+	Value string `json:"value"`
+}
+
 type Rule struct {
 	Family      string      `json:"family"`
+	Priority    int         `json:"priority"`
 	Source      Source      `json:"source"`
 	Destination Destination `json:"destination"`
 	Service     Service     `json:"service"`
@@ -87,12 +110,18 @@ type Rule struct {
 	IcmpBlock   IcmpBlock   `json:"icmpblock"`
 	IcmpType    IcmpType    `json:"icmptype"`
 	ForwardPort ForwardPort `json:"forwardport"`
+	SourcePort  SourcePort  `json:"sourceport"`
+	Masquerade  bool        `json:"masquerade"`
+	TcpMssClamp TcpMssClamp `json:"tcpmssclamp"`
 	Log         Log         `json:"log"`
+	NfLog       NfLog       `json:"nflog"`
 	Audit       Audit       `json:"audit"`
 	Accept      Accept      `json:"accept"`
 	Reject      Reject      `json:"reject"`
 	Drop        Drop        `json:"drop"`
 	Mark        Mark        `json:"mark"`
+	// Raw is a rule the parser does not understand, ToString returns it as is, see StringToRule.
+	Raw string `json:"raw,omitempty"`
 }
 
 type Interface struct {
@@ -138,7 +167,118 @@ type Settings struct {
 	Rule               []Rule        `json:"rule"`
 	Protocol           []Protocol    `json:"protocol"`
 	SourcePort         []SourcePort  `json:"sourceport"`
-	IcmpBlockInversion bool          `json:"icmp-block-inversion""`
+	IcmpBlockInversion bool          `json:"icmp-block-inversion"`
+}
+
+/*
+ * 对应firewalld serviceSettings的顺序
+   [
+	   "", version
+	   "", short
+	   "", description
+	   [], ports
+	   [], module names
+	   {}, destinations
+	]
+ * firewalld >= 0.9 also has protocols, source ports, includes and helpers, see getSettings2.
+*/
+
+type ServiceSettings struct {
+	Version     string            `json:"version"`
+	Short       string            `json:"short"`
+	Description string            `json:"description"`
+	Port        []Port            `json:"port"`
+	Module      []string          `json:"module"`
+	Destination map[string]string `json:"destination"`
+	Protocol    []Protocol        `json:"protocol"`
+	SourcePort  []SourcePort      `json:"sourceport"`
+	Include     []string          `json:"include"`
+	Helper      []string          `json:"helper"`
+}
+
+/*
+ * 对应firewalld policySettings的key, firewalld >= 0.9
+   {
+	   "version", "short", "description", "target": s
+	   "priority": i
+	   "ingress_zones", "egress_zones", "services", "icmp_blocks", "protocols", "rich_rules": as
+	   "ports", "source_ports": a(ss)
+	   "forward_ports": a(ssss)
+	   "masquerade": b
+	}
+*/
+
+type PolicySettings struct {
+	Version      string        `json:"version"`
+	Short        string        `json:"short"`
+	Description  string        `json:"description"`
+	Target       string        `json:"target"`
+	Priority     int           `json:"priority"`
+	IngressZones []string      `json:"ingresszones"`
+	EgressZones  []string      `json:"egresszones"`
+	Service      []string      `json:"service"`
+	Port         []Port        `json:"port"`
+	IcmpBlock    []IcmpBlock   `json:"icmpblock"`
+	Masquerade   bool          `json:"masquerade"`
+	ForwardPort  []ForwardPort `json:"forwardport"`
+	Rule         []Rule        `json:"rule"`
+	Protocol     []Protocol    `json:"protocol"`
+	SourcePort   []SourcePort  `json:"sourceport"`
+}
+
+/*
+ * 对应firewalld direct settings的顺序
+   [
+	   [], chains (ipv, table, chain)
+	   [], rules (ipv, table, chain, priority, args)
+	   [], passthroughs (ipv, args)
+	]
+*/
+
+type DirectSettings struct {
+	Chain       []DirectChain       `json:"chain"`
+	Rule        []DirectRule        `json:"rule"`
+	Passthrough []DirectPassthrough `json:"passthrough"`
+}
+
+type DirectChain struct {
+	IPV   string `json:"ipv"`
+	Table string `json:"table"`
+	Chain string `json:"chain"`
+}
+
+type DirectRule struct {
+	IPV      string   `json:"ipv"`
+	Table    string   `json:"table"`
+	Chain    string   `json:"chain"`
+	Priority int      `json:"priority"`
+	Args     []string `json:"args"`
+}
+
+type DirectPassthrough struct {
+	IPV  string   `json:"ipv"`
+	Args []string `json:"args"`
+}
+
+/*
+ * 对应firewalld ipsetSettings的顺序
+   [
+	   "", version
+	   "", short
+	   "", description
+	   "", type e.g. hash:ip, hash:net, hash:mac
+	   {}, options e.g. family: inet6, timeout: 60
+	   [], entries
+	]
+*/
+
+type IPSetSettings struct {
+	Version     string            `json:"version"`
+	Short       string            `json:"short"`
+	Description string            `json:"description"`
+	Type        string            `json:"type"`
+	Option      map[string]string `json:"option"`
+	Entry       []string          `json:"entry"`
 }
 
 func (this *Source) IsEmpty() bool {
@@ -197,134 +337,132 @@ func (this *Mark) IsEmpty() bool {
 	return reflect.DeepEqual(this, &Mark{})
 }
 
+func (this *SourcePort) IsEmpty() bool {
+	return reflect.DeepEqual(this, &SourcePort{})
+}
+
 func (this *Limit) IsEmpty() bool {
 	return reflect.DeepEqual(this, &Limit{})
 }
 
+func (this *NfLog) IsEmpty() bool {
+	return reflect.DeepEqual(this, &NfLog{})
+}
+
+func (this *Limit) ToString() string {
+	var str = "limit value=" + quote(this.Value)
+
+	if this.Burst != "" {
+		str += " burst=" + quote(this.Burst)
+	}
+	return str
+}
+
 func (this *Source) ToString() string {
-	var str = " source "
+	var str = "source"
+	if isTrue(this.Invert) {
+		str += " NOT"
+	}
 	if this.Address != "" {
-		str += "address=" + this.Address
+		str += " address=" + quote(this.Address)
 	} else if this.Mac != "" {
-		str += "mac=" + this.Mac
+		str += " mac=" + quote(this.Mac)
 	} else {
-		str += "ipset=" + this.Ipset
+		str += " ipset=" + quote(this.Ipset)
 	}
-	if this.Invert != "" {
-		str += " "
-		str += "invert=" + this.Invert
-	}
-	str += " "
 	return str
 }
 
 func (this *Destination) ToString() string {
-	var str = " destination "
-	if this.Address != "" {
-		str += "address=" + this.Address
-	}
-	if this.Invert != "" {
-		str += " "
-		str += "invert=" + this.Invert
+	var str = "destination"
+	if isTrue(this.Invert) {
+		str += " NOT"
 	}
-	str += " "
+	str += " address=" + quote(this.Address)
 	return str
 }
 
 func (this *Service) ToString() string {
-	var str = "service "
-	if this.Name != "" {
-		str += "name=" + this.Name
-	}
-	str += " "
-	return str
+	return "service name=" + quote(this.Name)
 }
 
 func (this *Port) ToString() string {
-	var str = "port "
-	if this.Port != "" {
-		str += "name=" + this.Port
-	}
-	if this.Protocol != "" {
-		str += "protocol=" + this.Protocol
-	}
-
-	str += " "
-	return str
+	return "port port=" + quote(this.Port) + " protocol=" + quote(this.Protocol)
 }
 
 func (this *Protocol) ToString() string {
-	var str = "Protocol "
-	if this.Value != "" {
-		str += "value=" + this.Value
-	}
-
-	str += " "
-	return str
+	return "protocol value=" + quote(this.Value)
 }
 
 func (this *IcmpBlock) ToString() string {
-	var str = "icmp-block "
-	if this.Name != "" {
-		str += "name=" + this.Name
-	}
-
-	str += " "
-	return str
+	return "icmp-block name=" + quote(this.Name)
 }
 
 func (this *IcmpType) ToString() string {
-	var str = "icmp-type "
-	if this.Name != "" {
-		str += "name=" + this.Name
-	}
-
-	str += " "
-	return str
+	return "icmp-type name=" + quote(this.Name)
 }
 
 func (this *ForwardPort) ToString() string {
-	var str = "forward-port "
-
-	if this.Port != "" {
-		str += "port=" + this.Port
-	}
-
-	if this.Protocol != "" {
-		str += " "
-		str += "protocol=" + this.Protocol
-	}
+	var str = "forward-port port=" + quote(this.Port) + " protocol=" + quote(this.Protocol)
 
 	if this.ToPort != "" {
-		str += " "
-		str += "to-port=" + this.ToPort
+		str += " to-port=" + quote(this.ToPort)
 	}
 
 	if this.ToAddr != "" {
-		str += " "
-		str += "to-addr=" + this.ToAddr
+		str += " to-addr=" + quote(this.ToAddr)
 	}
-
-	str += " "
 	return str
 }
 
+func (this *SourcePort) ToString() string {
+	return "source-port port=" + quote(this.Port) + " protocol=" + quote(this.Protocol)
+}
+
 func (this *Log) ToString() string {
 	var str = "log"
 
 	if this.Prefix != "" {
-		str += " " + "prefix=" + this.Prefix
+		str += " prefix=" + quote(this.Prefix)
 	}
 
 	if this.Level != "" {
-		str += " " + "level=" + this.Level
+		str += " level=" + quote(this.Level)
 	}
 
 	if !this.Limit.IsEmpty() {
-		str += " " + "limit value=" + this.Limit.Value
+		str += " " + this.Limit.ToString()
 	}
+	return str
+}
 
-	str += " "
+func (this *NfLog) ToString() string {
+	var str = "nflog"
+
+	if this.Group != "" {
+		str += " group=" + quote(this.Group)
+	}
+
+	if this.Prefix != "" {
+		str += " prefix=" + quote(this.Prefix)
+	}
+
+	if this.QueueSize != "" {
+		str += " queue-size=" + quote(this.QueueSize)
+	}
+
+	if !this.Limit.IsEmpty() {
+		str += " " + this.Limit.ToString()
+	}
+	return str
+}
+
+func (this *TcpMssClamp) ToString() string {
+	var str = "tcp-mss-clamp"
+
+	if this.Value != "" {
+		str += " value=" + quote(this.Value)
+	}
 	return str
 }
 
@@ -332,359 +470,771 @@ func (this *Audit) ToString() string {
 	var str = "audit"
 
 	if !this.Limit.IsEmpty() {
-		str += " " + "limit value=" + this.Limit.Value
+		str += " " + this.Limit.ToString()
 	}
-
-	str += " "
 	return str
 }
 
 func (this *Accept) ToString() string {
-	var str string
+	var str = "accept"
 
-	if this.Flag {
-		str = "accept "
-	}
 	if !this.Limit.IsEmpty() {
-		str += "limit value=" + this.Limit.Value
+		str += " " + this.Limit.ToString()
 	}
-
-	str += " "
 	return str
 }
 
 func (this *Reject) ToString() string {
-	var str = "reject "
+	var str = "reject"
 
 	if this.Type != "" {
-		str += "type=" + this.Type
+		str += " type=" + quote(this.Type)
 	}
 
 	if !this.Limit.IsEmpty() {
-		str += " "
-		str += "limit value=" + this.Limit.Value
+		str += " " + this.Limit.ToString()
 	}
-
-	str += " "
 	return str
 }
 
 func (this *Drop) ToString() string {
-	var str string
+	var str = "drop"
 
-	if this.Flag {
-		str = "drop "
-	}
 	if !this.Limit.IsEmpty() {
-		str += "limit value=" + this.Limit.Value
+		str += " " + this.Limit.ToString()
 	}
-	str += " "
 	return str
 }
 
 func (this *Mark) ToString() string {
-	var str = "mark"
-
-	if this.Set != "" {
-		str += " "
-		str += "set=" + this.Set
-	}
+	var str = "mark set=" + quote(this.Set)
 
 	if !this.Limit.IsEmpty() {
-		str += " "
-		str += "limit value=" + this.Limit.Value
+		str += " " + this.Limit.ToString()
 	}
-
-	str += " "
 	return str
 }
 
+// ToString renders the rule in firewalld's canonical rich language form, e.g.
+// rule family="ipv4" source address="10.0.0.0/8" port port="22" protocol="tcp" accept
 func (this *Rule) ToString() (ruleString string) {
-	ruleString = "rule "
+	if this.Raw != "" {
+		return this.Raw
+	}
+	var parts = []string{"rule"}
+
+	if this.Priority != 0 {
+		parts = append(parts, "priority="+quote(strconv.Itoa(this.Priority)))
+	}
+
 	if this.Family != "" {
-		ruleString += "family=" + this.Family
+		parts = append(parts, "family="+quote(this.Family))
 	}
 
 	if !this.Source.IsEmpty() {
-		ruleString += this.Source.ToString()
+		parts = append(parts, this.Source.ToString())
 	}
 
 	if !this.Destination.IsEmpty() {
-		ruleString += this.Destination.ToString()
+		parts = append(parts, this.Destination.ToString())
 	}
 
-	if !this.Service.IsEmpty() {
-		ruleString += this.Service.ToString()
+	// a rule carries at most one element.
+	switch {
+	case !this.Service.IsEmpty():
+		parts = append(parts, this.Service.ToString())
+	case !this.Port.IsEmpty():
+		parts = append(parts, this.Port.ToString())
+	case !this.Protocol.IsEmpty():
+		parts = append(parts, this.Protocol.ToString())
+	case !this.IcmpBlock.IsEmpty():
+		parts = append(parts, this.IcmpBlock.ToString())
+	case !this.IcmpType.IsEmpty():
+		parts = append(parts, this.IcmpType.ToString())
+	case !this.ForwardPort.IsEmpty():
+		parts = append(parts, this.ForwardPort.ToString())
+	case !this.SourcePort.IsEmpty():
+		parts = append(parts, this.SourcePort.ToString())
+	case this.Masquerade:
+		parts = append(parts, "masquerade")
+	case this.TcpMssClamp.Flag:
+		parts = append(parts, this.TcpMssClamp.ToString())
 	}
 
-	if !this.Port.IsEmpty() {
-		ruleString += this.Port.ToString()
+	if !this.Log.IsEmpty() {
+		parts = append(parts, this.Log.ToString())
 	}
 
-	if !this.Protocol.IsEmpty() {
-		ruleString += this.Protocol.ToString()
+	if !this.NfLog.IsEmpty() {
+		parts = append(parts, this.NfLog.ToString())
 	}
 
-	if !this.IcmpBlock.IsEmpty() {
-		ruleString += this.IcmpBlock.ToString()
+	if !this.Audit.IsEmpty() {
+		parts = append(parts, this.Audit.ToString())
 	}
 
-	if !this.IcmpType.IsEmpty() {
-		ruleString += this.IcmpType.ToString()
+	switch {
+	case !this.Accept.IsEmpty():
+		parts = append(parts, this.Accept.ToString())
+	case !this.Reject.IsEmpty():
+		parts = append(parts, this.Reject.ToString())
+	case !this.Drop.IsEmpty():
+		parts = append(parts, this.Drop.ToString())
+	case !this.Mark.IsEmpty():
+		parts = append(parts, this.Mark.ToString())
 	}
+	return strings.Join(parts, " ")
+}
 
-	if !this.ForwardPort.IsEmpty() {
-		ruleString += this.ForwardPort.ToString()
+// Action returns the terminal action of the rule: accept, reject, drop, mark,
+// or the empty string for log/audit only rules.
+func (this *Rule) Action() string {
+	switch {
+	case !this.Accept.IsEmpty():
+		return "accept"
+	case !this.Reject.IsEmpty():
+		return "reject"
+	case !this.Drop.IsEmpty():
+		return "drop"
+	case !this.Mark.IsEmpty():
+		return "mark"
 	}
+	return ""
+}
 
-	if !this.Log.IsEmpty() {
-		ruleString += this.Log.ToString()
+// StringToRule converts a rich language rule into Rule, a rule ParseRule does not understand is kept in Raw
+// so that writing it back does not change it.
+func StringToRule(str string) (rule *Rule) {
+	var err error
+	if rule, err = ParseRule(str); err != nil {
+		return &Rule{Raw: str}
 	}
+	return rule
+}
 
-	if !this.Audit.IsEmpty() {
-		ruleString += this.Audit.ToString()
+/*
+ * @title         ParseRule
+ * @description   parse a rich language rule, as returned by getRichRules, into Rule. Keywords and attribute
+ *                  names are case insensitive, e.g. NOT, values are kept as they are.
+ * @auth          author           2021-10-09
+ * @param         str              string         "e.g. rule family="ipv4" source address="10.0.0.0/8" service name="ssh" accept"
+ * @return        rule             *Rule          "The parsed rule, tokens before the first error are kept."
+ * @return        error            error          "Possible errors:
+ *                                                      INVALID_RULE"
+ */
+func ParseRule(str string) (rule *Rule, err error) {
+	rule = &Rule{}
+	var (
+		element string
+		limit   *Limit
+	)
+
+	for _, token := range splitRuleTokens(str) {
+		kv := strings.SplitN(token, "=", 2)
+		if len(kv) != 2 {
+			limit = nil
+			switch token = strings.ToLower(token); token {
+			case "rule", "source", "destination", "service", "port", "protocol", "icmp-block", "icmp-type",
+				"forward-port", "source-port", "mark":
+				element = token
+			// log, nflog, audit and reject are complete without attributes.
+			case "log":
+				element = token
+				rule.Log.Flag = true
+			case "nflog":
+				element = token
+				rule.NfLog.Flag = true
+			case "audit":
+				element = token
+				rule.Audit.Flag = true
+			case "reject":
+				element = token
+				rule.Reject.Flag = true
+			case "masquerade":
+				element = token
+				rule.Masquerade = true
+			case "tcp-mss-clamp":
+				element = token
+				rule.TcpMssClamp.Flag = true
+			case "accept":
+				element = token
+				rule.Accept.Flag = true
+			case "drop":
+				element = token
+				rule.Drop.Flag = true
+			case "not":
+				switch element {
+				case "source":
+					rule.Source.Invert = "true"
+				case "destination":
+					rule.Destination.Invert = "true"
+				default:
+					return rule, fmt.Errorf("INVALID_RULE: unexpected 'not' after '%s'", element)
+				}
+			case "limit":
+				switch element {
+				case "log":
+					limit = &rule.Log.Limit
+				case "nflog":
+					limit = &rule.NfLog.Limit
+				case "audit":
+					limit = &rule.Audit.Limit
+				case "accept":
+					limit = &rule.Accept.Limit
+				case "reject":
+					limit = &rule.Reject.Limit
+				case "drop":
+					limit = &rule.Drop.Limit
+				case "mark":
+					limit = &rule.Mark.Limit
+				default:
+					return rule, fmt.Errorf("INVALID_RULE: unexpected 'limit' after '%s'", element)
+				}
+			default:
+				return rule, fmt.Errorf("INVALID_RULE: unknown token '%s'", token)
+			}
+			continue
+		}
+
+		key, value := strings.ToLower(kv[0]), kv[1]
+		if limit != nil {
+			switch key {
+			case "value":
+				limit.Value = value
+				continue
+			case "burst":
+				limit.Burst = value
+				continue
+			}
+		}
+
+		switch element + "." + key {
+		case "rule.family":
+			rule.Family = value
+		case "rule.priority":
+			if rule.Priority, err = strconv.Atoi(value); err != nil {
+				return rule, fmt.Errorf("INVALID_PRIORITY: %s", value)
+			}
+		case "source.address":
+			rule.Source.Address = value
+		case "source.mac":
+			rule.Source.Mac = value
+		case "source.ipset":
+			rule.Source.Ipset = value
+		case "destination.address":
+			rule.Destination.Address = value
+		case "service.name":
+			rule.Service.Name = value
+		case "port.port":
+			rule.Port.Port = value
+		case "port.protocol":
+			rule.Port.Protocol = value
+		case "protocol.value":
+			rule.Protocol.Value = value
+		case "icmp-block.name":
+			rule.IcmpBlock.Name = value
+		case "icmp-type.name":
+			rule.IcmpType.Name = value
+		case "forward-port.port":
+			rule.ForwardPort.Port = value
+		case "forward-port.protocol":
+			rule.ForwardPort.Protocol = value
+		case "forward-port.to-port":
+			rule.ForwardPort.ToPort = value
+		case "forward-port.to-addr":
+			rule.ForwardPort.ToAddr = value
+		case "source-port.port":
+			rule.SourcePort.Port = value
+		case "source-port.protocol":
+			rule.SourcePort.Protocol = value
+		case "log.prefix":
+			rule.Log.Prefix = value
+		case "log.level":
+			rule.Log.Level = value
+		case "nflog.group":
+			rule.NfLog.Group = value
+		case "nflog.prefix":
+			rule.NfLog.Prefix = value
+		case "nflog.queue-size":
+			rule.NfLog.QueueSize = value
+		case "tcp-mss-clamp.value":
+			rule.TcpMssClamp.Value = value
+		case "reject.type":
+			rule.Reject.Type = value
+		case "mark.set":
+			rule.Mark.Set = value
+		default:
+			return rule, fmt.Errorf("INVALID_RULE: unknown attribute '%s' in '%s'", key, element)
+		}
 	}
+	return rule, nil
+}
 
-	if !this.Accept.IsEmpty() {
-		ruleString += this.Accept.ToString()
+// splitRuleTokens splits a rich rule on white spaces, quoted values are kept
+// together and the quotes are removed.
+func splitRuleTokens(str string) (tokens []string) {
+	var (
+		token  strings.Builder
+		quoted bool
+		inside bool
+	)
+	for _, r := range str {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inside = true
+		case (r == ' ' || r == '\t' || r == '\n') && !quoted:
+			if inside {
+				tokens = append(tokens, token.String())
+				token.Reset()
+				inside = false
+			}
+		default:
+			token.WriteRune(r)
+			inside = true
+		}
+	}
+	if inside {
+		tokens = append(tokens, token.String())
 	}
+	return tokens
+}
+
+func quote(value string) string {
+	return `"` + value + `"`
+}
+
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "", "false", "no", "0":
+		return false
+	}
+	return true
+}
+
+// zoneSettings is the D-Bus representation of Settings,
+// signature (sssbsasa(ss)asba(ssss)asasasasa(ss)b).
+type zoneSettings struct {
+	Version            string
+	Short              string
+	Description        string
+	Forward            bool
+	Target             string
+	Services           []string
+	Ports              []portTuple
+	IcmpBlocks         []string
+	Masquerade         bool
+	ForwardPorts       []forwardPortTuple
+	Interfaces         []string
+	Sources            []string
+	RichRules          []string
+	Protocols          []string
+	SourcePorts        []portTuple
+	IcmpBlockInversion bool
+}
+
+type portTuple struct {
+	Port     string
+	Protocol string
+}
+
+type forwardPortTuple struct {
+	Port     string
+	Protocol string
+	ToPort   string
+	ToAddr   string
+}
 
-	if !this.Reject.IsEmpty() {
-		ruleString += this.Reject.ToString()
+func (this *zoneSettings) toSettings() *Settings {
+	settings := &Settings{
+		Version:            this.Version,
+		Short:              this.Short,
+		Description:        this.Description,
+		Forward:            this.Forward,
+		Targe:              this.Target,
+		Service:            append([]string(nil), this.Services...),
+		Masquerade:         this.Masquerade,
+		IcmpBlockInversion: this.IcmpBlockInversion,
+	}
+	for _, value := range this.Ports {
+		settings.Port = append(settings.Port, Port{Port: value.Port, Protocol: value.Protocol})
+	}
+	for _, value := range this.IcmpBlocks {
+		settings.IcmpBlock = append(settings.IcmpBlock, IcmpBlock{Name: value})
+	}
+	for _, value := range this.ForwardPorts {
+		settings.ForwardPort = append(settings.ForwardPort, ForwardPort{
+			Port:     value.Port,
+			Protocol: value.Protocol,
+			ToPort:   value.ToPort,
+			ToAddr:   value.ToAddr,
+		})
+	}
+	for _, value := range this.Interfaces {
+		settings.Interface = append(settings.Interface, Interface{Name: value})
+	}
+	for _, value := range this.Sources {
+		settings.Source = append(settings.Source, stringToSource(value))
+	}
+	for _, value := range this.RichRules {
+		settings.Rule = append(settings.Rule, *StringToRule(value))
+	}
+	for _, value := range this.Protocols {
+		settings.Protocol = append(settings.Protocol, Protocol{Value: value})
 	}
+	for _, value := range this.SourcePorts {
+		settings.SourcePort = append(settings.SourcePort, SourcePort{Port: value.Port, Protocol: value.Protocol})
+	}
+	return settings
+}
 
-	if !this.Drop.IsEmpty() {
-		ruleString += this.Drop.ToString()
+func (this *Settings) toTuple() *zoneSettings {
+	settings := &zoneSettings{
+		Version:            this.Version,
+		Short:              this.Short,
+		Description:        this.Description,
+		Forward:            this.Forward,
+		Target:             this.Targe,
+		Services:           append([]string{}, this.Service...),
+		Ports:              []portTuple{},
+		IcmpBlocks:         []string{},
+		Masquerade:         this.Masquerade,
+		ForwardPorts:       []forwardPortTuple{},
+		Interfaces:         []string{},
+		Sources:            []string{},
+		RichRules:          []string{},
+		Protocols:          []string{},
+		SourcePorts:        []portTuple{},
+		IcmpBlockInversion: this.IcmpBlockInversion,
+	}
+	if settings.Target == "" {
+		settings.Target = "default"
+	}
+	for _, value := range this.Port {
+		settings.Ports = append(settings.Ports, portTuple{value.Port, value.Protocol})
+	}
+	for _, value := range this.IcmpBlock {
+		settings.IcmpBlocks = append(settings.IcmpBlocks, value.Name)
+	}
+	for _, value := range this.ForwardPort {
+		settings.ForwardPorts = append(settings.ForwardPorts, forwardPortTuple{value.Port, value.Protocol, value.ToPort, value.ToAddr})
+	}
+	for _, value := range this.Interface {
+		settings.Interfaces = append(settings.Interfaces, value.Name)
 	}
+	for _, value := range this.Source {
+		settings.Sources = append(settings.Sources, sourceString(value))
+	}
+	for _, value := range this.Rule {
+		settings.RichRules = append(settings.RichRules, value.ToString())
+	}
+	for _, value := range this.Protocol {
+		settings.Protocols = append(settings.Protocols, value.Value)
+	}
+	for _, value := range this.SourcePort {
+		settings.SourcePorts = append(settings.SourcePorts, portTuple{value.Port, value.Protocol})
+	}
+	return settings
+}
+
+// serviceSettings is the D-Bus representation of ServiceSettings, signature (sssa(ss)asa{ss}).
+type serviceSettings struct {
+	Version      string
+	Short        string
+	Description  string
+	Ports        []portTuple
+	Modules      []string
+	Destinations map[string]string
+}
 
-	if !this.Mark.IsEmpty() {
-		ruleString += this.Mark.ToString()
+func (this *serviceSettings) toServiceSettings() *ServiceSettings {
+	settings := &ServiceSettings{
+		Version:     this.Version,
+		Short:       this.Short,
+		Description: this.Description,
+		Module:      this.Modules,
+		Destination: this.Destinations,
+	}
+	for _, value := range this.Ports {
+		settings.Port = append(settings.Port, Port{Port: value.Port, Protocol: value.Protocol})
 	}
-	return
+	return settings
 }
 
-func stringToReject(slice []string) (reject Reject, ruleSlice []string) {
-Label:
-	for index, value := range slice {
-		tmp_slice := strings.Split(value, "=")
-		switch tmp_slice[1] {
-		case "type":
-			slice = removeSliceElement(slice, index)
-			reject.Type = slice[index]
-			slice = removeSliceElement(slice, index)
-			goto Label
-		case "limit":
-			slice = removeSliceElement(slice, index)
-			tmp_slice := strings.Split(slice[index], "=")
-			reject.Limit = Limit{Value: tmp_slice[1]}
-			slice = removeSliceElement(slice, index)
-			goto Label
-		}
+func (this *ServiceSettings) toTuple() *serviceSettings {
+	settings := &serviceSettings{
+		Version:      this.Version,
+		Short:        this.Short,
+		Description:  this.Description,
+		Ports:        []portTuple{},
+		Modules:      append([]string{}, this.Module...),
+		Destinations: map[string]string{},
 	}
-	ruleSlice = slice
-	return reject, ruleSlice
-}
-
-func stringToMark(slice []string) (mark Mark, ruleSlice []string) {
-
-Label:
-	for index, value := range slice {
-		tmp_slice := strings.Split(value, "=")
-		switch tmp_slice[0] {
-		case "set":
-			slice = removeSliceElement(slice, index)
-			mark.Set = tmp_slice[1]
-			goto Label
-		case "limit":
-			slice = removeSliceElement(slice, index)
-			tmp_slice := strings.Split(slice[index], "=")
-			mark.Limit = Limit{Value: tmp_slice[1]}
-			slice = removeSliceElement(slice, index)
-			goto Label
-		}
+	for _, value := range this.Port {
+		settings.Ports = append(settings.Ports, portTuple{value.Port, value.Protocol})
 	}
-	ruleSlice = slice
-	return
-}
-
-func stringToForwardPort(slice []string) (forwardPort ForwardPort, ruleSlice []string) {
-
-Label:
-	for index, value := range slice {
-		tmp_slice := strings.Split(value, "=")
-		switch tmp_slice[0] {
-		case "port":
-			slice = removeSliceElement(slice, index)
-			forwardPort.Port = tmp_slice[1]
-			goto Label
-		case "protocol":
-			slice = removeSliceElement(slice, index)
-			forwardPort.Protocol = tmp_slice[1]
-			goto Label
-		case "to-port":
-			slice = removeSliceElement(slice, index)
-			forwardPort.ToPort = tmp_slice[1]
-			goto Label
-		case "to-addr":
-			slice = removeSliceElement(slice, index)
-			forwardPort.ToAddr = tmp_slice[1]
-			goto Label
-		}
+	for key, value := range this.Destination {
+		settings.Destinations[key] = value
 	}
-	ruleSlice = slice
-	return
-}
-
-func stringToLog(slice []string) (log Log, ruleSlice []string) {
-
-Label:
-	for index, value := range slice {
-		tmp_slice := strings.Split(value, "=")
-		switch tmp_slice[0] {
-		case "prefix":
-			slice = removeSliceElement(slice, index)
-			log.Prefix = tmp_slice[1]
-			goto Label
-		case "level":
-			slice = removeSliceElement(slice, index)
-			log.Level = tmp_slice[1]
-			goto Label
-		case "limit":
-			slice = removeSliceElement(slice, index)
-			tmp_slice := strings.Split(slice[index], "=")
-			log.Limit = Limit{Value: tmp_slice[1]}
-			slice = removeSliceElement(slice, index)
-			goto Label
+	return settings
+}
+
+// toDict converts service settings into the a{sv} form of getSettings2/update2.
+func (this *ServiceSettings) toDict() map[string]dbus.Variant {
+	tuple := this.toTuple()
+	dict := map[string]dbus.Variant{
+		"version":      dbus.MakeVariant(tuple.Version),
+		"short":        dbus.MakeVariant(tuple.Short),
+		"description":  dbus.MakeVariant(tuple.Description),
+		"ports":        dbus.MakeVariant(tuple.Ports),
+		"module_names": dbus.MakeVariant(tuple.Modules),
+		"destination":  dbus.MakeVariant(tuple.Destinations),
+		"protocols":    dbus.MakeVariant(protocolStrings(this.Protocol)),
+		"source_ports": dbus.MakeVariant(sourcePortTuples(this.SourcePort)),
+		"includes":     dbus.MakeVariant(append([]string{}, this.Include...)),
+		"helpers":      dbus.MakeVariant(append([]string{}, this.Helper...)),
+	}
+	return dict
+}
+
+// serviceSettingsOfDict converts the a{sv} form of getSettings2 into ServiceSettings.
+func serviceSettingsOfDict(dict map[string]dbus.Variant) (settings *ServiceSettings, err error) {
+	var tuple serviceSettings
+	var protocols []string
+	var sourcePorts []portTuple
+	settings = &ServiceSettings{}
+	for key, dest := range map[string]interface{}{
+		"version":      &tuple.Version,
+		"short":        &tuple.Short,
+		"description":  &tuple.Description,
+		"ports":        &tuple.Ports,
+		"module_names": &tuple.Modules,
+		"destination":  &tuple.Destinations,
+		"protocols":    &protocols,
+		"source_ports": &sourcePorts,
+		"includes":     &settings.Include,
+		"helpers":      &settings.Helper,
+	} {
+		if err = storeDict(dict, key, dest); err != nil {
+			return nil, err
 		}
 	}
-	ruleSlice = slice
-	return
+	include, helper := settings.Include, settings.Helper
+	settings = tuple.toServiceSettings()
+	settings.Include, settings.Helper = include, helper
+	for _, value := range protocols {
+		settings.Protocol = append(settings.Protocol, Protocol{Value: value})
+	}
+	for _, value := range sourcePorts {
+		settings.SourcePort = append(settings.SourcePort, SourcePort{Port: value.Port, Protocol: value.Protocol})
+	}
+	return settings, nil
 }
 
-func StringToRule(str string) (rule *Rule) {
+// toDict converts policy settings into the a{sv} form of getPolicySettings/update.
+func (this *PolicySettings) toDict() map[string]dbus.Variant {
+	zone := (&Settings{
+		Service:     this.Service,
+		Port:        this.Port,
+		IcmpBlock:   this.IcmpBlock,
+		ForwardPort: this.ForwardPort,
+		Rule:        this.Rule,
+		Protocol:    this.Protocol,
+		SourcePort:  this.SourcePort,
+	}).toTuple()
+	target := this.Target
+	if target == "" {
+		target = "CONTINUE"
+	}
+	return map[string]dbus.Variant{
+		"version":       dbus.MakeVariant(this.Version),
+		"short":         dbus.MakeVariant(this.Short),
+		"description":   dbus.MakeVariant(this.Description),
+		"target":        dbus.MakeVariant(target),
+		"priority":      dbus.MakeVariant(int32(this.Priority)),
+		"ingress_zones": dbus.MakeVariant(append([]string{}, this.IngressZones...)),
+		"egress_zones":  dbus.MakeVariant(append([]string{}, this.EgressZones...)),
+		"services":      dbus.MakeVariant(zone.Services),
+		"ports":         dbus.MakeVariant(zone.Ports),
+		"icmp_blocks":   dbus.MakeVariant(zone.IcmpBlocks),
+		"masquerade":    dbus.MakeVariant(this.Masquerade),
+		"forward_ports": dbus.MakeVariant(zone.ForwardPorts),
+		"rich_rules":    dbus.MakeVariant(zone.RichRules),
+		"protocols":     dbus.MakeVariant(zone.Protocols),
+		"source_ports":  dbus.MakeVariant(zone.SourcePorts),
+	}
+}
 
-	strslice := strings.Split(str, " ")
-	rule = &Rule{}
-Label:
-	for index, value := range strslice {
-		switch value {
-		case "rule":
-			strslice = removeSliceElement(strslice, index)
-			tmp_str := strings.Split(strslice[index], "=")
-			rule.Family = tmp_str[1]
-			strslice = removeSliceElement(strslice, index)
-			goto Label
-		case "source":
-			strslice = removeSliceElement(strslice, index)
-			tmp_str := strings.Split(strslice[index], "=")
-			source := Source{}
-			switch tmp_str[0] {
-			case "address":
-				source.Address = tmp_str[1]
-			case "mac":
-				source.Mac = tmp_str[1]
-			case "ipset":
-				source.Ipset = tmp_str[1]
-			case "invert":
-				source.Invert = tmp_str[1]
-			}
-			rule.Source = source
-			strslice = removeSliceElement(strslice, index)
-			goto Label
-		case "destination":
-			strslice = removeSliceElement(strslice, index)
-			dst := Destination{}
-			tmp_str := strings.Split(strslice[index], "=")
-			switch tmp_str[0] {
-			case "address":
-				dst.Address = tmp_str[1]
-			case "invert":
-				dst.Invert = tmp_str[1]
-			}
-			rule.Destination = dst
-			strslice = removeSliceElement(strslice, index)
-			goto Label
-		case "service":
-			strslice = removeSliceElement(strslice, index)
-			tmp_str := strings.Split(strslice[index], "=")
-			rule.Service = Service{Name: tmp_str[1]}
-			strslice = removeSliceElement(strslice, index)
-			goto Label
-		case "port":
-			strslice = removeSliceElement(strslice, index)
-			port := strings.Split(strslice[index], "=")
-			protocol := strings.Split(strslice[index+1], "=")
-			rule.Port = Port{
-				Port:     port[1],
-				Protocol: protocol[1],
-			}
-			strslice = removeSliceElement(strslice, index)
-			goto Label
-		case "protocol":
-			strslice = removeSliceElement(strslice, index)
-			tmp_str := strings.Split(strslice[index+1], "=")
-			rule.Protocol = Protocol{Value: tmp_str[1]}
-			strslice = removeSliceElement(strslice, index)
-			goto Label
-		case "icmp-block":
-			strslice = removeSliceElement(strslice, index)
-			tmp_str := strings.Split(strslice[index+1], "=")
-			rule.IcmpBlock = IcmpBlock{Name: tmp_str[1]}
-			strslice = removeSliceElement(strslice, index)
-			goto Label
-		case "icmp-type":
-			strslice = removeSliceElement(strslice, index)
-			tmp_str := strings.Split(strslice[index+1], "=")
-			rule.IcmpType = IcmpType{Name: tmp_str[1]}
-			strslice = removeSliceElement(strslice, index)
-			goto Label
-		case "forward-port":
-			strslice = removeSliceElement(strslice, index)
-			rule.ForwardPort, strslice = stringToForwardPort(strslice)
-			goto Label
-		case "log":
-			strslice = removeSliceElement(strslice, index)
-			rule.Log, strslice = stringToLog(strslice)
-			goto Label
-		case "audit":
-			strslice = removeSliceElement(strslice, index)
-			tmp_str := strings.Split(strslice[index], "=")
-			rule.Audit = Audit{Limit: Limit{Value: tmp_str[1]}}
-			strslice = removeSliceElement(strslice, index)
-			goto Label
-		case "accept":
-			strslice = removeSliceElement(strslice, index)
-			rule.Accept.Flag = true
-			var tmp_str []string
-			if len(strslice) > 0 {
-				if strslice[index] == "limit" {
-					strslice = removeSliceElement(strslice, index)
-					tmp_str = strings.Split(strslice[index], "=")
-					rule.Accept.Limit = Limit{Value: tmp_str[1]}
-				}
-			}
-			goto Label
-		case "drop":
-			var tmp_str []string
-			strslice = removeSliceElement(strslice, index)
-			rule.Drop.Flag = true
-			if len(strslice) > 0 {
-				if strslice[index] == "limit" {
-					strslice = removeSliceElement(strslice, index)
-					tmp_str = strings.Split(strslice[index], "=")
-					rule.Drop.Limit = Limit{Value: tmp_str[1]}
-				}
-			}
-			goto Label
-		case "reject":
-			strslice = removeSliceElement(strslice, index)
-			rule.Reject, strslice = stringToReject(strslice)
-			goto Label
-		case "mark":
-			strslice = removeSliceElement(strslice, index)
-			rule.Mark, strslice = stringToMark(strslice)
-			goto Label
+// policySettingsOfDict converts the a{sv} form of getPolicySettings into PolicySettings.
+func policySettingsOfDict(dict map[string]dbus.Variant) (settings *PolicySettings, err error) {
+	var zone zoneSettings
+	var priority int32
+	settings = &PolicySettings{}
+	for key, dest := range map[string]interface{}{
+		"version":       &settings.Version,
+		"short":         &settings.Short,
+		"description":   &settings.Description,
+		"target":        &settings.Target,
+		"priority":      &priority,
+		"ingress_zones": &settings.IngressZones,
+		"egress_zones":  &settings.EgressZones,
+		"services":      &zone.Services,
+		"ports":         &zone.Ports,
+		"icmp_blocks":   &zone.IcmpBlocks,
+		"masquerade":    &settings.Masquerade,
+		"forward_ports": &zone.ForwardPorts,
+		"rich_rules":    &zone.RichRules,
+		"protocols":     &zone.Protocols,
+		"source_ports":  &zone.SourcePorts,
+	} {
+		if err = storeDict(dict, key, dest); err != nil {
+			return nil, err
 		}
 	}
-	return rule
+	converted := zone.toSettings()
+	settings.Priority = int(priority)
+	settings.Service = converted.Service
+	settings.Port = converted.Port
+	settings.IcmpBlock = converted.IcmpBlock
+	settings.ForwardPort = converted.ForwardPort
+	settings.Rule = converted.Rule
+	settings.Protocol = converted.Protocol
+	settings.SourcePort = converted.SourcePort
+	return settings, nil
+}
+
+// storeDict stores the value of key into dest, a missing key leaves dest unchanged.
+func storeDict(dict map[string]dbus.Variant, key string, dest interface{}) error {
+	value, ok := dict[key]
+	if !ok {
+		return nil
+	}
+	if err := dbus.Store([]interface{}{value.Value()}, dest); err != nil {
+		return fmt.Errorf("%s: %v", key, err)
+	}
+	return nil
+}
+
+func protocolStrings(protocols []Protocol) []string {
+	list := []string{}
+	for _, value := range protocols {
+		list = append(list, value.Value)
+	}
+	return list
+}
+
+func sourcePortTuples(ports []SourcePort) []portTuple {
+	list := []portTuple{}
+	for _, value := range ports {
+		list = append(list, portTuple{value.Port, value.Protocol})
+	}
+	return list
+}
+
+// directSettings is the D-Bus representation of DirectSettings, signature (a(sss)a(sssias)a(sas)).
+type directSettings struct {
+	Chains       []directChain
+	Rules        []directRule
+	Passthroughs []directPassthrough
+}
+
+type directChain struct {
+	IPV   string
+	Table string
+	Chain string
+}
+
+type directRule struct {
+	IPV      string
+	Table    string
+	Chain    string
+	Priority int32
+	Args     []string
+}
+
+type directPassthrough struct {
+	IPV  string
+	Args []string
+}
+
+func (this *directSettings) toDirectSettings() *DirectSettings {
+	settings := &DirectSettings{}
+	for _, value := range this.Chains {
+		settings.Chain = append(settings.Chain, DirectChain{value.IPV, value.Table, value.Chain})
+	}
+	for _, value := range this.Rules {
+		settings.Rule = append(settings.Rule, DirectRule{value.IPV, value.Table, value.Chain, int(value.Priority), value.Args})
+	}
+	for _, value := range this.Passthroughs {
+		settings.Passthrough = append(settings.Passthrough, DirectPassthrough{value.IPV, value.Args})
+	}
+	return settings
+}
+
+func (this *DirectSettings) toTuple() *directSettings {
+	settings := &directSettings{
+		Chains:       []directChain{},
+		Rules:        []directRule{},
+		Passthroughs: []directPassthrough{},
+	}
+	for _, value := range this.Chain {
+		settings.Chains = append(settings.Chains, directChain{value.IPV, value.Table, value.Chain})
+	}
+	for _, value := range this.Rule {
+		settings.Rules = append(settings.Rules, directRule{value.IPV, value.Table, value.Chain, int32(value.Priority), append([]string{}, value.Args...)})
+	}
+	for _, value := range this.Passthrough {
+		settings.Passthroughs = append(settings.Passthroughs, directPassthrough{value.IPV, append([]string{}, value.Args...)})
+	}
+	return settings
+}
+
+// ipsetSettings is the D-Bus representation of IPSetSettings, signature (ssssa{ss}as).
+type ipsetSettings struct {
+	Version     string
+	Short       string
+	Description string
+	Type        string
+	Options     map[string]string
+	Entries     []string
+}
+
+func (this *ipsetSettings) toIPSetSettings() *IPSetSettings {
+	return &IPSetSettings{
+		Version:     this.Version,
+		Short:       this.Short,
+		Description: this.Description,
+		Type:        this.Type,
+		Option:      this.Options,
+		Entry:       this.Entries,
+	}
+}
+
+func (this *IPSetSettings) toTuple() *ipsetSettings {
+	settings := &ipsetSettings{
+		Version:     this.Version,
+		Short:       this.Short,
+		Description: this.Description,
+		Type:        this.Type,
+		Options:     this.Option,
+		Entries:     this.Entry,
+	}
+	if settings.Options == nil {
+		settings.Options = map[string]string{}
+	}
+	if settings.Entries == nil {
+		settings.Entries = []string{}
+	}
+	return settings
+}
+
+// stringToSource converts a zone source, an address, a mac or "ipset:name", into Source.
+func stringToSource(str string) Source {
+	if strings.HasPrefix(str, "ipset:") {
+		return Source{Ipset: strings.TrimPrefix(str, "ipset:")}
+	}
+	if _, err := net.ParseMAC(str); err == nil {
+		return Source{Mac: str}
+	}
+	return Source{Address: str}
 }
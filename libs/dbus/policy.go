@@ -0,0 +1,187 @@
+package dbus
+
+import (
+	"errors"
+
+	"github.com/cylonchau/gofirewallder/object"
+	"github.com/godbus/dbus/v5"
+)
+
+/************************************************** policy area ***********************************************************/
+
+// @title         GetPolicies
+// @description   Return list of policy names of the runtime configuration, firewalld >= 0.9.
+// @auth      	  author           2021-10-15
+// @return        policies         []string       "policy names, e.g. allow-host-ipv6"
+// @return        error            error          ""
+func (c *DbusClientSerivce) GetPolicies() (policies []string, err error) {
+	c, end := c.span("GetPolicies", SCOPE_RUNTIME)
+	defer end(&err)
+	obj := c.object(object.PATH)
+	call := obj.Call(object.POLICY_GETPOLICIES, dbus.FlagNoAutoStart)
+
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	return call.Body[0].([]string), nil
+}
+
+// @title         GetPolicySettings
+// @description   Return runtime settings of given policy.
+// @auth      	  author           2021-10-15
+// @param         policy           string          "policy name."
+// @return        settings         *PolicySettings "runtime settings of policy."
+// @return        error            error           "Possible errors: INVALID_POLICY"
+func (c *DbusClientSerivce) GetPolicySettings(policy string) (settings *PolicySettings, err error) {
+	c, end := c.span("GetPolicySettings", SCOPE_RUNTIME)
+	defer end(&err)
+	obj := c.object(object.PATH)
+	call := obj.Call(object.POLICY_GETPOLICYSETTINGS, dbus.FlagNoAutoStart, policy)
+
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	var dict map[string]dbus.Variant
+	if err = call.Store(&dict); err != nil {
+		return nil, err
+	}
+	return policySettingsOfDict(dict)
+}
+
+// @title         SetPolicySettings
+// @description   temporary Replace the runtime settings of given policy.
+// @auth      	  author           2021-10-15
+// @param         policy           string          "policy name."
+// @param         settings         *PolicySettings "the complete settings, e.g. returned by GetPolicySettings and modified."
+// @return        error            error           "Possible errors: INVALID_POLICY, INVALID_ZONE"
+func (c *DbusClientSerivce) SetPolicySettings(policy string, settings *PolicySettings) (err error) {
+	c, end := c.span("SetPolicySettings", SCOPE_RUNTIME)
+	defer end(&err)
+	obj := c.object(object.PATH)
+	call := obj.Call(object.POLICY_SETPOLICYSETTINGS, dbus.FlagNoAutoStart, policy, settings.toDict())
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+// @title         PermanentGetPolicies
+// @description   Return list of policy names of the permanent configuration, firewalld >= 0.9.
+// @auth      	  author           2021-10-15
+// @return        policies         []string       "policy names."
+// @return        error            error          ""
+func (c *DbusClientSerivce) PermanentGetPolicies() (policies []string, err error) {
+	c, end := c.span("PermanentGetPolicies", SCOPE_PERMANENT)
+	defer end(&err)
+	obj := c.object(object.CONFIG_PATH)
+	call := obj.Call(object.CONFIG_GETPOLICYNAMES, dbus.FlagNoAutoStart)
+
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	return call.Body[0].([]string), nil
+}
+
+// @title         PermanentGetPolicySettings
+// @description   Return permanent settings of given policy.
+// @auth      	  author           2021-10-15
+// @param         policy           string          "policy name."
+// @return        settings         *PolicySettings "permanent settings of policy."
+// @return        error            error           "Possible errors: INVALID_POLICY"
+func (c *DbusClientSerivce) PermanentGetPolicySettings(policy string) (settings *PolicySettings, err error) {
+	c, end := c.span("PermanentGetPolicySettings", SCOPE_PERMANENT)
+	defer end(&err)
+	var path dbus.ObjectPath
+	if path, err = c.policyPath(policy); err != nil {
+		return nil, err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_POLICY_GETSETTINGS, dbus.FlagNoAutoStart)
+
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	var dict map[string]dbus.Variant
+	if err = call.Store(&dict); err != nil {
+		return nil, err
+	}
+	return policySettingsOfDict(dict)
+}
+
+// @title         PermanentSetPolicySettings
+// @description   Replace the permanent settings of given policy in a single update.
+// @auth      	  author           2021-10-15
+// @param         policy           string          "policy name."
+// @param         settings         *PolicySettings "the complete settings."
+// @return        error            error           "Possible errors: INVALID_POLICY, INVALID_ZONE"
+func (c *DbusClientSerivce) PermanentSetPolicySettings(policy string, settings *PolicySettings) (err error) {
+	c, end := c.span("PermanentSetPolicySettings", SCOPE_PERMANENT)
+	defer end(&err)
+	var path dbus.ObjectPath
+	if path, err = c.policyPath(policy); err != nil {
+		return err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_POLICY_UPDATE, dbus.FlagNoAutoStart, settings.toDict())
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+// @title         PermanentAddPolicy
+// @description   Add policy with given settings into permanent configuration, it is available in runtime after Reload.
+// @auth      	  author           2021-10-15
+// @param         policy           string          "policy name."
+// @param         settings         *PolicySettings "e.g. ingress zones, egress zones and target."
+// @return        error            error           "Possible errors: NAME_CONFLICT, INVALID_NAME, INVALID_ZONE"
+func (c *DbusClientSerivce) PermanentAddPolicy(policy string, settings *PolicySettings) (err error) {
+	c, end := c.span("PermanentAddPolicy", SCOPE_PERMANENT)
+	defer end(&err)
+	obj := c.object(object.CONFIG_PATH)
+	call := obj.Call(object.CONFIG_ADDPOLICY, dbus.FlagNoAutoStart, policy, settings.toDict())
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+// @title         PermanentRemovePolicy
+// @description   Remove policy from permanent configuration.
+// @auth      	  author           2021-10-15
+// @param         policy           string         "policy name."
+// @return        error            error          "Possible errors: INVALID_POLICY, BUILTIN_POLICY"
+func (c *DbusClientSerivce) PermanentRemovePolicy(policy string) (err error) {
+	c, end := c.span("PermanentRemovePolicy", SCOPE_PERMANENT)
+	defer end(&err)
+	var path dbus.ObjectPath
+	if path, err = c.policyPath(policy); err != nil {
+		return err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_POLICY_REMOVE, dbus.FlagNoAutoStart)
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+// policyPath returns the object path of the permanent policy.
+func (c *DbusClientSerivce) policyPath(policy string) (path dbus.ObjectPath, err error) {
+	c, end := c.span("policyPath", 0)
+	defer end(&err)
+	obj := c.object(object.CONFIG_PATH)
+	call := obj.Call(object.CONFIG_GETPOLICYBYNAME, dbus.FlagNoAutoStart, policy)
+
+	if call.Err != nil {
+		return "", call.Err
+	}
+	if len(call.Body) <= 0 {
+		return "", errors.New("invalid policy.")
+	}
+	return call.Body[0].(dbus.ObjectPath), nil
+}
@@ -0,0 +1,263 @@
+package dbus
+
+import (
+	"fmt"
+)
+
+// Scope selects the configuration a Zone method works on.
+type Scope uint8
+
+const (
+	SCOPE_RUNTIME Scope = 1 << iota
+	SCOPE_PERMANENT
+	SCOPE_BOTH = SCOPE_RUNTIME | SCOPE_PERMANENT
+)
+
+func (s Scope) String() string {
+	switch s {
+	case SCOPE_RUNTIME:
+		return "runtime"
+	case SCOPE_PERMANENT:
+		return "permanent"
+	case SCOPE_BOTH:
+		return "both"
+	}
+	return fmt.Sprintf("scope(%d)", uint8(s))
+}
+
+// permanents returns the Operation.Permanent values of scope, runtime first.
+func (s Scope) permanents() (permanents []bool) {
+	if s&SCOPE_RUNTIME != 0 {
+		permanents = append(permanents, false)
+	}
+	if s&SCOPE_PERMANENT != 0 {
+		permanents = append(permanents, true)
+	}
+	return permanents
+}
+
+/*
+ * Zone is the API of one zone taking a Scope instead of runtime and Permanent method pairs, e.g.
+ *
+ *   public := client.Zone("public")
+ *   err := public.AddPort(SCOPE_BOTH, "8080/tcp", 0)
+ *   ok, err := public.QueryService(SCOPE_PERMANENT, "ssh")
+ *
+ * Changes return only an error, queries return (bool, error).
+ * With SCOPE_BOTH a change is skipped in the configuration that already has it, the other one is still changed,
+ * if the second change fails the first one is undone. A query is true if the item is in both.
+ * The timeout of a change only applies to runtime.
+ */
+type Zone struct {
+	Name string
+
+	client Firewall
+}
+
+// Zone returns the scoped API of zone, the empty string is usage default zone.
+func (c *DbusClientSerivce) Zone(zone string) *Zone {
+	return &Zone{Name: zone, client: c}
+}
+
+func (z *Zone) AddPort(scope Scope, port string, timeout int) error {
+	return z.change(scope, Operation{Action: OP_ADD, Kind: KIND_PORT, Value: port, Timeout: timeout})
+}
+
+func (z *Zone) RemovePort(scope Scope, port string) error {
+	return z.change(scope, Operation{Action: OP_REMOVE, Kind: KIND_PORT, Value: port})
+}
+
+func (z *Zone) QueryPort(scope Scope, port string) (bool, error) {
+	return z.query(scope, Operation{Kind: KIND_PORT, Value: port})
+}
+
+func (z *Zone) AddService(scope Scope, service string, timeout int) error {
+	return z.change(scope, Operation{Action: OP_ADD, Kind: KIND_SERVICE, Value: service, Timeout: timeout})
+}
+
+func (z *Zone) RemoveService(scope Scope, service string) error {
+	return z.change(scope, Operation{Action: OP_REMOVE, Kind: KIND_SERVICE, Value: service})
+}
+
+func (z *Zone) QueryService(scope Scope, service string) (bool, error) {
+	return z.query(scope, Operation{Kind: KIND_SERVICE, Value: service})
+}
+
+func (z *Zone) AddSource(scope Scope, source string) error {
+	return z.change(scope, Operation{Action: OP_ADD, Kind: KIND_SOURCE, Value: source})
+}
+
+func (z *Zone) RemoveSource(scope Scope, source string) error {
+	return z.change(scope, Operation{Action: OP_REMOVE, Kind: KIND_SOURCE, Value: source})
+}
+
+func (z *Zone) QuerySource(scope Scope, source string) (bool, error) {
+	return z.query(scope, Operation{Kind: KIND_SOURCE, Value: source})
+}
+
+func (z *Zone) BindInterface(scope Scope, iface string) error {
+	return z.change(scope, Operation{Action: OP_ADD, Kind: KIND_INTERFACE, Value: iface})
+}
+
+func (z *Zone) RemoveInterface(scope Scope, iface string) error {
+	return z.change(scope, Operation{Action: OP_REMOVE, Kind: KIND_INTERFACE, Value: iface})
+}
+
+func (z *Zone) QueryInterface(scope Scope, iface string) (bool, error) {
+	return z.query(scope, Operation{Kind: KIND_INTERFACE, Value: iface})
+}
+
+// AddForwardPort forwards portProtocol, e.g. 80/tcp, to toHostPort, e.g. 10.0.0.2:8080 or :8080 for a local port.
+func (z *Zone) AddForwardPort(scope Scope, portProtocol, toHostPort string, timeout int) error {
+	op := forwardPortOperation(OP_ADD, z.Name, portProtocol, toHostPort, false)
+	op.Timeout = timeout
+	return z.change(scope, op)
+}
+
+func (z *Zone) RemoveForwardPort(scope Scope, portProtocol, toHostPort string) error {
+	return z.change(scope, forwardPortOperation(OP_REMOVE, z.Name, portProtocol, toHostPort, false))
+}
+
+func (z *Zone) QueryForwardPort(scope Scope, portProtocol, toHostPort string) (bool, error) {
+	return z.query(scope, forwardPortOperation("", z.Name, portProtocol, toHostPort, false))
+}
+
+func (z *Zone) AddRichRule(scope Scope, rule *Rule, timeout int) error {
+	return z.change(scope, Operation{Action: OP_ADD, Kind: KIND_RICHRULE, Value: rule.ToString(), Timeout: timeout})
+}
+
+func (z *Zone) RemoveRichRule(scope Scope, rule *Rule) error {
+	return z.change(scope, Operation{Action: OP_REMOVE, Kind: KIND_RICHRULE, Value: rule.ToString()})
+}
+
+func (z *Zone) QueryRichRule(scope Scope, rule *Rule) (bool, error) {
+	return z.query(scope, Operation{Kind: KIND_RICHRULE, Value: rule.ToString()})
+}
+
+func (z *Zone) EnableMasquerade(scope Scope, timeout int) error {
+	return z.change(scope, Operation{Action: OP_ADD, Kind: KIND_MASQUERADE, Timeout: timeout})
+}
+
+func (z *Zone) DisableMasquerade(scope Scope) error {
+	return z.change(scope, Operation{Action: OP_REMOVE, Kind: KIND_MASQUERADE})
+}
+
+func (z *Zone) QueryMasquerade(scope Scope) (bool, error) {
+	return z.query(scope, Operation{Kind: KIND_MASQUERADE})
+}
+
+/*
+ * @title         change
+ * @description   execute op in each configuration of scope, with SCOPE_BOTH as a Tx.
+ * @auth          author           2021-10-16
+ * @param         scope            Scope          ""
+ * @param         op               Operation      "Permanent is set per configuration."
+ * @return        error            error          "Possible errors: the errors of the called methods, INVALID_COMMAND"
+ */
+func (z *Zone) change(scope Scope, op Operation) (err error) {
+	op.Zone = z.Name
+	permanents := scope.permanents()
+	if len(permanents) == 0 {
+		return fmt.Errorf("INVALID_COMMAND: unknown scope %s", scope)
+	}
+
+	var ops []Operation
+	for _, permanent := range permanents {
+		op.Permanent = permanent
+		if len(permanents) > 1 {
+			var present bool
+			if present, err = z.query(scopeOf(permanent), op); err != nil {
+				return err
+			}
+			if present == (op.Action == OP_ADD) {
+				continue
+			}
+		}
+		ops = append(ops, op)
+	}
+
+	switch len(ops) {
+	case 0:
+		if op.Action == OP_ADD {
+			return fmt.Errorf("ALREADY_ENABLED: %s", op.String())
+		}
+		return fmt.Errorf("NOT_ENABLED: %s", op.String())
+	case 1:
+		return z.client.Execute(ops[0])
+	}
+	return z.client.NewTx(ops...).Commit()
+}
+
+// query reports whether op.Value is in each configuration of scope, read from the zone settings.
+func (z *Zone) query(scope Scope, op Operation) (b bool, err error) {
+	permanents := scope.permanents()
+	if len(permanents) == 0 {
+		return false, fmt.Errorf("INVALID_COMMAND: unknown scope %s", scope)
+	}
+	value, err := op.normalizedValue()
+	if err != nil {
+		return false, err
+	}
+
+	for _, permanent := range permanents {
+		var settings *Settings
+		if permanent {
+			settings, err = z.client.PermanentGetZoneSettings(z.Name)
+		} else {
+			settings, err = z.client.GetZoneSettings(z.Name)
+		}
+		if err != nil {
+			return false, err
+		}
+
+		state := zoneStateOf(settings)
+		var found bool
+		switch op.Kind {
+		case KIND_PORT:
+			found = contains(state.Ports, value)
+		case KIND_SERVICE:
+			found = contains(state.Services, value)
+		case KIND_SOURCE:
+			found = contains(state.Sources, value)
+		case KIND_INTERFACE:
+			found = contains(state.Interfaces, value)
+		case KIND_FORWARDPORT:
+			found = contains(state.ForwardPorts, value)
+		case KIND_RICHRULE:
+			found = contains(state.RichRules, value)
+		case KIND_MASQUERADE:
+			found = settings.Masquerade
+		default:
+			return false, fmt.Errorf("INVALID_COMMAND: can not query kind '%s'", op.Kind)
+		}
+		if !found {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// normalizedValue returns op.Value in the form of zoneStateOf, e.g. 80 is 80/tcp.
+func (op Operation) normalizedValue() (value string, err error) {
+	switch op.Kind {
+	case KIND_PORT:
+		port, protocol := splitPortProtocol(op.Value)
+		return port + "/" + protocol, nil
+	case KIND_FORWARDPORT:
+		var forward ForwardPort
+		if forward, err = StringToForwardPort(op.Value); err != nil {
+			return "", err
+		}
+		return ForwardPortToString(forward), nil
+	case KIND_RICHRULE:
+		return StringToRule(op.Value).ToString(), nil
+	}
+	return op.Value, nil
+}
+
+func scopeOf(permanent bool) Scope {
+	if permanent {
+		return SCOPE_PERMANENT
+	}
+	return SCOPE_RUNTIME
+}
@@ -1,72 +1,245 @@
 package dbus
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
-	"sort"
-	"sync"
 
 	"github.com/godbus/dbus/v5"
 	"github.com/cylonchau/gofirewallder/object"
 )
 
 var (
-	dbusClient          *DbusClientSerivce
-	remotelyBusLck      sync.Mutex
 	PORT                = 55557
 	DEFAULT_ZONE_TARGET = "{chain}_{zone}"
+	// SYSTEM_BUS_ADDR is the Addr of the clients of NewDbusClientServiceSystemBus.
+	SYSTEM_BUS_ADDR = "system"
 )
 
+// the states of firewalld, see GetState.
+const (
+	STATE_INIT    = "INIT"
+	STATE_RUNNING = "RUNNING"
+	STATE_FAILED  = "FAILED"
+)
+
+/*
+ * DbusClientSerivce is a client of firewalld on one host, each method talking to firewalld is a span, see span.
+ *   Guard         refuses changes blocking the management connection, see checkLockout, NewGuard by default,
+ *                 nil disables it, Unguarded overrides it for one client.
+ *   DryRun        records the changes instead of sending them, reads are still sent, see DryRun, nil sends everything.
+ *   Interceptors  wrap every D-Bus call of the client, the first one is the outermost, see Interceptor.
+ *   ctx           the context of the calls, see WithContext.
+ *   defaultZone   shared with the copies of the client, e.g. of WithContext, so they see a SetDefaultZone.
+ *   cache         zone lookups kept between calls, shared with the copies of the client, see EnableCache.
+ */
 type DbusClientSerivce struct {
-	Conn      *dbus.Conn
-	defaultZone string
+	Conn         *dbus.Conn
+	Guard        *Guard
+	DryRun       *DryRun
+	Interceptors []Interceptor
+	ctx          context.Context
+	defaultZone  *string
+	addr         string
+	localAddr    net.Addr
+	remoteAddr   net.Addr
+	unguarded    bool
+	cache        *cacheSlot
 }
 
+// NewDbusClientService connects to dbus-daemon of a host listening on tcp with ANONYMOUS auth, the agent of
+// NewDbusClientServiceTLS does not need that.
 func NewDbusClientService(addr string) (*DbusClientSerivce, error) {
-	host, port, err := net.SplitHostPort(addr)
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, err
+	}
+
+	transport, err := net.Dial("tcp", addr)
 	if err != nil {
 		return nil, err
 	}
+	return NewDbusClientServiceOf(transport, addr)
+}
 
-	remotelyBusLck.Lock()
-	defer remotelyBusLck.Unlock()
-	if dbusClient != nil && dbusClient.Conn.Connected() {
-		return dbusClient, nil
+/*
+ * @title         NewDbusClientServiceTLS
+ * @description   connect to the agent of a host, see libs/agent, firewalld is not exposed on tcp then.
+ * @auth          author           2021-10-18
+ * @param         addr             string         "host:port of the agent."
+ * @param         config           *tls.Config    "the client certificate and the CA of the agent, mTLS."
+ * @return        client           *DbusClientSerivce ""
+ * @return        error            error          "the TLS handshake or the D-Bus authentication failed."
+ */
+func NewDbusClientServiceTLS(addr string, config *tls.Config) (*DbusClientSerivce, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, err
 	}
-	conn, err := dbus.Connect("tcp:host="+host+",port="+port, dbus.WithAuth(dbus.AuthAnonymous()))
+
+	transport, err := tls.Dial("tcp", addr, config)
 	if err != nil {
 		return nil, err
 	}
+	return NewDbusClientServiceOf(transport, addr)
+}
 
-	obj := conn.Object(object.INTERFACE, object.SERVICE)
+// NewDbusClientServiceContext is NewDbusClientService giving up when ctx is done, e.g. for fleet.Executor.Dial.
+func NewDbusClientServiceContext(ctx context.Context, addr string) (*DbusClientSerivce, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, err
+	}
+
+	var dialer net.Dialer
+	transport, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return newDbusClientServiceContext(ctx, transport, addr)
+}
+
+// NewDbusClientServiceTLSContext is NewDbusClientServiceTLS giving up when ctx is done.
+func NewDbusClientServiceTLSContext(ctx context.Context, addr string, config *tls.Config) (*DbusClientSerivce, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, err
+	}
+
+	dialer := tls.Dialer{Config: config}
+	transport, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return newDbusClientServiceContext(ctx, transport, addr)
+}
+
+// TLSDialer returns a dialer connecting with config, e.g. for fleet.Executor.Dial.
+func TLSDialer(config *tls.Config) func(ctx context.Context, addr string) (*DbusClientSerivce, error) {
+	return func(ctx context.Context, addr string) (*DbusClientSerivce, error) {
+		return NewDbusClientServiceTLSContext(ctx, addr, config)
+	}
+}
+
+// newDbusClientServiceContext runs the D-Bus handshake over transport, the transport is closed if ctx is done first.
+func newDbusClientServiceContext(ctx context.Context, transport net.Conn, addr string) (*DbusClientSerivce, error) {
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			transport.Close()
+		case <-stop:
+		}
+	}()
+	client, err := NewDbusClientServiceOf(transport, addr)
+	close(stop)
+	if ctx.Err() != nil {
+		if err == nil {
+			client.Close()
+		}
+		return nil, ctx.Err()
+	}
+	return client, err
+}
+
+// NewDbusClientServiceOf runs D-Bus over an open transport to addr, the transport is closed if it fails.
+func NewDbusClientServiceOf(transport net.Conn, addr string) (*DbusClientSerivce, error) {
+	conn, err := dbus.NewConn(transport)
+	if err != nil {
+		transport.Close()
+		return nil, err
+	}
+	if err = conn.Auth([]dbus.Auth{dbus.AuthAnonymous()}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err = conn.Hello(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return newDbusClientService(conn, addr, transport.LocalAddr(), transport.RemoteAddr())
+}
+
+/*
+ * @title         NewDbusClientServiceSystemBus
+ * @description   connect to firewalld on the system bus of the local host with EXTERNAL auth, e.g. for the
+ *                  stand-in of libs/confirm, which must own its name on the bus of firewalld.
+ * @auth          author           2021-10-18
+ * @return        client           *DbusClientSerivce "its Addr is SYSTEM_BUS_ADDR."
+ * @return        error            error          "the bus can not be reached or denies the connection."
+ */
+func NewDbusClientServiceSystemBus() (*DbusClientSerivce, error) {
+	conn, err := dbus.SystemBusPrivate()
+	if err != nil {
+		return nil, err
+	}
+	if err = conn.Auth(nil); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err = conn.Hello(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return newDbusClientService(conn, SYSTEM_BUS_ADDR, nil, nil)
+}
+
+// newDbusClientService reads the default zone over the authenticated conn, local and remote are nil but on tcp.
+func newDbusClientService(conn *dbus.Conn, addr string, local, remote net.Addr) (*DbusClientSerivce, error) {
+	client := &DbusClientSerivce{
+		Conn:        conn,
+		Guard:       NewGuard(),
+		defaultZone: new(string),
+		cache:       &cacheSlot{},
+		addr:        addr,
+		localAddr:   local,
+		remoteAddr:  remote,
+	}
+	obj := client.object(object.SERVICE)
 	call := obj.Call(object.INTERFACE_GETDEFAULTZONE, dbus.FlagNoAutoStart)
 
 	if call.Err != nil {
+		conn.Close()
 		return nil, call.Err
 	}
-
-	return &DbusClientSerivce{
-		conn,
-		call.Body[0].(string),
-	}, err
+	*client.defaultZone = call.Body[0].(string)
+	return client, nil
 }
 
-// @title         Reload
-// @description   temporary Add rich language rule into zone.
-// @auth      	  author           2021-10-05
-// @return        error            error          "Possible errors: ALREADY_ENABLED"
-func (c *DbusClientSerivce) generatePath(zone, interface_path string) (path dbus.ObjectPath, err error) {
+// Addr returns the host:port the client is connected to.
+func (c *DbusClientSerivce) Addr() string {
+	return c.addr
+}
 
-	zoneid := c.getZoneId(zone)
-	if zoneid < 0 {
-		return "", errors.New("invalid zone.")
-	}
-	return dbus.ObjectPath(fmt.Sprintf("%s/%d", interface_path, zoneid)), nil
+// Close closes the connection to firewalld, the client can not be used afterwards.
+func (c *DbusClientSerivce) Close() error {
+	return c.Conn.Close()
 }
 
 func (c *DbusClientSerivce) GetDefaultZone() string {
-	return c.defaultZone
+	return *c.defaultZone
+}
+
+// @title         SetDefaultZone
+// @description   Set default zone for connections and interfaces where no zone has been selected, runtime and permanent.
+// @auth      	  author           2021-10-11
+// @param         zone		       string         "zone name."
+// @return        error            error          "Possible errors: ZONE_ALREADY_SET, INVALID_ZONE"
+func (c *DbusClientSerivce) SetDefaultZone(zone string) (err error) {
+	c, end := c.zoneSpan("SetDefaultZone", SCOPE_BOTH, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_SET, Kind: KIND_DEFAULTZONE, Value: zone, Previous: c.GetDefaultZone()}); err != nil {
+		return err
+	}
+	if err = c.checkZoneName(zone); err != nil {
+		return err
+	}
+
+	obj := c.object(object.PATH)
+	call := obj.Call(object.INTERFACE_SETDEFAULTZONE, dbus.FlagNoAutoStart, zone)
+	if call.Err != nil {
+		return call.Err
+	}
+	*c.defaultZone = zone
+	return nil
 }
 
 // @title         GetZoneSettings
@@ -75,55 +248,88 @@ func (c *DbusClientSerivce) GetDefaultZone() string {
 // @return        zones            []string       "Return array of names (s) of predefined zones known to current runtime environment."
 // @return        error            error          ""
 func (c *DbusClientSerivce) GetZones() (zones []string, err error) {
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	c, end := c.span("GetZones", SCOPE_RUNTIME)
+	defer end(&err)
+	cache := c.Cache()
+	if zones, ok := cache.getZones(false); ok {
+		return zones, nil
+	}
+	generation := cache.generation("")
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_GETZONES, dbus.FlagNoAutoStart)
 
 	if call.Err != nil {
 		return nil, call.Err
 	}
-	return call.Body[0].([]string), nil
+	zones = call.Body[0].([]string)
+	cache.setZones(false, zones, generation)
+	return zones, nil
 }
 
 // @title         GetZoneSettings
 // @description   Return runtime settings of given zone.
 // @auth      	  author           2021-09-26
-// @param         zone		       string         "zone name."
+// @param         zone		       string         "zone name. The empty string is usage default zone."
+// @return        settings         *Settings      "runtime settings of zone, rich rules are decoded into Rule."
 // @return        error            error          "Possible errors: INVALID_ZONE"
-func (c *DbusClientSerivce) getZoneId(zone string) int {
-	var (
-		zoneArray []string
-		err       error
-	)
-	if zoneArray, err = c.GetZones(); err != nil {
-		return -1
-	}
-	index := sort.SearchStrings(zoneArray, zone)
-
-	if index < len(zoneArray) && zoneArray[index] == zone {
-		return index
-	} else {
-		return -1
+func (c *DbusClientSerivce) GetZoneSettings(zone string) (settings *Settings, err error) {
+	c, end := c.zoneSpan("GetZoneSettings", SCOPE_RUNTIME, zone)
+	defer end(&err)
+	if zone == "" {
+		zone = c.GetDefaultZone()
 	}
-}
-
-// @title         GetZoneSettings
-// @description   Return runtime settings of given zone.
-// @auth      	  author           2021-09-26
-// @param         zone		       string         "zone name."
-// @return        error            error          "Possible errors: INVALID_ZONE"
-func (c *DbusClientSerivce) GetZoneSettings(zone string) (err error) {
 	if err = c.checkZoneName(zone); err != nil {
-		return err
+		return nil, err
 	}
 
-	obj := c.Conn.Object(object.INTERFACE, object.PATH)
+	obj := c.object(object.PATH)
 
 	call := obj.Call(object.INTERFACE_GETZONESETTINGS, dbus.FlagNoAutoStart, zone)
 	if call.Err != nil {
-		return call.Err
+		return nil, call.Err
 	}
 
-	return
+	var raw zoneSettings
+	if err = call.Store(&raw); err != nil {
+		return nil, err
+	}
+	return raw.toSettings(), nil
+}
+
+// @title         PermanentGetZoneSettings
+// @description   Return permanent settings of given zone.
+// @auth      	  author           2021-10-11
+// @param         zone		       string         "zone name. The empty string is usage default zone."
+// @return        settings         *Settings      "permanent settings of zone, rich rules are decoded into Rule."
+// @return        error            error          "Possible errors: INVALID_ZONE"
+func (c *DbusClientSerivce) PermanentGetZoneSettings(zone string) (settings *Settings, err error) {
+	c, end := c.zoneSpan("PermanentGetZoneSettings", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	if zone == "" {
+		zone = c.GetDefaultZone()
+	}
+	cache := c.Cache()
+	if settings, ok := cache.getSettings(zone); ok {
+		return settings, nil
+	}
+	generation := cache.generation(zone)
+
+	var path dbus.ObjectPath
+	if path, err = c.zonePath(zone); err != nil {
+		return nil, err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_ZONE_GETSETTINGS, dbus.FlagNoAutoStart)
+	if call.Err != nil {
+		return nil, call.Err
+	}
+
+	var raw zoneSettings
+	if err = call.Store(&raw); err != nil {
+		return nil, err
+	}
+	cache.setSettings(zone, &raw, generation)
+	return raw.toSettings(), nil
 }
 
 // @title         AddZone
@@ -133,22 +339,149 @@ func (c *DbusClientSerivce) GetZoneSettings(zone string) (err error) {
 // @return        error            error          "Possible errors: NAME_CONFLICT, INVALID_NAME, INVALID_TYPE"
 
 func (c *DbusClientSerivce) AddZone(name string) (err error) {
+	c, end := c.span("AddZone", SCOPE_PERMANENT)
+	defer end(&err)
 	if err = c.checkZoneName(name); err != nil {
 		return err
 	}
+	return c.permanentAddZone(name, &Settings{Short: name, Targe: "default"})
+}
 
-	obj := c.Conn.Object(object.INTERFACE, object.CONFIG_PATH)
-	zoneSettings := Settings{}
+func (c *DbusClientSerivce) permanentAddZone(name string, settings *Settings) (err error) {
+	obj := c.object(object.CONFIG_PATH)
+	call := obj.Call(object.CONFIG_ADDZONE, dbus.FlagNoAutoStart, name, settings.toTuple())
 
-	zoneSettings.Targe = "default"
-	zoneSettings.Short = name
+	if call.Err != nil {
+		return call.Err
+	}
+	c.Cache().invalidateZone(name, true)
+	return nil
+}
 
-	call := obj.Call(object.CONFIG_ADDZONE, dbus.FlagNoAutoStart, name, zoneSettings)
+// @title         PermanentGetZones
+// @description   Return list of zone names of the permanent configuration.
+// @auth      	  author           2021-10-15
+// @return        zones            []string       "zone names, zones added by AddZone are listed before Reload."
+// @return        error            error          ""
+func (c *DbusClientSerivce) PermanentGetZones() (zones []string, err error) {
+	c, end := c.span("PermanentGetZones", SCOPE_PERMANENT)
+	defer end(&err)
+	cache := c.Cache()
+	if zones, ok := cache.getZones(true); ok {
+		return zones, nil
+	}
+	generation := cache.generation("")
+	obj := c.object(object.CONFIG_PATH)
+	call := obj.Call(object.CONFIG_GETZONENAMES, dbus.FlagNoAutoStart)
 
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	zones = call.Body[0].([]string)
+	cache.setZones(true, zones, generation)
+	return zones, nil
+}
+
+// @title         PermanentSetZoneSettings
+// @description   Replace the permanent settings of zone in a single update, it is checked for lockout as a whole by checkSettings.
+// @auth      	  author           2021-10-15
+// @param         zone		       string         "zone name. The empty string is usage default zone."
+// @param         settings         *Settings      "the complete settings, e.g. returned by PermanentGetZoneSettings and modified."
+// @return        error            error          "Possible errors: INVALID_ZONE, INVALID_RULE, INVALID_PORT, INVALID_SERVICE, LOCKOUT"
+func (c *DbusClientSerivce) PermanentSetZoneSettings(zone string, settings *Settings) (err error) {
+	c, end := c.zoneSpan("PermanentSetZoneSettings", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	if zone == "" {
+		zone = c.GetDefaultZone()
+	}
+	if err = c.checkSettings(zone, settings); err != nil {
+		return err
+	}
+
+	var path dbus.ObjectPath
+	if path, err = c.zonePath(zone); err != nil {
+		return err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_UPDATE, dbus.FlagNoAutoStart, settings.toTuple())
 	if call.Err != nil {
 		return call.Err
 	}
-	return
+	c.Cache().invalidateZone(zone, false)
+	return nil
+}
+
+// @title         PermanentRemoveZone
+// @description   Remove zone from permanent configuration, it is removed from runtime on the next Reload.
+// @auth      	  author           2021-10-15
+// @param         zone		       string         "zone name."
+// @return        error            error          "Possible errors: INVALID_ZONE, BUILTIN_ZONE"
+func (c *DbusClientSerivce) PermanentRemoveZone(zone string) (err error) {
+	c, end := c.zoneSpan("PermanentRemoveZone", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	var path dbus.ObjectPath
+	if path, err = c.zonePath(zone); err != nil {
+		return err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_ZONE_REMOVE, dbus.FlagNoAutoStart)
+	if call.Err != nil {
+		return call.Err
+	}
+	c.Cache().invalidateZone(zone, true)
+	return nil
+}
+
+// @title         PermanentSetZoneTarget
+// @description   Set the target of zone in permanent configuration, firewalld has no runtime target, it is used after Reload.
+// @auth      	  author           2021-10-15
+// @param         zone		       string         "zone name. The empty string is usage default zone."
+// @param         target           string         "default, ACCEPT, REJECT or DROP"
+// @return        error            error          "Possible errors: INVALID_ZONE, INVALID_TARGET, LOCKOUT"
+func (c *DbusClientSerivce) PermanentSetZoneTarget(zone, target string) (err error) {
+	c, end := c.zoneSpan("PermanentSetZoneTarget", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_SET, Kind: KIND_TARGET, Zone: zone, Value: target, Permanent: true}); err != nil {
+		return err
+	}
+	if zone == "" {
+		zone = c.GetDefaultZone()
+	}
+
+	var path dbus.ObjectPath
+	if path, err = c.zonePath(zone); err != nil {
+		return err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_ZONE_SETTARGET, dbus.FlagNoAutoStart, target)
+	if call.Err != nil {
+		return call.Err
+	}
+	c.Cache().invalidateZone(zone, false)
+	return nil
+}
+
+// zonePath returns the object path of the permanent zone, it also finds zones that are not in runtime yet.
+func (c *DbusClientSerivce) zonePath(zone string) (path dbus.ObjectPath, err error) {
+	c, end := c.zoneSpan("zonePath", 0, zone)
+	defer end(&err)
+	cache := c.Cache()
+	if path, ok := cache.getPath(zone); ok {
+		return path, nil
+	}
+	generation := cache.generation(zone)
+	obj := c.object(object.CONFIG_PATH)
+	call := obj.Call(object.CONFIG_GETZONEBYNAME, dbus.FlagNoAutoStart, zone)
+
+	if call.Err != nil {
+		return "", call.Err
+	}
+	if len(call.Body) <= 0 {
+		return "", errors.New("invalid zone.")
+	}
+	path = call.Body[0].(dbus.ObjectPath)
+	cache.setPath(zone, path, generation)
+	return path, nil
 }
 
 // @title         GetZoneOfInterface
@@ -157,8 +490,13 @@ func (c *DbusClientSerivce) AddZone(name string) (err error) {
 // @param         iface    		   string         "e.g. eth0, iface is device name."
 // @return        zoneName         string         "Return name (s) of zone the interface is bound to or empty string.."
 func (c *DbusClientSerivce) GetZoneOfInterface(iface string) string {
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	c, end := c.span("GetZoneOfInterface", SCOPE_RUNTIME)
+	defer end(nil)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_GETZONEOFINTERFACE, dbus.FlagNoAutoStart, iface)
+	if call.Err != nil || len(call.Body) <= 0 {
+		return ""
+	}
 	return call.Body[0].(string)
 }
 
@@ -174,6 +512,8 @@ func (c *DbusClientSerivce) GetZoneOfInterface(iface string) string {
 // @return        error            error          "Possible errors: INVALID_ZONE, INVALID_PORT, MISSING_PROTOCOL, INVALID_PROTOCOL, ALREADY_ENABLED, INVALID_COMMAND."
 
 func (c *DbusClientSerivce) AddPort(port, zone string, timeout int) (list string, err error) {
+	c, end := c.zoneSpan("AddPort", SCOPE_RUNTIME, zone)
+	defer end(&err)
 
 	if err = checkPort(port); err != nil {
 		return "", err
@@ -185,7 +525,7 @@ func (c *DbusClientSerivce) AddPort(port, zone string, timeout int) (list string
 
 	port, protocol := splitPortProtocol(port)
 
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_ADDPORT, dbus.FlagNoAutoStart, zone, port, protocol, timeout)
 
 	if call.Err != nil {
@@ -201,6 +541,8 @@ func (c *DbusClientSerivce) AddPort(port, zone string, timeout int) (list string
 // @param         zone    		   string         "e.g. public|dmz.. The empty string is usage default zone, is currently firewalld defualt zone"
 // @return        error            error          "Possible errors: ALREADY_ENABLED."
 func (c *DbusClientSerivce) PermanentAddPort(port, zone string) (err error) {
+	c, end := c.zoneSpan("PermanentAddPort", SCOPE_PERMANENT, zone)
+	defer end(&err)
 
 	if err = checkPort(port); err != nil {
 		return err
@@ -212,14 +554,15 @@ func (c *DbusClientSerivce) PermanentAddPort(port, zone string) (err error) {
 
 	port, protocol := splitPortProtocol(port)
 
-	if path, err := c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err := c.zonePath(zone); err != nil {
 		return err
 	} else {
-		obj := c.Conn.Object(object.INTERFACE, path)
+		obj := c.object(path)
 		call := obj.Call(object.CONFIG_ZONE_ADDPORT, dbus.FlagNoAutoStart, port, protocol)
 		if call.Err != nil {
 			return call.Err
 		}
+		c.Cache().invalidateZone(zone, false)
 		return nil
 	}
 }
@@ -235,12 +578,14 @@ func (c *DbusClientSerivce) PermanentAddPort(port, zone string) (err error) {
  *                                                      INVALID_ZONE"
  */
 func (c *DbusClientSerivce) GetPort(zone string) (list []*Port, err error) {
+	c, end := c.zoneSpan("GetPort", SCOPE_RUNTIME, zone)
+	defer end(&err)
 
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_GETPORTS, dbus.FlagNoAutoStart, zone)
 
 	if call.Err != nil {
@@ -267,15 +612,17 @@ func (c *DbusClientSerivce) GetPort(zone string) (list []*Port, err error) {
  * 														INVALID_ZONE
  */
 func (c *DbusClientSerivce) PermanentGetPort(zone string) (list []*Port, err error) {
+	c, end := c.zoneSpan("PermanentGetPort", SCOPE_PERMANENT, zone)
+	defer end(&err)
 
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return nil, err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_GETPORTS, dbus.FlagNoAutoStart)
 
 	if call.Err != nil {
@@ -308,6 +655,11 @@ func (c *DbusClientSerivce) PermanentGetPort(zone string) (list []*Port, err err
  *                                                      INVALID_COMMAND"
  */
 func (c *DbusClientSerivce) RemovePort(port, zone string) (b bool, err error) {
+	c, end := c.zoneSpan("RemovePort", SCOPE_RUNTIME, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_REMOVE, Kind: KIND_PORT, Zone: zone, Value: port}); err != nil {
+		return false, err
+	}
 
 	if err = checkPort(port); err != nil {
 		return false, err
@@ -318,7 +670,7 @@ func (c *DbusClientSerivce) RemovePort(port, zone string) (b bool, err error) {
 	}
 	port, protocol := splitPortProtocol(port)
 
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_REMOVEPORT, dbus.FlagNoAutoStart, zone, port, protocol)
 
 	if call.Err != nil {
@@ -339,6 +691,11 @@ func (c *DbusClientSerivce) RemovePort(port, zone string) (b bool, err error) {
  *                                                      NOT_ENABLED"
  */
 func (c *DbusClientSerivce) PermanentRemovePort(port, zone string) (b bool, err error) {
+	c, end := c.zoneSpan("PermanentRemovePort", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_REMOVE, Kind: KIND_PORT, Zone: zone, Value: port, Permanent: true}); err != nil {
+		return false, err
+	}
 	if err = checkPort(port); err != nil {
 		return false, err
 	}
@@ -349,15 +706,15 @@ func (c *DbusClientSerivce) PermanentRemovePort(port, zone string) (b bool, err
 	port, protocol := splitPortProtocol(port)
 
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return false, err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_REMOVEPORT, dbus.FlagNoAutoStart, port, protocol)
-
 	if call.Err != nil {
 		return false, call.Err
 	}
+	c.Cache().invalidateZone(zone, false)
 	return true, nil
 }
 
@@ -372,11 +729,13 @@ func (c *DbusClientSerivce) PermanentRemovePort(port, zone string) (b bool, err
 // @return        zoneName         string         "Returns name of zone to which the protocol was added."
 // @return        error            error          "Possible errors: INVALID_ZONE, INVALID_PROTOCOL, ALREADY_ENABLED, INVALID_COMMAND"
 func (c *DbusClientSerivce) AddProtocol(zone, protocol string, timeout int) (list string, err error) {
+	c, end := c.zoneSpan("AddProtocol", SCOPE_RUNTIME, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_ADDPROTOCOL, dbus.FlagNoAutoStart, zone, protocol, timeout)
 
 	if call.Err != nil {
@@ -396,11 +755,13 @@ func (c *DbusClientSerivce) AddProtocol(zone, protocol string, timeout int) (lis
 // @return        zoneName         string         "Returns name of zone to which the service was added."
 // @return        error            error          "Possible errors: INVALID_ZONE, INVALID_SERVICE, ALREADY_ENABLED, INVALID_COMMAND"
 func (c *DbusClientSerivce) AddService(zone, service string, timeout int) (list string, err error) {
+	c, end := c.zoneSpan("AddService", SCOPE_RUNTIME, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_ADDSERVICE, dbus.FlagNoAutoStart, zone, service, timeout)
 
 	if call.Err != nil {
@@ -416,20 +777,22 @@ func (c *DbusClientSerivce) AddService(zone, service string, timeout int) (list
 // @param         service          string         "service name e.g. http|ssh|ftp.."
 // @return        error            error          "Possible errors: INVALID_ZONE, INVALID_SERVICE, ALREADY_ENABLED, INVALID_COMMAND"
 func (c *DbusClientSerivce) PermanentAddService(zone, service string) (err error) {
+	c, end := c.zoneSpan("PermanentAddService", SCOPE_PERMANENT, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_ADDSERVICE, dbus.FlagNoAutoStart, service)
-
 	if call.Err != nil {
 		return call.Err
 	}
+	c.Cache().invalidateZone(zone, false)
 	return nil
 }
 
@@ -440,11 +803,13 @@ func (c *DbusClientSerivce) PermanentAddService(zone, service string) (err error
 // @param         service          string         "service name e.g. http|ssh|ftp.."
 // @return        error            error          "Possible errors: INVALID_ZONE, INVALID_SERVICE, ALREADY_ENABLED, INVALID_COMMAND"
 func (c *DbusClientSerivce) QueryService(zone, service string) bool {
+	c, end := c.zoneSpan("QueryService", SCOPE_RUNTIME, zone)
+	defer end(nil)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
-	obj := c.Conn.Object(object.INTERFACE, object.PATH)
+	obj := c.object(object.PATH)
 	call := obj.Call(object.ZONE_QUERYSERVICE, dbus.FlagNoAutoStart, zone, service)
 	if !call.Body[0].(bool) {
 		return false
@@ -459,17 +824,19 @@ func (c *DbusClientSerivce) QueryService(zone, service string) bool {
 // @param         service          string         "service name e.g. http|ssh|ftp.."
 // @return        error            error          "Possible errors: INVALID_ZONE, INVALID_SERVICE, ALREADY_ENABLED, INVALID_COMMAND"
 func (c *DbusClientSerivce) PermanentQueryService(zone, service string) bool {
+	c, end := c.zoneSpan("PermanentQueryService", SCOPE_PERMANENT, zone)
+	defer end(nil)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
 	var path dbus.ObjectPath
 	var err error
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return false
 	}
 
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_QUERYSERVICE, dbus.FlagNoAutoStart, service)
 	if !call.Body[0].(bool) {
 		return false
@@ -484,11 +851,16 @@ func (c *DbusClientSerivce) PermanentQueryService(zone, service string) bool {
 // @param         service          string         "service name e.g. http|ssh|ftp.."
 // @return        error            error          "Possible errors: INVALID_ZONE, INVALID_SERVICE, ALREADY_ENABLED, INVALID_COMMAND"
 func (c *DbusClientSerivce) RemoveService(zone, service string) (err error) {
+	c, end := c.zoneSpan("RemoveService", SCOPE_RUNTIME, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_REMOVE, Kind: KIND_SERVICE, Zone: zone, Value: service}); err != nil {
+		return err
+	}
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
-	obj := c.Conn.Object(object.INTERFACE, object.PATH)
+	obj := c.object(object.PATH)
 	call := obj.Call(object.ZONE_REMOVESERVICE, dbus.FlagNoAutoStart, zone, service)
 
 	if call.Err != nil {
@@ -504,23 +876,167 @@ func (c *DbusClientSerivce) RemoveService(zone, service string) (err error) {
 // @param         service          string         "service name e.g. http|ssh|ftp.."
 // @return        error            error          "Possible errors: INVALID_ZONE, INVALID_SERVICE, ALREADY_ENABLED, INVALID_COMMAND"
 func (c *DbusClientSerivce) PermanentRemoveService(zone, service string) (err error) {
+	c, end := c.zoneSpan("PermanentRemoveService", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_REMOVE, Kind: KIND_SERVICE, Zone: zone, Value: service, Permanent: true}); err != nil {
+		return err
+	}
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_REMOVESERVICE, dbus.FlagNoAutoStart, service)
+	if call.Err != nil {
+		return call.Err
+	}
+	c.Cache().invalidateZone(zone, false)
+	return nil
+}
+
+// @title         GetServiceSettings
+// @description   Return runtime settings of given service.
+// @auth      	  author           2021-10-09
+// @param         service          string         "service name e.g. http|ssh|ftp.."
+// @return        settings         *ServiceSettings "runtime settings of service."
+// @return        error            error          "Possible errors: INVALID_SERVICE"
+func (c *DbusClientSerivce) GetServiceSettings(service string) (settings *ServiceSettings, err error) {
+	c, end := c.span("GetServiceSettings", SCOPE_RUNTIME)
+	defer end(&err)
+	obj := c.object(object.PATH)
+	call := obj.Call(object.INTERFACE_GETSERVICESETTINGS, dbus.FlagNoAutoStart, service)
+
+	if call.Err != nil {
+		return nil, call.Err
+	}
+
+	var raw serviceSettings
+	if err = call.Store(&raw); err != nil {
+		return nil, err
+	}
+	return raw.toServiceSettings(), nil
+}
+
+// @title         PermanentGetServices
+// @description   Return list of service names of the permanent configuration.
+// @auth      	  author           2021-10-15
+// @return        services         []string       "service names, e.g. ssh, http"
+// @return        error            error          ""
+func (c *DbusClientSerivce) PermanentGetServices() (services []string, err error) {
+	c, end := c.span("PermanentGetServices", SCOPE_PERMANENT)
+	defer end(&err)
+	obj := c.object(object.CONFIG_PATH)
+	call := obj.Call(object.CONFIG_GETSERVICENAMES, dbus.FlagNoAutoStart)
 
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	return call.Body[0].([]string), nil
+}
+
+// @title         PermanentGetServiceSettings
+// @description   Return permanent settings of given service, firewalld < 0.9 has no protocols, source ports, includes and helpers.
+// @auth      	  author           2021-10-15
+// @param         service          string         "service name e.g. http|ssh|ftp.."
+// @return        settings         *ServiceSettings "permanent settings of service."
+// @return        error            error          "Possible errors: INVALID_SERVICE"
+func (c *DbusClientSerivce) PermanentGetServiceSettings(service string) (settings *ServiceSettings, err error) {
+	c, end := c.span("PermanentGetServiceSettings", SCOPE_PERMANENT)
+	defer end(&err)
+	var path dbus.ObjectPath
+	if path, err = c.servicePath(service); err != nil {
+		return nil, err
+	}
+	obj := c.object(path)
+
+	call := obj.Call(object.CONFIG_SERVICE_GETSETTINGS2, dbus.FlagNoAutoStart)
+	if call.Err == nil {
+		var dict map[string]dbus.Variant
+		if err = call.Store(&dict); err != nil {
+			return nil, err
+		}
+		return serviceSettingsOfDict(dict)
+	}
+	if !isUnknownMethod(call.Err) {
+		return nil, call.Err
+	}
+
+	call = obj.Call(object.CONFIG_SERVICE_GETSETTINGS, dbus.FlagNoAutoStart)
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	var raw serviceSettings
+	if err = call.Store(&raw); err != nil {
+		return nil, err
+	}
+	return raw.toServiceSettings(), nil
+}
+
+// permanentSetServiceSettings replaces the permanent settings of service, or adds the service if create is true.
+func (c *DbusClientSerivce) permanentSetServiceSettings(service string, settings *ServiceSettings, create bool) (err error) {
+	obj := c.object(object.CONFIG_PATH)
+	if !create {
+		var path dbus.ObjectPath
+		if path, err = c.servicePath(service); err != nil {
+			return err
+		}
+		obj = c.object(path)
+	}
+
+	var call *dbus.Call
+	if create {
+		call = obj.Call(object.CONFIG_ADDSERVICE2, dbus.FlagNoAutoStart, service, settings.toDict())
+	} else {
+		call = obj.Call(object.CONFIG_SERVICE_UPDATE2, dbus.FlagNoAutoStart, settings.toDict())
+	}
+	if isUnknownMethod(call.Err) {
+		if create {
+			call = obj.Call(object.CONFIG_ADDSERVICE, dbus.FlagNoAutoStart, service, settings.toTuple())
+		} else {
+			call = obj.Call(object.CONFIG_SERVICE_UPDATE, dbus.FlagNoAutoStart, settings.toTuple())
+		}
+	}
 	if call.Err != nil {
 		return call.Err
 	}
 	return nil
 }
 
+// permanentRemoveServiceConfig removes the service from permanent configuration.
+func (c *DbusClientSerivce) permanentRemoveServiceConfig(service string) (err error) {
+	var path dbus.ObjectPath
+	if path, err = c.servicePath(service); err != nil {
+		return err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_SERVICE_REMOVE, dbus.FlagNoAutoStart)
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+// servicePath returns the object path of the permanent service.
+func (c *DbusClientSerivce) servicePath(service string) (path dbus.ObjectPath, err error) {
+	c, end := c.span("servicePath", 0)
+	defer end(&err)
+	obj := c.object(object.CONFIG_PATH)
+	call := obj.Call(object.CONFIG_GETSERVICEBYNAME, dbus.FlagNoAutoStart, service)
+
+	if call.Err != nil {
+		return "", call.Err
+	}
+	if len(call.Body) <= 0 {
+		return "", errors.New("invalid service.")
+	}
+	return call.Body[0].(dbus.ObjectPath), nil
+}
+
 /************************************************** Masquerade area ***********************************************************/
 
 /*
@@ -535,10 +1051,12 @@ func (c *DbusClientSerivce) PermanentRemoveService(zone, service string) (err er
  *                                                  INVALID_COMMAND"
  */
 func (c *DbusClientSerivce) EnableMasquerade(zone string, timeout int) (err error) {
+	c, end := c.zoneSpan("EnableMasquerade", SCOPE_RUNTIME, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_ADDMASQUERADE, dbus.FlagNoAutoStart, zone, timeout)
 
 	if call.Err != nil && len(call.Body) <= 0 {
@@ -558,20 +1076,22 @@ func (c *DbusClientSerivce) EnableMasquerade(zone string, timeout int) (err erro
  *                                                  INVALID_COMMAND"
  */
 func (c *DbusClientSerivce) PermanentEnableMasquerade(zone string) (err error) {
+	c, end := c.zoneSpan("PermanentEnableMasquerade", SCOPE_PERMANENT, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_ADDMASQUERADE, dbus.FlagNoAutoStart)
-
 	if call.Err != nil {
 		return call.Err
 	}
+	c.Cache().invalidateZone(zone, false)
 	return nil
 }
 
@@ -588,10 +1108,12 @@ func (c *DbusClientSerivce) PermanentEnableMasquerade(zone string) (err error) {
  *                                                  INVALID_COMMAND"
  */
 func (c *DbusClientSerivce) DisableMasquerade(zone string) (err error) {
+	c, end := c.zoneSpan("DisableMasquerade", SCOPE_RUNTIME, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_REMOVEMASQUERADE, dbus.FlagNoAutoStart, zone)
 
 	if call.Err != nil && len(call.Body) <= 0 {
@@ -610,20 +1132,22 @@ func (c *DbusClientSerivce) DisableMasquerade(zone string) (err error) {
  *                                                  NOT_ENABLED"
  */
 func (c *DbusClientSerivce) PermanentDisableMasquerade(zone string) (err error) {
+	c, end := c.zoneSpan("PermanentDisableMasquerade", SCOPE_PERMANENT, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_REMOVEMASQUERADE, dbus.FlagNoAutoStart)
-
 	if call.Err != nil && len(call.Body) <= 0 {
 		return call.Err
 	}
+	c.Cache().invalidateZone(zone, false)
 	return nil
 }
 
@@ -637,15 +1161,17 @@ func (c *DbusClientSerivce) PermanentDisableMasquerade(zone string) (err error)
  *                                                   INVALID_ZONE"
  */
 func (c *DbusClientSerivce) PermanentQueryMasquerade(zone string) (b bool, err error) {
+	c, end := c.zoneSpan("PermanentQueryMasquerade", SCOPE_PERMANENT, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return false, err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_QUERYMASQUERADE, dbus.FlagNoAutoStart)
 
 	if len(call.Body) <= 0 || !call.Body[0].(bool) {
@@ -665,10 +1191,12 @@ func (c *DbusClientSerivce) PermanentQueryMasquerade(zone string) (b bool, err e
  *                                                  INVALID_ZONE"
  */
 func (c *DbusClientSerivce) QueryMasquerade(zone string) (b bool, err error) {
+	c, end := c.zoneSpan("QueryMasquerade", SCOPE_RUNTIME, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_QUERYMASQUERADE, dbus.FlagNoAutoStart, zone)
 	if len(call.Body) <= 0 || !call.Body[0].(bool) {
 		return false, call.Err
@@ -692,11 +1220,16 @@ func (c *DbusClientSerivce) QueryMasquerade(zone string) (b bool, err error) {
  *                                                      INVALID_COMMAND"
  */
 func (c *DbusClientSerivce) BindInterface(zone, interface_name string) (list string, err error) {
+	c, end := c.zoneSpan("BindInterface", SCOPE_RUNTIME, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_ADD, Kind: KIND_INTERFACE, Zone: zone, Value: interface_name}); err != nil {
+		return "", err
+	}
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_ADDINTERFACE, dbus.FlagNoAutoStart, zone, interface_name)
 
 	if call.Err != nil {
@@ -715,19 +1248,24 @@ func (c *DbusClientSerivce) BindInterface(zone, interface_name string) (list str
  *                                                      ALREADY_ENABLED"
  */
 func (c *DbusClientSerivce) PermanentBindInterface(zone, interface_name string) (err error) {
+	c, end := c.zoneSpan("PermanentBindInterface", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_ADD, Kind: KIND_INTERFACE, Zone: zone, Value: interface_name, Permanent: true}); err != nil {
+		return err
+	}
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_ADDINTERFACE, dbus.FlagNoAutoStart, interface_name)
-
 	if call.Err != nil {
 		return call.Err
 	}
+	c.Cache().invalidateZone(zone, false)
 	return nil
 }
 
@@ -743,11 +1281,13 @@ func (c *DbusClientSerivce) PermanentBindInterface(zone, interface_name string)
  *                                                      INVALID_INTERFACE
  */
 func (c *DbusClientSerivce) QueryInterface(zone, interface_name string) (b bool, err error) {
+	c, end := c.zoneSpan("QueryInterface", SCOPE_RUNTIME, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_QUERYINTERFACE, dbus.FlagNoAutoStart, zone, interface_name)
 
 	if len(call.Body) <= 0 || !call.Body[0].(bool) {
@@ -765,19 +1305,21 @@ func (c *DbusClientSerivce) QueryInterface(zone, interface_name string) (b bool,
  *                                                      ALREADY_ENABLED"
  */
 func (c *DbusClientSerivce) PermanentQueryInterface(zone, interface_name string) (err error) {
+	c, end := c.zoneSpan("PermanentQueryInterface", SCOPE_PERMANENT, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_ADDINTERFACE, dbus.FlagNoAutoStart, interface_name)
-
 	if call.Err != nil {
 		return call.Err
 	}
+	c.Cache().invalidateZone(zone, false)
 	return nil
 }
 
@@ -790,11 +1332,16 @@ func (c *DbusClientSerivce) PermanentQueryInterface(zone, interface_name string)
  *                                                      ALREADY_ENABLED"
  */
 func (c *DbusClientSerivce) RemoveInterface(zone, interface_name string) (err error) {
+	c, end := c.zoneSpan("RemoveInterface", SCOPE_RUNTIME, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_REMOVE, Kind: KIND_INTERFACE, Zone: zone, Value: interface_name}); err != nil {
+		return err
+	}
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_REMOVEINTERFACE, dbus.FlagNoAutoStart, zone, interface_name)
 	fmt.Println(call.Body)
 	if call.Err != nil && len(call.Body) <= 0 {
@@ -813,19 +1360,203 @@ func (c *DbusClientSerivce) RemoveInterface(zone, interface_name string) (err er
  *                                                       NOT_ENABLED"
  */
 func (c *DbusClientSerivce) PermanentRemoveInterface(zone, interface_name string) (err error) {
+	c, end := c.zoneSpan("PermanentRemoveInterface", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_REMOVE, Kind: KIND_INTERFACE, Zone: zone, Value: interface_name, Permanent: true}); err != nil {
+		return err
+	}
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_REMOVEINTERFACE, dbus.FlagNoAutoStart, interface_name)
 	fmt.Println(call.Body)
 	if call.Err != nil {
 		return call.Err
 	}
+	c.Cache().invalidateZone(zone, false)
+	return nil
+}
+
+/************************************************** Source area ***********************************************************/
+
+/*
+ * @title         AddSource
+ * @description   temporary Bind source with zone. From now on all traffic
+ * 				   going from this source will respect the zone's settings.
+ * @auth          author           2021-10-11
+ * @param         zone             string         "If zone is empty string, use default zone. e.g. public|dmz..  "
+ * @param         source           string         "address, address/mask, mac or ipset:name. e.g. 10.0.0.0/8"
+ * @return        zoneName         string         "Returns name of zone to which the source was bound."
+ * @return        error            error          "Possible errors:
+ *                                                      INVALID_ZONE,
+ *                                                      INVALID_ADDR,
+ *                                                      ZONE_CONFLICT,
+ *                                                      ALREADY_ENABLED,
+ *                                                      INVALID_COMMAND"
+ */
+func (c *DbusClientSerivce) AddSource(zone, source string) (list string, err error) {
+	c, end := c.zoneSpan("AddSource", SCOPE_RUNTIME, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_ADD, Kind: KIND_SOURCE, Zone: zone, Value: source}); err != nil {
+		return "", err
+	}
+	if zone == "" {
+		zone = c.GetDefaultZone()
+	}
+
+	obj := c.object(object.SERVICE)
+	call := obj.Call(object.ZONE_ADDSOURCE, dbus.FlagNoAutoStart, zone, source)
+
+	if call.Err != nil {
+		return "", call.Err
+	}
+	return call.Body[0].(string), nil
+}
+
+/*
+ * @title         PermanentAddSource
+ * @description   Permanently bind source with zone.
+ * @auth          author           2021-10-11
+ * @param         zone             string         "If zone is empty string, use default zone. e.g. public|dmz..  "
+ * @param         source           string         "address, address/mask, mac or ipset:name. e.g. 10.0.0.0/8"
+ * @return        error            error          "Possible errors:
+ *                                                      ALREADY_ENABLED"
+ */
+func (c *DbusClientSerivce) PermanentAddSource(zone, source string) (err error) {
+	c, end := c.zoneSpan("PermanentAddSource", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_ADD, Kind: KIND_SOURCE, Zone: zone, Value: source, Permanent: true}); err != nil {
+		return err
+	}
+	if zone == "" {
+		zone = c.GetDefaultZone()
+	}
+	var path dbus.ObjectPath
+	if path, err = c.zonePath(zone); err != nil {
+		return err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_ZONE_ADDSOURCE, dbus.FlagNoAutoStart, source)
+	if call.Err != nil {
+		return call.Err
+	}
+	c.Cache().invalidateZone(zone, false)
+	return nil
+}
+
+/*
+ * @title         QuerySource
+ * @description   temporary Query whether source has been bound to zone.
+ * @auth          author           2021-10-11
+ * @param         zone             string         "If zone is empty string, use default zone. e.g. public|dmz..  "
+ * @param         source           string         "address, address/mask, mac or ipset:name. e.g. 10.0.0.0/8"
+ * @return        b         	   bool           "true:enable, fales:disable."
+ * @return        error            error          "Possible errors:
+ *                                                      INVALID_ZONE,
+ *                                                      INVALID_ADDR"
+ */
+func (c *DbusClientSerivce) QuerySource(zone, source string) (b bool, err error) {
+	c, end := c.zoneSpan("QuerySource", SCOPE_RUNTIME, zone)
+	defer end(&err)
+	if zone == "" {
+		zone = c.GetDefaultZone()
+	}
+
+	obj := c.object(object.SERVICE)
+	call := obj.Call(object.ZONE_QUERYSOURCE, dbus.FlagNoAutoStart, zone, source)
+
+	if len(call.Body) <= 0 || !call.Body[0].(bool) {
+		return false, call.Err
+	}
+	return true, nil
+}
+
+/*
+ * @title         PermanentQuerySource
+ * @description   Permanently Query whether source has been bound to zone.
+ * @auth          author           2021-10-11
+ * @param         zone             string         "If zone is empty string, use default zone. e.g. public|dmz..  "
+ * @param         source           string         "address, address/mask, mac or ipset:name. e.g. 10.0.0.0/8"
+ * @return        b         	   bool           "true:enable, fales:disable."
+ * @return        error            error          "Possible errors:
+ *                                                      INVALID_ZONE"
+ */
+func (c *DbusClientSerivce) PermanentQuerySource(zone, source string) (b bool, err error) {
+	c, end := c.zoneSpan("PermanentQuerySource", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	if zone == "" {
+		zone = c.GetDefaultZone()
+	}
+	var path dbus.ObjectPath
+	if path, err = c.zonePath(zone); err != nil {
+		return false, err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_ZONE_QUERYSOURCE, dbus.FlagNoAutoStart, source)
+
+	if len(call.Body) <= 0 || !call.Body[0].(bool) {
+		return false, call.Err
+	}
+	return true, nil
+}
+
+/*
+ * @title         RemoveSource
+ * @description   temporary Remove source from list of sources bound to zone.
+ * @auth          author           2021-10-11
+ * @param         zone             string         "If zone is empty string, use default zone. e.g. public|dmz..  "
+ * @param         source           string         "address, address/mask, mac or ipset:name. e.g. 10.0.0.0/8"
+ * @return        error            error          "Possible errors:
+ *                                                      INVALID_ZONE,
+ *                                                      INVALID_ADDR,
+ *                                                      NOT_ENABLED"
+ */
+func (c *DbusClientSerivce) RemoveSource(zone, source string) (err error) {
+	c, end := c.zoneSpan("RemoveSource", SCOPE_RUNTIME, zone)
+	defer end(&err)
+	if zone == "" {
+		zone = c.GetDefaultZone()
+	}
+
+	obj := c.object(object.SERVICE)
+	call := obj.Call(object.ZONE_REMOVESOURCE, dbus.FlagNoAutoStart, zone, source)
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+/*
+ * @title         PermanentRemoveSource
+ * @description   Permanently remove source from list of sources bound to zone.
+ * @auth          author           2021-10-11
+ * @param         zone             string         "If zone is empty string, use default zone. e.g. public|dmz..  "
+ * @param         source           string         "address, address/mask, mac or ipset:name. e.g. 10.0.0.0/8"
+ * @return        error            error          "Possible errors:
+ *                                                       NOT_ENABLED"
+ */
+func (c *DbusClientSerivce) PermanentRemoveSource(zone, source string) (err error) {
+	c, end := c.zoneSpan("PermanentRemoveSource", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	if zone == "" {
+		zone = c.GetDefaultZone()
+	}
+	var path dbus.ObjectPath
+	if path, err = c.zonePath(zone); err != nil {
+		return err
+	}
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_ZONE_REMOVESOURCE, dbus.FlagNoAutoStart, source)
+	if call.Err != nil {
+		return call.Err
+	}
+	c.Cache().invalidateZone(zone, false)
 	return nil
 }
 
@@ -851,6 +1582,11 @@ func (c *DbusClientSerivce) PermanentRemoveInterface(zone, interface_name string
  * 													INVALID_COMMAND"
  */
 func (c *DbusClientSerivce) AddForwardPort(zone string, portProtocol, toHostPort string, timeout int) (err error) {
+	c, end := c.zoneSpan("AddForwardPort", SCOPE_RUNTIME, zone)
+	defer end(&err)
+	if err = c.checkLockout(forwardPortOperation(OP_ADD, zone, portProtocol, toHostPort, false)); err != nil {
+		return err
+	}
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
@@ -859,7 +1595,7 @@ func (c *DbusClientSerivce) AddForwardPort(zone string, portProtocol, toHostPort
 	if err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_ADDFORWARDPORT, dbus.FlagNoAutoStart, zone, port, protocol, toPort, toAddr, timeout)
 	if call.Err != nil && len(call.Body) <= 0 {
 		return call.Err
@@ -879,6 +1615,11 @@ func (c *DbusClientSerivce) AddForwardPort(zone string, portProtocol, toHostPort
  * 													ALREADY_ENABLED"
  */
 func (c *DbusClientSerivce) PermanentAddForwardPort(zone string, portProtocol, toHostPort string) (err error) {
+	c, end := c.zoneSpan("PermanentAddForwardPort", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	if err = c.checkLockout(forwardPortOperation(OP_ADD, zone, portProtocol, toHostPort, true)); err != nil {
+		return err
+	}
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
@@ -888,14 +1629,15 @@ func (c *DbusClientSerivce) PermanentAddForwardPort(zone string, portProtocol, t
 		return err
 	}
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_ADDFORWARDPORT, dbus.FlagNoAutoStart, port, protocol, toPort, toAddr)
 	if call.Err != nil && len(call.Body) <= 0 {
 		return call.Err
 	}
+	c.Cache().invalidateZone(zone, false)
 	return nil
 }
 
@@ -918,6 +1660,8 @@ func (c *DbusClientSerivce) PermanentAddForwardPort(zone string, portProtocol, t
  * 													INVALID_COMMAND"
  */
 func (c *DbusClientSerivce) RemoveForwardPort(zone string, portProtocol, toHostPort string) (err error) {
+	c, end := c.zoneSpan("RemoveForwardPort", SCOPE_RUNTIME, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
@@ -926,7 +1670,7 @@ func (c *DbusClientSerivce) RemoveForwardPort(zone string, portProtocol, toHostP
 	if err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_REMOVEFORWARDPORT, dbus.FlagNoAutoStart, zone, port, protocol, toPort, toAddr)
 	if call.Err != nil && len(call.Body) <= 0 {
 		return call.Err
@@ -946,6 +1690,8 @@ func (c *DbusClientSerivce) RemoveForwardPort(zone string, portProtocol, toHostP
  * 													ALREADY_ENABLED"
  */
 func (c *DbusClientSerivce) PermanentRemoveForwardPort(zone string, portProtocol, toHostPort string) (err error) {
+	c, end := c.zoneSpan("PermanentRemoveForwardPort", SCOPE_PERMANENT, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
@@ -955,14 +1701,15 @@ func (c *DbusClientSerivce) PermanentRemoveForwardPort(zone string, portProtocol
 		return err
 	}
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_REMOVEFORWARDPORT, dbus.FlagNoAutoStart, port, protocol, toPort, toAddr)
 	if call.Err != nil && len(call.Body) <= 0 {
 		return call.Err
 	}
+	c.Cache().invalidateZone(zone, false)
 	return nil
 }
 
@@ -985,6 +1732,8 @@ func (c *DbusClientSerivce) PermanentRemoveForwardPort(zone string, portProtocol
  * 													INVALID_COMMAND"
  */
 func (c *DbusClientSerivce) QueryForwardPort(zone string, portProtocol, toHostPort string) (b bool) {
+	c, end := c.zoneSpan("QueryForwardPort", SCOPE_RUNTIME, zone)
+	defer end(nil)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
@@ -993,7 +1742,7 @@ func (c *DbusClientSerivce) QueryForwardPort(zone string, portProtocol, toHostPo
 	if err != nil {
 		return false
 	}
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_QUERYFORWARDPORT, dbus.FlagNoAutoStart, zone, port, protocol, toPort, toAddr)
 	fmt.Println(call.Body)
 	if call.Err != nil || !call.Body[0].(bool) {
@@ -1014,6 +1763,8 @@ func (c *DbusClientSerivce) QueryForwardPort(zone string, portProtocol, toHostPo
  * 													ALREADY_ENABLED"
  */
 func (c *DbusClientSerivce) PermanentQueryForwardPort(zone string, portProtocol, toHostPort string) (b bool, err error) {
+	c, end := c.zoneSpan("PermanentQueryForwardPort", SCOPE_PERMANENT, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
@@ -1023,10 +1774,10 @@ func (c *DbusClientSerivce) PermanentQueryForwardPort(zone string, portProtocol,
 		return false, err
 	}
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return false, err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_QUERYFORWARDPORT, dbus.FlagNoAutoStart, port, protocol, toPort, toAddr)
 	if call.Err != nil || (len(call.Body) <= 0 || !call.Body[0].(bool)) {
 		return false, call.Err
@@ -1043,11 +1794,13 @@ func (c *DbusClientSerivce) PermanentQueryForwardPort(zone string, portProtocol,
 // @return        zoneName         string         "Returns name of zone to which the interface was bound."
 // @return        error            error          "Possible errors: INVALID_ZONE"
 func (c *DbusClientSerivce) GetRichRules(zone string) (ruleList []*Rule, err error) {
+	c, end := c.zoneSpan("GetRichRules", SCOPE_RUNTIME, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_GETRICHRULES, dbus.FlagNoAutoStart, zone)
 
 	if call.Err != nil {
@@ -1068,11 +1821,16 @@ func (c *DbusClientSerivce) GetRichRules(zone string) (ruleList []*Rule, err err
 // @param         timeout    	   int	          "Timeout, if timeout is non-zero, the operation will be active only for the amount of seconds."
 // @return        error            error          "Possible errors: ALREADY_ENABLED"
 func (c *DbusClientSerivce) AddRichRule(zone string, rule *Rule, timeout int) (err error) {
+	c, end := c.zoneSpan("AddRichRule", SCOPE_RUNTIME, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_ADD, Kind: KIND_RICHRULE, Zone: zone, Value: rule.ToString()}); err != nil {
+		return err
+	}
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_ADDRICHRULE, dbus.FlagNoAutoStart, zone, rule.ToString(), timeout)
 
 	if call.Err != nil {
@@ -1088,20 +1846,25 @@ func (c *DbusClientSerivce) AddRichRule(zone string, rule *Rule, timeout int) (e
 // @param         rule    	   	   rule	          "rule, rule is rule struct."
 // @return        error            error          "Possible errors: ALREADY_ENABLED"
 func (c *DbusClientSerivce) PermanentAddRichRule(zone string, rule *Rule) (err error) {
+	c, end := c.zoneSpan("PermanentAddRichRule", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_ADD, Kind: KIND_RICHRULE, Zone: zone, Value: rule.ToString(), Permanent: true}); err != nil {
+		return err
+	}
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_ADDRICHRULE, dbus.FlagNoAutoStart, rule.ToString())
-
 	if call.Err != nil {
 		return call.Err
 	}
+	c.Cache().invalidateZone(zone, false)
 	return nil
 }
 
@@ -1112,10 +1875,15 @@ func (c *DbusClientSerivce) PermanentAddRichRule(zone string, rule *Rule) (err e
 // @param         rule    	   	   rule	          "rule, rule is rule struct."
 // @return        error            error          "Possible errors: INVALID_ZONE, INVALID_RULE, NOT_ENABLED, INVALID_COMMAND"
 func (c *DbusClientSerivce) RemoveRichRule(zone string, rule *Rule) (err error) {
+	c, end := c.zoneSpan("RemoveRichRule", SCOPE_RUNTIME, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_REMOVE, Kind: KIND_RICHRULE, Zone: zone, Value: rule.ToString()}); err != nil {
+		return err
+	}
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_REOMVERICHRULE, dbus.FlagNoAutoStart, zone, rule.ToString())
 
 	if call.Err != nil {
@@ -1131,19 +1899,24 @@ func (c *DbusClientSerivce) RemoveRichRule(zone string, rule *Rule) (err error)
 // @param         rule    	   	   rule	          "rule, rule is rule struct."
 // @return        error            error          "Possible errors: ALREADY_ENABLED"
 func (c *DbusClientSerivce) PermanentRemoveRichRule(zone string, rule *Rule) (err error) {
+	c, end := c.zoneSpan("PermanentRemoveRichRule", SCOPE_PERMANENT, zone)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Action: OP_REMOVE, Kind: KIND_RICHRULE, Zone: zone, Value: rule.ToString(), Permanent: true}); err != nil {
+		return err
+	}
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_REOMVERICHRULE, dbus.FlagNoAutoStart, rule.ToString())
-
 	if call.Err != nil {
 		return call.Err
 	}
+	c.Cache().invalidateZone(zone, false)
 	return nil
 }
 
@@ -1154,16 +1927,18 @@ func (c *DbusClientSerivce) PermanentRemoveRichRule(zone string, rule *Rule) (er
 // @param         rule    	   	   rule	          "rule, rule is rule struct."
 // @return        bool             bool           "Possible errors: INVALID_ZONE, INVALID_RULE"
 func (c *DbusClientSerivce) PermanentQueryRichRule(zone string, rule *Rule) bool {
+	c, end := c.zoneSpan("PermanentQueryRichRule", SCOPE_PERMANENT, zone)
+	defer end(nil)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 
 	var path dbus.ObjectPath
 	var err error
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return false
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
+	obj := c.object(path)
 	call := obj.Call(object.CONFIG_ZONE_QUERYRICHRULE, dbus.FlagNoAutoStart, rule.ToString())
 
 	if len(call.Body) <= 0 || !call.Body[0].(bool) {
@@ -1179,10 +1954,12 @@ func (c *DbusClientSerivce) PermanentQueryRichRule(zone string, rule *Rule) bool
 // @param         rule    	   	   rule	          "rule, rule is rule struct."
 // @return        bool             bool           "Possible errors: INVALID_ZONE, INVALID_RULE"
 func (c *DbusClientSerivce) QueryRichRule(zone string, rule *Rule) bool {
+	c, end := c.zoneSpan("QueryRichRule", SCOPE_RUNTIME, zone)
+	defer end(nil)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.ZONE_QUERYRICHRULE, dbus.FlagNoAutoStart, zone, rule.ToString())
 
 	if len(call.Body) <= 0 || !call.Body[0].(bool) {
@@ -1201,8 +1978,34 @@ func (c *DbusClientSerivce) QueryRichRule(zone string, rule *Rule) bool {
  *                                                      ALREADY_ENABLED"
  */
 func (c *DbusClientSerivce) Reload() (err error) {
-	obj := c.Conn.Object(object.INTERFACE, object.SERVICE)
+	c, end := c.span("Reload", 0)
+	defer end(&err)
+	if err = c.checkLockout(Operation{Kind: KIND_RELOAD}); err != nil {
+		return err
+	}
+	obj := c.object(object.SERVICE)
 	call := obj.Call(object.INTERFACE_RELOAD, dbus.FlagNoAutoStart)
+	c.Cache().Invalidate()
+
+	if call.Err != nil {
+		return call.Err
+	}
+	return nil
+}
+
+/*
+ * @title         RuntimeToPermanent
+ * @description   Make runtime settings of all zones permanent, they survive the next Reload.
+ * @auth          author           2021-10-12
+ * @return        error            error          "Possible errors:
+ *                                                      RT_TO_PERM_FAILED"
+ */
+func (c *DbusClientSerivce) RuntimeToPermanent() (err error) {
+	c, end := c.span("RuntimeToPermanent", 0)
+	defer end(&err)
+	obj := c.object(object.SERVICE)
+	call := obj.Call(object.INTERFACE_RUNTIMETOPERMANENT, dbus.FlagNoAutoStart)
+	c.Cache().Invalidate()
 
 	if call.Err != nil {
 		return call.Err
@@ -1210,37 +2013,92 @@ func (c *DbusClientSerivce) Reload() (err error) {
 	return nil
 }
 
+/*
+ * @title         QueryPanicMode
+ * @description   Return whether panic mode is enabled, all packets are dropped then.
+ * @auth          author           2021-10-18
+ * @return        enabled          bool           "true if panic mode is enabled."
+ * @return        error            error          ""
+ */
+func (c *DbusClientSerivce) QueryPanicMode() (enabled bool, err error) {
+	c, end := c.span("QueryPanicMode", 0)
+	defer end(&err)
+	obj := c.object(object.PATH)
+	call := obj.Call(object.INTERFACE_QUERYPANICMODE, dbus.FlagNoAutoStart)
+	if call.Err != nil {
+		return false, call.Err
+	}
+	if len(call.Body) <= 0 {
+		return false, nil
+	}
+	enabled, _ = call.Body[0].(bool)
+	return enabled, nil
+}
+
+/*
+ * @title         GetState
+ * @description   Return the state of firewalld, the property state of org.fedoraproject.FirewallD1.
+ * @auth          author           2021-10-18
+ * @return        state            string         "STATE_INIT, STATE_RUNNING or STATE_FAILED."
+ * @return        error            error          ""
+ */
+func (c *DbusClientSerivce) GetState() (state string, err error) {
+	c, end := c.span("GetState", 0)
+	defer end(&err)
+	obj := c.object(object.PATH)
+	call := obj.Call(object.PROPERTIES_GET, dbus.FlagNoAutoStart, object.INTERFACE, object.PROPERTY_STATE)
+	if call.Err != nil {
+		return "", call.Err
+	}
+	if len(call.Body) <= 0 {
+		return "", errors.New("no state.")
+	}
+	variant, ok := call.Body[0].(dbus.Variant)
+	if !ok {
+		return "", fmt.Errorf("state is %T.", call.Body[0])
+	}
+	if state, ok = variant.Value().(string); !ok {
+		return "", fmt.Errorf("state is %s.", variant.Signature())
+	}
+	return state, nil
+}
+
 /*
  * @title         flush currently zone zoneSettings to default zoneSettings.
- * @description   temporary Add rich language rule into zone.
+ * @description   Replace the permanent settings of zone with target default and the services ssh and dhcpv6-client.
+ *                  to return to a known state of the whole firewall, use Snapshot and Restore.
  * @auth          author           2021-10-05
  * @return        error            error          "Possible errors:
- *                                                      ALREADY_ENABLED"
+ *                                                      INVALID_ZONE"
  */
 func (c *DbusClientSerivce) RuntimeFlush(zone string) (err error) {
+	c, end := c.zoneSpan("RuntimeFlush", SCOPE_PERMANENT, zone)
+	defer end(&err)
 	if zone == "" {
 		zone = c.GetDefaultZone()
 	}
 	zoneSettings := &Settings{
 		Targe:       "default",
 		Description: "reset to firewalld-api",
-		Short:       "public",
-		Interface:   nil,
+		Short:       zone,
 		Service: []string{
 			"ssh",
 			"dhcpv6-client",
 		},
 	}
+	if err = c.checkSettings(zone, zoneSettings); err != nil {
+		return err
+	}
 
 	var path dbus.ObjectPath
-	if path, err = c.generatePath(zone, object.ZONE_PATH); err != nil {
+	if path, err = c.zonePath(zone); err != nil {
 		return err
 	}
-	obj := c.Conn.Object(object.INTERFACE, path)
-	call := obj.Call(object.CONFIG_UPDATE, dbus.FlagNoAutoStart, zoneSettings)
-
-	if call.Err != nil || len(call.Body) > 0 {
+	obj := c.object(path)
+	call := obj.Call(object.CONFIG_UPDATE, dbus.FlagNoAutoStart, zoneSettings.toTuple())
+	if call.Err != nil {
 		return call.Err
 	}
+	c.Cache().invalidateZone(zone, false)
 	return nil
 }
@@ -0,0 +1,584 @@
+package dbus
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+ * Flow describes a single new connection as seen by the host, e.g.
+ *   a TCP packet from 10.1.2.3 to port 5432 on eth1:
+ *   Flow{Interface: "eth1", Source: "10.1.2.3", Protocol: "tcp", Port: "5432"}
+ */
+type Flow struct {
+	Interface   string `json:"interface"`
+	Source      string `json:"source"`
+	SourceMac   string `json:"sourcemac"`
+	Destination string `json:"destination"`
+	Protocol    string `json:"protocol"`
+	Port        string `json:"port"`
+	SourcePort  string `json:"sourceport"`
+	IcmpType    string `json:"icmptype"`
+}
+
+// Verdict is the result of a simulation, Trace explains every step that was taken.
+type Verdict struct {
+	Zone      string   `json:"zone"`
+	Action    string   `json:"action"`
+	Match     string   `json:"match"`
+	ForwardTo string   `json:"forwardto"`
+	Trace     []string `json:"trace"`
+}
+
+/*
+ * Simulator is an offline evaluator over fetched zone settings.
+ *   Zones       zone name -> runtime settings, see GetZoneSettings
+ *   Interfaces  interface -> zone, see GetZoneOfInterface, it overrides the zone interface lists.
+ *   Services    service name -> settings, used to resolve the ports of a service.
+ *   IPSets      ipset name -> entries, used to resolve ipset sources.
+ */
+type Simulator struct {
+	DefaultZone string
+	Zones       map[string]*Settings
+	Interfaces  map[string]string
+	Services    map[string]*ServiceSettings
+	IPSets      map[string][]string
+}
+
+const (
+	VERDICT_ACCEPT  = "accept"
+	VERDICT_REJECT  = "reject"
+	VERDICT_DROP    = "drop"
+	VERDICT_FORWARD = "forward"
+)
+
+// @title         NewSimulator
+// @description   fetch runtime settings of all zones and the services they use.
+// @auth      	  author           2021-10-09
+// @return        simulator        *Simulator     "simulator over the current runtime configuration."
+// @return        error            error          "Possible errors: INVALID_ZONE, INVALID_SERVICE"
+func (c *DbusClientSerivce) NewSimulator() (simulator *Simulator, err error) {
+	c, end := c.span("NewSimulator", 0)
+	defer end(&err)
+	return c.newSimulator(false)
+}
+
+// newSimulator fetches the runtime, or the permanent, settings of all zones.
+func (c *DbusClientSerivce) newSimulator(permanent bool) (simulator *Simulator, err error) {
+	return loadSimulator(c, permanent)
+}
+
+func loadSimulator(c Firewall, permanent bool) (simulator *Simulator, err error) {
+	var zones []string
+	if permanent {
+		zones, err = c.PermanentGetZones()
+	} else {
+		zones, err = c.GetZones()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	simulator = &Simulator{
+		DefaultZone: c.GetDefaultZone(),
+		Zones:       make(map[string]*Settings),
+		Interfaces:  make(map[string]string),
+		Services:    make(map[string]*ServiceSettings),
+		IPSets:      make(map[string][]string),
+	}
+	for _, zone := range zones {
+		var settings *Settings
+		if permanent {
+			settings, err = c.PermanentGetZoneSettings(zone)
+		} else {
+			settings, err = c.GetZoneSettings(zone)
+		}
+		if err != nil {
+			return nil, err
+		}
+		simulator.Zones[zone] = settings
+
+		services := append([]string{}, settings.Service...)
+		for _, rule := range settings.Rule {
+			if rule.Service.Name != "" {
+				services = append(services, rule.Service.Name)
+			}
+		}
+		for _, service := range services {
+			if _, ok := simulator.Services[service]; ok {
+				continue
+			}
+			if permanent {
+				simulator.Services[service], err = c.PermanentGetServiceSettings(service)
+			} else {
+				simulator.Services[service], err = c.GetServiceSettings(service)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, source := range settings.Source {
+			if source.Ipset == "" {
+				continue
+			}
+			if permanent {
+				simulator.IPSets[source.Ipset], err = c.PermanentGetIPSetEntries(source.Ipset)
+			} else {
+				simulator.IPSets[source.Ipset], err = c.GetIPSetEntries(source.Ipset)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return simulator, nil
+}
+
+// @title         Simulate
+// @description   evaluate which zone and rule would match the given flow in the current runtime configuration.
+// @auth      	  author           2021-10-09
+// @param         flow             Flow           "e.g. Flow{Interface: "eth1", Source: "10.1.2.3", Protocol: "tcp", Port: "5432"}"
+// @return        verdict          *Verdict       "the action with an explanation trace."
+// @return        error            error          "Possible errors: INVALID_ZONE, INVALID_SERVICE, INVALID_ADDR"
+func (c *DbusClientSerivce) Simulate(flow Flow) (verdict *Verdict, err error) {
+	c, end := c.span("Simulate", 0)
+	defer end(&err)
+	return simulateFlow(c, flow)
+}
+
+func simulateFlow(c Firewall, flow Flow) (verdict *Verdict, err error) {
+	var simulator *Simulator
+	if simulator, err = c.NewSimulator(); err != nil {
+		return nil, err
+	}
+	if flow.Interface != "" {
+		if zone := c.GetZoneOfInterface(flow.Interface); zone != "" {
+			simulator.Interfaces[flow.Interface] = zone
+		}
+	}
+	return simulator.Evaluate(flow)
+}
+
+/*
+ * @title         Evaluate
+ * @description   follow firewalld zone selection and rule ordering for flow:
+ *                  zone: source binding (most specific first), interface binding, default zone.
+ *                  rules: forward ports, rich rules with priority < 0, log/audit, deny (drop, reject, icmp-block),
+ *                  allow (services, ports, protocols, source ports, accept), rich rules with priority > 0, zone target.
+ * @auth          author           2021-10-09
+ * @param         flow             Flow           "the connection to evaluate."
+ * @return        verdict          *Verdict       "the action with an explanation trace."
+ * @return        error            error          "Possible errors: INVALID_ZONE, INVALID_ADDR"
+ */
+func (s *Simulator) Evaluate(flow Flow) (verdict *Verdict, err error) {
+	if flow.Source != "" && net.ParseIP(flow.Source) == nil {
+		return nil, fmt.Errorf("INVALID_ADDR: %s", flow.Source)
+	}
+	if flow.Destination != "" && net.ParseIP(flow.Destination) == nil {
+		return nil, fmt.Errorf("INVALID_ADDR: %s", flow.Destination)
+	}
+	if flow.Protocol == "" {
+		flow.Protocol = "tcp"
+	}
+	flow.Protocol = strings.ToLower(flow.Protocol)
+
+	verdict = &Verdict{}
+	if verdict.Zone, err = s.selectZone(flow, verdict); err != nil {
+		return nil, err
+	}
+	settings := s.Zones[verdict.Zone]
+
+	if s.evalForwardPorts(settings, flow, verdict) {
+		return verdict, nil
+	}
+
+	rules := sortedRules(settings.Rule)
+	for _, rule := range rules {
+		if rule.Priority < 0 && s.evalRule(rule, flow, verdict) {
+			return verdict, nil
+		}
+	}
+	for _, rule := range rules {
+		if rule.Priority == 0 && (!rule.Log.IsEmpty() || !rule.Audit.IsEmpty()) && s.matchRule(rule, flow) {
+			verdict.trace("log: %s", rule.ToString())
+		}
+	}
+	for _, rule := range rules {
+		action := rule.Action()
+		if rule.Priority == 0 && (action == VERDICT_DROP || action == VERDICT_REJECT || !rule.IcmpBlock.IsEmpty()) &&
+			s.evalRule(rule, flow, verdict) {
+			return verdict, nil
+		}
+	}
+	if s.evalIcmpBlock(settings, flow, verdict) {
+		return verdict, nil
+	}
+	if s.evalAllow(settings, flow, verdict) {
+		return verdict, nil
+	}
+	for _, rule := range rules {
+		action := rule.Action()
+		if rule.Priority == 0 && (action == VERDICT_ACCEPT || action == "mark") && s.evalRule(rule, flow, verdict) {
+			return verdict, nil
+		}
+	}
+	for _, rule := range rules {
+		if rule.Priority > 0 && s.evalRule(rule, flow, verdict) {
+			return verdict, nil
+		}
+	}
+
+	s.evalTarget(settings, flow, verdict)
+	return verdict, nil
+}
+
+func (v *Verdict) trace(format string, args ...interface{}) {
+	v.Trace = append(v.Trace, fmt.Sprintf(format, args...))
+}
+
+func (v *Verdict) set(action, match string) {
+	v.Action = action
+	v.Match = match
+	v.trace("%s: %s", action, match)
+}
+
+func (s *Simulator) selectZone(flow Flow, v *Verdict) (zone string, err error) {
+	names := make([]string, 0, len(s.Zones))
+	for name := range s.Zones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best := -1
+	for _, name := range names {
+		for _, source := range s.Zones[name].Source {
+			if ok, bits := s.matchSource(source, flow); ok && bits > best {
+				zone, best = name, bits
+				v.trace("zone %s: source %s matches", name, sourceString(source))
+			}
+		}
+	}
+	if zone != "" {
+		return zone, nil
+	}
+
+	if flow.Interface != "" {
+		if name, ok := s.Interfaces[flow.Interface]; ok {
+			v.trace("zone %s: interface %s is bound to zone", name, flow.Interface)
+			zone = name
+		} else {
+			for _, name := range names {
+				for _, iface := range s.Zones[name].Interface {
+					if iface.Name == flow.Interface && zone == "" {
+						v.trace("zone %s: interface %s is bound to zone", name, flow.Interface)
+						zone = name
+					}
+				}
+			}
+		}
+	}
+	if zone == "" {
+		zone = s.DefaultZone
+		v.trace("zone %s: no source or interface binding, using default zone", zone)
+	}
+	if _, ok := s.Zones[zone]; !ok {
+		return "", fmt.Errorf("INVALID_ZONE: %s", zone)
+	}
+	return zone, nil
+}
+
+func (s *Simulator) evalForwardPorts(settings *Settings, flow Flow, v *Verdict) bool {
+	var forward *ForwardPort
+	var match string
+	for _, rule := range sortedRules(settings.Rule) {
+		if !rule.ForwardPort.IsEmpty() && s.matchRule(rule, flow) {
+			forward, match = &rule.ForwardPort, rule.ToString()
+			break
+		}
+	}
+	if forward == nil {
+		for i := range settings.ForwardPort {
+			fp := settings.ForwardPort[i]
+			if strings.EqualFold(fp.Protocol, flow.Protocol) && portMatches(fp.Port, flow.Port) {
+				forward, match = &fp, fp.ToString()
+				break
+			}
+		}
+	}
+	if forward == nil {
+		return false
+	}
+
+	toPort := forward.ToPort
+	if toPort == "" {
+		toPort = flow.Port
+	}
+	if forward.ToAddr == "" {
+		v.ForwardTo = net.JoinHostPort("127.0.0.1", toPort)
+		v.set(VERDICT_ACCEPT, match)
+		v.trace("port %s is redirected to local port %s, dnat traffic is accepted", flow.Port, toPort)
+		return true
+	}
+	v.ForwardTo = net.JoinHostPort(forward.ToAddr, toPort)
+	v.set(VERDICT_FORWARD, match)
+	if !settings.Masquerade {
+		v.trace("masquerade is disabled in zone %s, replies must be routed back through this host", v.Zone)
+	}
+	return true
+}
+
+func (s *Simulator) evalIcmpBlock(settings *Settings, flow Flow, v *Verdict) bool {
+	if !isIcmp(flow.Protocol) {
+		return false
+	}
+	listed := false
+	for _, block := range settings.IcmpBlock {
+		if block.Name == flow.IcmpType {
+			listed = true
+		}
+	}
+	if settings.IcmpBlockInversion && !listed {
+		v.set(VERDICT_REJECT, "icmp-block-inversion")
+		return true
+	}
+	if !settings.IcmpBlockInversion && listed {
+		v.set(VERDICT_REJECT, "icmp-block name="+quote(flow.IcmpType))
+		return true
+	}
+	return false
+}
+
+func (s *Simulator) evalAllow(settings *Settings, flow Flow, v *Verdict) bool {
+	for _, name := range settings.Service {
+		if s.matchService(name, flow, v) {
+			v.set(VERDICT_ACCEPT, "service "+name)
+			return true
+		}
+	}
+	for _, port := range settings.Port {
+		if strings.EqualFold(port.Protocol, flow.Protocol) && portMatches(port.Port, flow.Port) {
+			v.set(VERDICT_ACCEPT, "port "+port.Port+"/"+port.Protocol)
+			return true
+		}
+	}
+	for _, protocol := range settings.Protocol {
+		if strings.EqualFold(protocol.Value, flow.Protocol) {
+			v.set(VERDICT_ACCEPT, "protocol "+protocol.Value)
+			return true
+		}
+	}
+	for _, port := range settings.SourcePort {
+		if strings.EqualFold(port.Protocol, flow.Protocol) && portMatches(port.Port, flow.SourcePort) {
+			v.set(VERDICT_ACCEPT, "source-port "+port.Port+"/"+port.Protocol)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Simulator) evalTarget(settings *Settings, flow Flow, v *Verdict) {
+	target := settings.Targe
+	switch strings.ToUpper(target) {
+	case "ACCEPT":
+		v.set(VERDICT_ACCEPT, "target ACCEPT")
+	case "DROP":
+		v.set(VERDICT_DROP, "target DROP")
+	case "%%REJECT%%", "REJECT":
+		v.set(VERDICT_REJECT, "target REJECT")
+	default:
+		if isIcmp(flow.Protocol) {
+			v.set(VERDICT_ACCEPT, "target default, icmp is allowed")
+		} else {
+			v.set(VERDICT_REJECT, "target default")
+		}
+	}
+}
+
+// evalRule applies rule on flow, it returns true if the rule terminated the evaluation.
+func (s *Simulator) evalRule(rule Rule, flow Flow, v *Verdict) bool {
+	if rule.Raw != "" {
+		v.trace("rule not understood, skipped: %s", rule.Raw)
+		return false
+	}
+	if !rule.ForwardPort.IsEmpty() || rule.Masquerade || rule.TcpMssClamp.Flag || !s.matchRule(rule, flow) {
+		return false
+	}
+	if !rule.IcmpBlock.IsEmpty() {
+		v.set(VERDICT_REJECT, rule.ToString())
+		return true
+	}
+	switch action := rule.Action(); action {
+	case VERDICT_ACCEPT, VERDICT_REJECT, VERDICT_DROP:
+		v.set(action, rule.ToString())
+		return true
+	case "mark":
+		v.trace("mark: %s", rule.ToString())
+	}
+	return false
+}
+
+func (s *Simulator) matchRule(rule Rule, flow Flow) bool {
+	if rule.Family != "" {
+		if family := flowFamily(flow); family != "" && family != rule.Family {
+			return false
+		}
+	}
+	if !rule.Source.IsEmpty() {
+		ok, _ := s.matchSource(rule.Source, flow)
+		if ok == isTrue(rule.Source.Invert) {
+			return false
+		}
+	}
+	if !rule.Destination.IsEmpty() {
+		ok := matchAddress(rule.Destination.Address, flow.Destination) >= 0
+		if ok == isTrue(rule.Destination.Invert) {
+			return false
+		}
+	}
+
+	switch {
+	case !rule.Service.IsEmpty():
+		return s.matchService(rule.Service.Name, flow, nil)
+	case !rule.Port.IsEmpty():
+		return strings.EqualFold(rule.Port.Protocol, flow.Protocol) && portMatches(rule.Port.Port, flow.Port)
+	case !rule.Protocol.IsEmpty():
+		return strings.EqualFold(rule.Protocol.Value, flow.Protocol)
+	case !rule.IcmpBlock.IsEmpty():
+		return isIcmp(flow.Protocol) && rule.IcmpBlock.Name == flow.IcmpType
+	case !rule.IcmpType.IsEmpty():
+		return isIcmp(flow.Protocol) && rule.IcmpType.Name == flow.IcmpType
+	case !rule.ForwardPort.IsEmpty():
+		return strings.EqualFold(rule.ForwardPort.Protocol, flow.Protocol) && portMatches(rule.ForwardPort.Port, flow.Port)
+	case !rule.SourcePort.IsEmpty():
+		return strings.EqualFold(rule.SourcePort.Protocol, flow.Protocol) && portMatches(rule.SourcePort.Port, flow.SourcePort)
+	}
+	return true
+}
+
+func (s *Simulator) matchService(name string, flow Flow, v *Verdict) bool {
+	service, ok := s.Services[name]
+	if !ok || service == nil {
+		if v != nil {
+			v.trace("service %s: definition unknown, skipped", name)
+		}
+		return false
+	}
+	for _, port := range service.Port {
+		if port.Port == "" && strings.EqualFold(port.Protocol, flow.Protocol) {
+			return true
+		}
+		if strings.EqualFold(port.Protocol, flow.Protocol) && portMatches(port.Port, flow.Port) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSource returns whether source matches flow and the prefix length of the match.
+func (s *Simulator) matchSource(source Source, flow Flow) (bool, int) {
+	switch {
+	case source.Address != "":
+		bits := matchAddress(source.Address, flow.Source)
+		return bits >= 0, bits
+	case source.Mac != "":
+		return flow.SourceMac != "" && strings.EqualFold(source.Mac, flow.SourceMac), 128
+	case source.Ipset != "":
+		best := -1
+		for _, entry := range s.IPSets[source.Ipset] {
+			if bits := matchAddress(entry, flow.Source); bits > best {
+				best = bits
+			}
+		}
+		return best >= 0, best
+	}
+	return false, -1
+}
+
+// matchAddress returns the prefix length of address (ip or cidr) if addr is within it, otherwise -1.
+func matchAddress(address, addr string) int {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return -1
+	}
+	if !strings.Contains(address, "/") {
+		// a host ranks as its /32 or /128, ParseIP returns ipv4 in the 16 bytes form.
+		if other := net.ParseIP(address); other != nil && other.Equal(ip) {
+			if other.To4() != nil {
+				return 32
+			}
+			return 128
+		}
+		return -1
+	}
+	_, network, err := net.ParseCIDR(address)
+	if err != nil || !network.Contains(ip) {
+		return -1
+	}
+	bits, _ := network.Mask.Size()
+	return bits
+}
+
+// portMatches reports whether port is within spec, spec is a port or a port range e.g. 1000-1100.
+func portMatches(spec, port string) bool {
+	if spec == "" || port == "" {
+		return false
+	}
+	value, err := strconv.Atoi(port)
+	if err != nil {
+		return spec == port
+	}
+	bounds := strings.SplitN(spec, "-", 2)
+	first, err := strconv.Atoi(bounds[0])
+	if err != nil {
+		return false
+	}
+	last := first
+	if len(bounds) == 2 {
+		if last, err = strconv.Atoi(bounds[1]); err != nil {
+			return false
+		}
+	}
+	return value >= first && value <= last
+}
+
+func flowFamily(flow Flow) string {
+	for _, addr := range []string{flow.Source, flow.Destination} {
+		if ip := net.ParseIP(addr); ip != nil {
+			if ip.To4() != nil {
+				return "ipv4"
+			}
+			return "ipv6"
+		}
+	}
+	return ""
+}
+
+func isIcmp(protocol string) bool {
+	switch protocol {
+	case "icmp", "ipv6-icmp", "icmpv6":
+		return true
+	}
+	return false
+}
+
+func sourceString(source Source) string {
+	switch {
+	case source.Address != "":
+		return source.Address
+	case source.Mac != "":
+		return source.Mac
+	}
+	return "ipset:" + source.Ipset
+}
+
+// sortedRules orders rich rules by priority, rules with the same priority keep their order.
+func sortedRules(rules []Rule) []Rule {
+	sorted := append([]Rule{}, rules...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority < sorted[j].Priority
+	})
+	return sorted
+}
@@ -0,0 +1,735 @@
+package dbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/cylonchau/gofirewallder/object"
+	"github.com/godbus/dbus/v5"
+)
+
+// SNAPSHOT_VERSION is the format version of Snapshot, Restore refuses other versions.
+const SNAPSHOT_VERSION = 1
+
+/*
+ * Snapshot is the complete configuration of a host, runtime and permanent, e.g. taken before a change.
+ *   Zones, IPSets, Policies  keyed by name, Runtime is nil if the object is only in permanent configuration.
+ *   Services                 permanent service definitions that are not the defaults shipped with firewalld,
+ *                            runtime uses them after Reload.
+ *   Policies                 nil if firewalld has no policies, < 0.9.
+ */
+type Snapshot struct {
+	Version     int                         `json:"version"`
+	Taken       time.Time                   `json:"taken"`
+	DefaultZone string                      `json:"defaultzone"`
+	Zones       map[string]*ZoneSnapshot    `json:"zones"`
+	IPSets      map[string]*IPSetSnapshot   `json:"ipsets"`
+	Services    map[string]*ServiceSettings `json:"services"`
+	Policies    map[string]*PolicySnapshot  `json:"policies,omitempty"`
+	Direct      DirectSnapshot              `json:"direct"`
+}
+
+type ZoneSnapshot struct {
+	Runtime   *Settings `json:"runtime,omitempty"`
+	Permanent *Settings `json:"permanent"`
+}
+
+// IPSetSnapshot holds the permanent settings of an ipset, the runtime entries are in Runtime.
+type IPSetSnapshot struct {
+	Runtime   []string       `json:"runtime,omitempty"`
+	Permanent *IPSetSettings `json:"permanent"`
+}
+
+type PolicySnapshot struct {
+	Runtime   *PolicySettings `json:"runtime,omitempty"`
+	Permanent *PolicySettings `json:"permanent"`
+}
+
+type DirectSnapshot struct {
+	Runtime   *DirectSettings `json:"runtime"`
+	Permanent *DirectSettings `json:"permanent"`
+}
+
+// JSON encodes the snapshot.
+func (s *Snapshot) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// ParseSnapshot decodes a snapshot encoded by JSON.
+func ParseSnapshot(data []byte) (snapshot *Snapshot, err error) {
+	snapshot = &Snapshot{}
+	if err = json.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Version != SNAPSHOT_VERSION {
+		return nil, fmt.Errorf("INVALID_COMMAND: unsupported snapshot version %d", snapshot.Version)
+	}
+	return snapshot, nil
+}
+
+// validate checks everything Restore decodes from snapshot, so a bad snapshot fails before the first change.
+func (s *Snapshot) validate() (err error) {
+	if s.Version != SNAPSHOT_VERSION {
+		return fmt.Errorf("INVALID_COMMAND: unsupported snapshot version %d", s.Version)
+	}
+	for _, name := range zoneSnapshotNames(s.Zones) {
+		zone := s.Zones[name]
+		if zone == nil || zone.Permanent == nil {
+			return fmt.Errorf("INVALID_COMMAND: zone %s has no permanent settings", name)
+		}
+		for _, settings := range []*Settings{zone.Permanent, zone.Runtime} {
+			if settings == nil {
+				continue
+			}
+			if err = (&Plan{}).diffZone(name, false, &Settings{}, zoneStateOf(settings)); err != nil {
+				return fmt.Errorf("zone %s: %v", name, err)
+			}
+		}
+	}
+	for name, ipset := range s.IPSets {
+		if ipset == nil || ipset.Permanent == nil {
+			return fmt.Errorf("INVALID_COMMAND: ipset %s has no permanent settings", name)
+		}
+	}
+	for name, service := range s.Services {
+		if service == nil {
+			return fmt.Errorf("INVALID_COMMAND: service %s has no settings", name)
+		}
+	}
+	for name, policy := range s.Policies {
+		if policy == nil || policy.Permanent == nil {
+			return fmt.Errorf("INVALID_COMMAND: policy %s has no permanent settings", name)
+		}
+	}
+	return nil
+}
+
+/*
+ * @title         Snapshot
+ * @description   capture the runtime and permanent settings of every zone, ipset, policy and the direct configuration,
+ *                  the permanent service definitions added or changed by the user and the default zone.
+ * @auth          author           2021-10-15
+ * @return        snapshot         *Snapshot      "serializable with JSON, see Restore."
+ * @return        error            error          "the object that could not be read and the error of firewalld."
+ */
+func (c *DbusClientSerivce) Snapshot() (snapshot *Snapshot, err error) {
+	c, end := c.span("Snapshot", 0)
+	defer end(&err)
+	snapshot = &Snapshot{
+		Version:  SNAPSHOT_VERSION,
+		Taken:    time.Now().UTC(),
+		Zones:    map[string]*ZoneSnapshot{},
+		IPSets:   map[string]*IPSetSnapshot{},
+		Services: map[string]*ServiceSettings{},
+	}
+	obj := c.object(object.SERVICE)
+	call := obj.Call(object.INTERFACE_GETDEFAULTZONE, dbus.FlagNoAutoStart)
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	snapshot.DefaultZone = call.Body[0].(string)
+
+	var names []string
+	if names, err = c.PermanentGetZones(); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		zone := &ZoneSnapshot{}
+		if zone.Permanent, err = c.PermanentGetZoneSettings(name); err != nil {
+			return nil, fmt.Errorf("zone %s: %v", name, err)
+		}
+		snapshot.Zones[name] = zone
+	}
+	if names, err = c.GetZones(); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if zone, ok := snapshot.Zones[name]; ok {
+			if zone.Runtime, err = c.GetZoneSettings(name); err != nil {
+				return nil, fmt.Errorf("zone %s: %v", name, err)
+			}
+		}
+	}
+
+	if names, err = c.PermanentGetIPSets(); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		ipset := &IPSetSnapshot{}
+		if ipset.Permanent, err = c.PermanentGetIPSetSettings(name); err != nil {
+			return nil, fmt.Errorf("ipset %s: %v", name, err)
+		}
+		snapshot.IPSets[name] = ipset
+	}
+	if names, err = c.GetIPSets(); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if ipset, ok := snapshot.IPSets[name]; ok {
+			if ipset.Runtime, err = c.GetIPSetEntries(name); err != nil {
+				return nil, fmt.Errorf("ipset %s: %v", name, err)
+			}
+		}
+	}
+
+	if names, err = c.PermanentGetServices(); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		var builtin, isDefault bool
+		if builtin, isDefault, err = c.permanentBuiltin(KIND_SERVICE, name); err != nil {
+			return nil, fmt.Errorf("service %s: %v", name, err)
+		}
+		if builtin && isDefault {
+			continue
+		}
+		if snapshot.Services[name], err = c.PermanentGetServiceSettings(name); err != nil {
+			return nil, fmt.Errorf("service %s: %v", name, err)
+		}
+	}
+
+	if names, err = c.PermanentGetPolicies(); err != nil && !isUnknownMethod(err) {
+		return nil, err
+	}
+	if err == nil {
+		snapshot.Policies = map[string]*PolicySnapshot{}
+		for _, name := range names {
+			policy := &PolicySnapshot{}
+			if policy.Permanent, err = c.PermanentGetPolicySettings(name); err != nil {
+				return nil, fmt.Errorf("policy %s: %v", name, err)
+			}
+			snapshot.Policies[name] = policy
+		}
+		if names, err = c.GetPolicies(); err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if policy, ok := snapshot.Policies[name]; ok {
+				if policy.Runtime, err = c.GetPolicySettings(name); err != nil {
+					return nil, fmt.Errorf("policy %s: %v", name, err)
+				}
+			}
+		}
+	}
+
+	if snapshot.Direct.Permanent, err = c.PermanentGetDirectSettings(); err != nil {
+		return nil, err
+	}
+	if snapshot.Direct.Runtime, err = c.GetDirectSettings(); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+/*
+ * @title         Restore
+ * @description   return the host to the configuration of snapshot.
+ *                  permanent objects that differ are updated, missing ones are added and extra ones removed,
+ *                  builtin objects can not be removed, they are reset to their defaults, then firewalld is reloaded, the default zone is set and the runtime only changes are replayed.
+ *                  the snapshot is checked before the first change, rich rules the parser does not understand are restored as written.
+ * @auth          author           2021-10-15
+ * @param         snapshot         *Snapshot      "taken by Snapshot, or decoded by ParseSnapshot."
+ * @return        error            error          "the object that could not be restored and the error of firewalld."
+ */
+func (c *DbusClientSerivce) Restore(snapshot *Snapshot) (err error) {
+	c, end := c.span("Restore", 0)
+	defer end(&err)
+	if err = snapshot.validate(); err != nil {
+		return err
+	}
+	// the steps pass through states that may block the management connection, only the outcome is checked.
+	if err = c.checkRestore(snapshot); err != nil {
+		return err
+	}
+	c = c.Unguarded()
+
+	// referenced objects are restored first and removed last: ipsets and services, zones, policies.
+	var ipsets, services, zones, policies []string
+	if ipsets, err = c.PermanentGetIPSets(); err != nil {
+		return err
+	}
+	for _, name := range ipsetSnapshotNames(snapshot.IPSets) {
+		want := snapshot.IPSets[name].Permanent
+		err = c.restoreObject(KIND_IPSET, name, contains(ipsets, name), want,
+			func() (interface{}, error) { return c.PermanentGetIPSetSettings(name) },
+			func(create bool) error {
+				if create {
+					return c.permanentAddIPSet(name, want)
+				}
+				return c.PermanentSetIPSetSettings(name, want)
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	if services, err = c.PermanentGetServices(); err != nil {
+		return err
+	}
+	for _, name := range serviceNames(snapshot.Services) {
+		want := snapshot.Services[name]
+		err = c.restoreObject(KIND_SERVICE, name, contains(services, name), want,
+			func() (interface{}, error) { return c.PermanentGetServiceSettings(name) },
+			func(create bool) error { return c.permanentSetServiceSettings(name, want, create) })
+		if err != nil {
+			return err
+		}
+	}
+
+	if zones, err = c.PermanentGetZones(); err != nil {
+		return err
+	}
+	for _, name := range zoneSnapshotNames(snapshot.Zones) {
+		want := snapshot.Zones[name].Permanent
+		err = c.restoreObject(KIND_ZONE, name, contains(zones, name), want,
+			func() (interface{}, error) { return c.PermanentGetZoneSettings(name) },
+			func(create bool) error {
+				if create {
+					return c.permanentAddZone(name, want)
+				}
+				return c.PermanentSetZoneSettings(name, want)
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	if snapshot.Policies != nil {
+		if policies, err = c.PermanentGetPolicies(); err != nil {
+			return err
+		}
+		for _, name := range policySnapshotNames(snapshot.Policies) {
+			want := snapshot.Policies[name].Permanent
+			err = c.restoreObject(KIND_POLICY, name, contains(policies, name), want,
+				func() (interface{}, error) { return c.PermanentGetPolicySettings(name) },
+				func(create bool) error {
+					if create {
+						return c.PermanentAddPolicy(name, want)
+					}
+					return c.PermanentSetPolicySettings(name, want)
+				})
+			if err != nil {
+				return err
+			}
+		}
+		for _, name := range policies {
+			if _, ok := snapshot.Policies[name]; !ok {
+				if err = c.removeExtra(KIND_POLICY, name, func() error { return c.PermanentRemovePolicy(name) }); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, name := range zones {
+		if _, ok := snapshot.Zones[name]; !ok {
+			if err = c.removeExtra(KIND_ZONE, name, func() error { return c.PermanentRemoveZone(name) }); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range services {
+		if _, ok := snapshot.Services[name]; !ok {
+			if err = c.removeExtra(KIND_SERVICE, name, func() error { return c.permanentRemoveServiceConfig(name) }); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range ipsets {
+		if _, ok := snapshot.IPSets[name]; !ok {
+			if err = c.removeExtra(KIND_IPSET, name, func() error { return c.PermanentRemoveIPSet(name) }); err != nil {
+				return err
+			}
+		}
+	}
+
+	if snapshot.Direct.Permanent != nil {
+		err = c.restoreObject("direct", "configuration", true, snapshot.Direct.Permanent,
+			func() (interface{}, error) { return c.PermanentGetDirectSettings() },
+			func(bool) error { return c.PermanentSetDirectSettings(snapshot.Direct.Permanent) })
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = c.Reload(); err != nil {
+		return fmt.Errorf("restore: reload failed: %v", err)
+	}
+	obj := c.object(object.SERVICE)
+	call := obj.Call(object.INTERFACE_GETDEFAULTZONE, dbus.FlagNoAutoStart)
+	if call.Err != nil {
+		return call.Err
+	}
+	*c.defaultZone = call.Body[0].(string)
+	if snapshot.DefaultZone != "" && snapshot.DefaultZone != *c.defaultZone {
+		if err = c.SetDefaultZone(snapshot.DefaultZone); err != nil {
+			return fmt.Errorf("restore: set default zone %s failed: %v", snapshot.DefaultZone, err)
+		}
+	}
+	return c.restoreRuntime(snapshot)
+}
+
+// restoreRuntime replays the runtime only changes of snapshot on top of the reloaded permanent configuration.
+func (c *DbusClientSerivce) restoreRuntime(snapshot *Snapshot) (err error) {
+	plan := &Plan{}
+	for _, name := range ipsetSnapshotNames(snapshot.IPSets) {
+		if want := snapshot.IPSets[name]; want.Runtime != nil {
+			var live []string
+			if live, err = c.GetIPSetEntries(name); err != nil {
+				return fmt.Errorf("restore: ipset %s: %v", name, err)
+			}
+			plan.diff(Operation{Kind: KIND_IPSETENTRY, IPSet: name}, live, want.Runtime)
+		}
+	}
+
+	var extra []func() error
+	for _, name := range zoneSnapshotNames(snapshot.Zones) {
+		want := snapshot.Zones[name].Runtime
+		if want == nil {
+			continue
+		}
+		var live *Settings
+		if live, err = c.GetZoneSettings(name); err != nil {
+			return fmt.Errorf("restore: zone %s: %v", name, err)
+		}
+		if err = plan.diffZone(name, false, live, zoneStateOf(want)); err != nil {
+			return err
+		}
+		extra = append(extra, c.restoreZoneExtras(name, live, want)...)
+	}
+	// an interface or source moving between zones must be removed before it is added.
+	sort.SliceStable(plan.Operations, func(i, j int) bool {
+		return plan.Operations[i].rank() < plan.Operations[j].rank()
+	})
+	if err = c.Apply(plan); err != nil {
+		return fmt.Errorf("restore: %v", err)
+	}
+	for _, call := range extra {
+		if err = call(); err != nil {
+			return fmt.Errorf("restore: %v", err)
+		}
+	}
+
+	for _, name := range policySnapshotNames(snapshot.Policies) {
+		want := snapshot.Policies[name].Runtime
+		if want == nil {
+			continue
+		}
+		var live *PolicySettings
+		if live, err = c.GetPolicySettings(name); err != nil {
+			return fmt.Errorf("restore: policy %s: %v", name, err)
+		}
+		if !sameJSON(live, want) {
+			if err = c.SetPolicySettings(name, want); err != nil {
+				return fmt.Errorf("restore: policy %s: %v", name, err)
+			}
+		}
+	}
+
+	if snapshot.Direct.Runtime != nil {
+		var live *DirectSettings
+		if live, err = c.GetDirectSettings(); err != nil {
+			return err
+		}
+		if err = c.restoreDirect(live, snapshot.Direct.Runtime); err != nil {
+			return fmt.Errorf("restore: direct: %v", err)
+		}
+	}
+	return nil
+}
+
+// restoreZoneExtras returns the runtime calls for the zone items that have no Operation kind.
+func (c *DbusClientSerivce) restoreZoneExtras(zone string, live, want *Settings) (calls []func() error) {
+	obj := c.object(object.SERVICE)
+	call := func(method string, args ...interface{}) func() error {
+		return func() error {
+			if call := obj.Call(method, dbus.FlagNoAutoStart, args...); call.Err != nil {
+				return fmt.Errorf("zone %s: %s: %v", zone, method, call.Err)
+			}
+			return nil
+		}
+	}
+	liveTuple, wantTuple := live.toTuple(), want.toTuple()
+
+	for _, value := range liveTuple.IcmpBlocks {
+		if !contains(wantTuple.IcmpBlocks, value) {
+			calls = append(calls, call(object.ZONE_REMOVEICMPBLOCK, zone, value))
+		}
+	}
+	for _, value := range wantTuple.IcmpBlocks {
+		if !contains(liveTuple.IcmpBlocks, value) {
+			calls = append(calls, call(object.ZONE_ADDICMPBLOCK, zone, value, 0))
+		}
+	}
+	for _, value := range liveTuple.Protocols {
+		if !contains(wantTuple.Protocols, value) {
+			calls = append(calls, call(object.ZONE_REMOVEPROTOCOL, zone, value))
+		}
+	}
+	for _, value := range wantTuple.Protocols {
+		if !contains(liveTuple.Protocols, value) {
+			calls = append(calls, call(object.ZONE_ADDPROTOCOL, zone, value, 0))
+		}
+	}
+	for _, value := range liveTuple.SourcePorts {
+		if !containsPort(wantTuple.SourcePorts, value) {
+			calls = append(calls, call(object.ZONE_REMOVESOURCEPORT, zone, value.Port, value.Protocol))
+		}
+	}
+	for _, value := range wantTuple.SourcePorts {
+		if !containsPort(liveTuple.SourcePorts, value) {
+			calls = append(calls, call(object.ZONE_ADDSOURCEPORT, zone, value.Port, value.Protocol, 0))
+		}
+	}
+	if live.IcmpBlockInversion != want.IcmpBlockInversion {
+		method := object.ZONE_REMOVEICMPBLOCKINVERSION
+		if want.IcmpBlockInversion {
+			method = object.ZONE_ADDICMPBLOCKINVERSION
+		}
+		calls = append(calls, call(method, zone))
+	}
+	return calls
+}
+
+// restoreDirect removes the live direct rules that are not wanted and adds the missing ones.
+func (c *DbusClientSerivce) restoreDirect(live, want *DirectSettings) (err error) {
+	for _, value := range live.Passthrough {
+		if !containsJSON(want.Passthrough, value) {
+			if err = c.RemoveDirectPassthrough(value); err != nil {
+				return err
+			}
+		}
+	}
+	for _, value := range live.Rule {
+		if !containsJSON(want.Rule, value) {
+			if err = c.RemoveDirectRule(value); err != nil {
+				return err
+			}
+		}
+	}
+	for _, value := range live.Chain {
+		if !containsJSON(want.Chain, value) {
+			if err = c.RemoveDirectChain(value); err != nil {
+				return err
+			}
+		}
+	}
+	for _, value := range want.Chain {
+		if !containsJSON(live.Chain, value) {
+			if err = c.AddDirectChain(value); err != nil {
+				return err
+			}
+		}
+	}
+	for _, value := range want.Rule {
+		if !containsJSON(live.Rule, value) {
+			if err = c.AddDirectRule(value); err != nil {
+				return err
+			}
+		}
+	}
+	for _, value := range want.Passthrough {
+		if !containsJSON(live.Passthrough, value) {
+			if err = c.AddDirectPassthrough(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// restoreObject adds a missing permanent object, or updates it if its settings differ from want.
+func (c *DbusClientSerivce) restoreObject(kind, name string, exists bool, want interface{},
+	get func() (interface{}, error), set func(create bool) error) (err error) {
+	if exists {
+		var live interface{}
+		if live, err = get(); err != nil {
+			return fmt.Errorf("restore: %s %s: %v", kind, name, err)
+		}
+		if sameJSON(live, want) {
+			return nil
+		}
+	}
+	if err = set(!exists); err != nil {
+		return fmt.Errorf("restore: %s %s failed: %v", kind, name, err)
+	}
+	return nil
+}
+
+// removeExtra removes an object missing from the snapshot, a builtin object is reset to its defaults instead.
+func (c *DbusClientSerivce) removeExtra(kind, name string, remove func() error) (err error) {
+	var builtin, isDefault bool
+	if builtin, isDefault, err = c.permanentBuiltin(kind, name); err != nil {
+		return fmt.Errorf("restore: %s %s: %v", kind, name, err)
+	}
+	switch {
+	case builtin && isDefault:
+		return nil
+	case builtin:
+		err = c.permanentLoadDefaults(kind, name)
+	default:
+		err = remove()
+	}
+	if err != nil {
+		return fmt.Errorf("restore: remove %s %s failed: %v", kind, name, err)
+	}
+	return nil
+}
+
+// configObject returns the permanent config object of a zone, ipset, service or policy and its interface.
+func (c *DbusClientSerivce) configObject(kind, name string) (path dbus.ObjectPath, iface string, err error) {
+	switch kind {
+	case KIND_ZONE:
+		path, err = c.zonePath(name)
+		return path, object.CONFIG_ZONE, err
+	case KIND_IPSET:
+		path, err = c.ipsetPath(name)
+		return path, object.CONFIG_IPSET, err
+	case KIND_SERVICE:
+		path, err = c.servicePath(name)
+		return path, object.CONFIG_SERVICE, err
+	case KIND_POLICY:
+		path, err = c.policyPath(name)
+		return path, object.CONFIG_POLICY, err
+	}
+	return "", "", fmt.Errorf("INVALID_COMMAND: unknown kind '%s'", kind)
+}
+
+// permanentBuiltin reads the builtin and default properties of the config object of kind, KIND_ZONE, KIND_IPSET,
+// KIND_SERVICE or KIND_POLICY: whether it is shipped with firewalld and whether it still has the shipped settings.
+func (c *DbusClientSerivce) permanentBuiltin(kind, name string) (builtin, isDefault bool, err error) {
+	c, end := c.span("permanentBuiltin", SCOPE_PERMANENT)
+	defer end(&err)
+	path, iface, err := c.configObject(kind, name)
+	if err != nil {
+		return false, false, err
+	}
+	obj := c.object(path)
+	for _, property := range []struct {
+		name  string
+		value *bool
+	}{{object.PROPERTY_BUILTIN, &builtin}, {object.PROPERTY_DEFAULT, &isDefault}} {
+		call := obj.Call(object.PROPERTIES_GET, dbus.FlagNoAutoStart, iface, property.name)
+		if call.Err != nil {
+			return false, false, call.Err
+		}
+		if len(call.Body) <= 0 {
+			return false, false, fmt.Errorf("no property %s.", property.name)
+		}
+		variant, ok := call.Body[0].(dbus.Variant)
+		if !ok {
+			return false, false, fmt.Errorf("%s is %T.", property.name, call.Body[0])
+		}
+		if *property.value, ok = variant.Value().(bool); !ok {
+			return false, false, fmt.Errorf("%s is %s.", property.name, variant.Signature())
+		}
+	}
+	return builtin, isDefault, nil
+}
+
+// permanentLoadDefaults resets a builtin object of kind to the settings shipped with firewalld.
+func (c *DbusClientSerivce) permanentLoadDefaults(kind, name string) (err error) {
+	path, iface, err := c.configObject(kind, name)
+	if err != nil {
+		return err
+	}
+	obj := c.object(path)
+	call := obj.Call(iface+".loadDefaults", dbus.FlagNoAutoStart)
+
+	if call.Err != nil {
+		return call.Err
+	}
+	if kind == KIND_ZONE {
+		c.Cache().invalidateZone(name, true)
+	}
+	return nil
+}
+
+// sameJSON reports whether a and b have the same JSON form, empty lists and maps are equal to null.
+func sameJSON(a, b interface{}) bool {
+	var left, right interface{}
+	for _, pair := range []struct {
+		value interface{}
+		dest  *interface{}
+	}{{a, &left}, {b, &right}} {
+		data, err := json.Marshal(pair.value)
+		if err != nil {
+			return false
+		}
+		if err = json.Unmarshal(data, pair.dest); err != nil {
+			return false
+		}
+		*pair.dest = withoutEmpty(*pair.dest)
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+func withoutEmpty(value interface{}) interface{} {
+	switch value := value.(type) {
+	case []interface{}:
+		if len(value) == 0 {
+			return nil
+		}
+		for index := range value {
+			value[index] = withoutEmpty(value[index])
+		}
+	case map[string]interface{}:
+		if len(value) == 0 {
+			return nil
+		}
+		for key := range value {
+			value[key] = withoutEmpty(value[key])
+		}
+	}
+	return value
+}
+
+// containsJSON reports whether the slice list contains value, compared by their JSON form.
+func containsJSON(list interface{}, value interface{}) bool {
+	var items []json.RawMessage
+	data, _ := json.Marshal(list)
+	if err := json.Unmarshal(data, &items); err != nil {
+		return false
+	}
+	for _, item := range items {
+		if sameJSON(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPort(list []portTuple, value portTuple) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func zoneSnapshotNames(m map[string]*ZoneSnapshot) (names []string) {
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func ipsetSnapshotNames(m map[string]*IPSetSnapshot) (names []string) {
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func policySnapshotNames(m map[string]*PolicySnapshot) (names []string) {
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,395 @@
+package dbus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+/*
+ * DesiredState describes the firewall of a host, e.g. in yaml:
+ *
+ *   defaultzone: public
+ *   zones:
+ *     public:
+ *       ports: ["80/tcp", "443/tcp"]
+ *       services: [ssh]
+ *       sources: ["10.0.0.0/8"]
+ *       forwardports: ["port=8080:proto=tcp:toport=80:toaddr=10.0.0.2"]
+ *       masquerade: true
+ *       richrules: ['rule family="ipv4" source address="10.1.0.0/16" service name="http" accept']
+ *   ipsets:
+ *     blocklist:
+ *       type: hash:ip
+ *       entries: ["192.0.2.1"]
+ *
+ * A field that is omitted is not managed, an empty list removes all items.
+ * Zones and ipsets that are not listed are not touched.
+ */
+type DesiredState struct {
+	DefaultZone string                `json:"defaultzone,omitempty" yaml:"defaultzone,omitempty"`
+	Zones       map[string]ZoneState  `json:"zones,omitempty" yaml:"zones,omitempty"`
+	IPSets      map[string]IPSetState `json:"ipsets,omitempty" yaml:"ipsets,omitempty"`
+}
+
+type ZoneState struct {
+	Ports        []string `json:"ports,omitempty" yaml:"ports,omitempty"`
+	Services     []string `json:"services,omitempty" yaml:"services,omitempty"`
+	Sources      []string `json:"sources,omitempty" yaml:"sources,omitempty"`
+	Interfaces   []string `json:"interfaces,omitempty" yaml:"interfaces,omitempty"`
+	ForwardPorts []string `json:"forwardports,omitempty" yaml:"forwardports,omitempty"`
+	Masquerade   *bool    `json:"masquerade,omitempty" yaml:"masquerade,omitempty"`
+	RichRules    []string `json:"richrules,omitempty" yaml:"richrules,omitempty"`
+}
+
+type IPSetState struct {
+	Type    string   `json:"type" yaml:"type"`
+	Entries []string `json:"entries,omitempty" yaml:"entries,omitempty"`
+}
+
+// Plan is the ordered list of operations converging a host to the desired state.
+type Plan struct {
+	Operations []Operation `json:"operations" yaml:"operations"`
+	Notes      []string    `json:"notes,omitempty" yaml:"notes,omitempty"`
+}
+
+// ParseDesiredState decodes a desired state document, yaml or json.
+func ParseDesiredState(data []byte) (desired *DesiredState, err error) {
+	desired = &DesiredState{}
+	if err = yaml.Unmarshal(data, desired); err != nil {
+		return nil, err
+	}
+	return desired, nil
+}
+
+// IsEmpty reports whether the host already is in the desired state.
+func (p *Plan) IsEmpty() bool {
+	return len(p.Operations) == 0
+}
+
+// String renders the plan for review, one operation per line.
+func (p *Plan) String() string {
+	if p.IsEmpty() {
+		return "no changes, the host is in the desired state.\n"
+	}
+	var builder strings.Builder
+	for _, op := range p.Operations {
+		builder.WriteString(op.String())
+		builder.WriteString("\n")
+	}
+	for _, note := range p.Notes {
+		builder.WriteString("# " + note + "\n")
+	}
+	fmt.Fprintf(&builder, "%d operation(s).\n", len(p.Operations))
+	return builder.String()
+}
+
+/*
+ * @title         Plan
+ * @description   read the live runtime and permanent state and compute the operations converging it to desired.
+ *                  permanent operations are planned before runtime operations, removals before additions.
+ *                  zones and ipsets missing from the permanent configuration are added to it.
+ * @auth          author           2021-10-11
+ * @param         desired          *DesiredState  "the desired state of the host."
+ * @return        plan             *Plan          "the operations to Apply."
+ * @return        error            error          "Possible errors: INVALID_ZONE, INVALID_IPSET, INVALID_FORWARD"
+ */
+func (c *DbusClientSerivce) Plan(desired *DesiredState) (plan *Plan, err error) {
+	c, end := c.span("Plan", 0)
+	defer end(&err)
+	return computePlan(c, desired)
+}
+
+func computePlan(c Firewall, desired *DesiredState) (plan *Plan, err error) {
+	plan = &Plan{}
+
+	var ipsets, permanentIPSets, zones, permanentZones []string
+	if len(desired.Zones) > 0 {
+		if zones, err = c.GetZones(); err != nil {
+			return nil, err
+		}
+		if permanentZones, err = c.PermanentGetZones(); err != nil {
+			return nil, err
+		}
+	}
+	if len(desired.IPSets) > 0 {
+		if ipsets, err = c.GetIPSets(); err != nil {
+			return nil, err
+		}
+		if permanentIPSets, err = c.PermanentGetIPSets(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, permanent := range []bool{true, false} {
+		for _, name := range ipsetStateNames(desired.IPSets) {
+			state := desired.IPSets[name]
+			exists := contains(ipsets, name)
+			if permanent {
+				exists = contains(permanentIPSets, name)
+			}
+			if !exists {
+				if !permanent {
+					plan.Notes = append(plan.Notes, fmt.Sprintf("ipset %s is created in permanent configuration, reload to use it in runtime.", name))
+					continue
+				}
+				plan.Operations = append(plan.Operations, Operation{Action: OP_ADD, Kind: KIND_IPSET, IPSet: name, Value: state.Type, Permanent: true})
+				for _, entry := range state.Entries {
+					plan.Operations = append(plan.Operations, Operation{Action: OP_ADD, Kind: KIND_IPSETENTRY, IPSet: name, Value: entry, Permanent: true})
+				}
+				continue
+			}
+
+			var live []string
+			if permanent {
+				live, err = c.PermanentGetIPSetEntries(name)
+			} else {
+				live, err = c.GetIPSetEntries(name)
+			}
+			if err != nil {
+				return nil, err
+			}
+			plan.diff(Operation{Kind: KIND_IPSETENTRY, IPSet: name, Permanent: permanent}, live, state.Entries)
+		}
+
+		for _, zone := range zoneStateNames(desired.Zones) {
+			var settings *Settings
+			if !contains(permanentZones, zone) {
+				if !permanent {
+					plan.Notes = append(plan.Notes, fmt.Sprintf("zone %s is created in permanent configuration, reload to use it in runtime.", zone))
+					continue
+				}
+				plan.Operations = append(plan.Operations, Operation{Action: OP_ADD, Kind: KIND_ZONE, Zone: zone, Permanent: true})
+				if err = plan.diffZone(zone, true, &Settings{}, desired.Zones[zone]); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if !permanent && !contains(zones, zone) {
+				plan.Notes = append(plan.Notes, fmt.Sprintf("zone %s is in permanent configuration only, reload to use it in runtime.", zone))
+				continue
+			}
+			if permanent {
+				settings, err = c.PermanentGetZoneSettings(zone)
+			} else {
+				settings, err = c.GetZoneSettings(zone)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err = plan.diffZone(zone, permanent, settings, desired.Zones[zone]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if desired.DefaultZone != "" && desired.DefaultZone != c.GetDefaultZone() {
+		plan.Operations = append(plan.Operations, Operation{
+			Action:    OP_SET,
+			Kind:      KIND_DEFAULTZONE,
+			Value:     desired.DefaultZone,
+			Previous:  c.GetDefaultZone(),
+			Permanent: true,
+		})
+	}
+
+	// an interface or source moving between zones must be removed before it is added.
+	sort.SliceStable(plan.Operations, func(i, j int) bool {
+		return plan.Operations[i].rank() < plan.Operations[j].rank()
+	})
+	return plan, nil
+}
+
+/*
+ * @title         Apply
+ * @description   execute the operations of plan in order, it stops at the first error.
+ * @auth          author           2021-10-11
+ * @param         plan             *Plan          "the plan computed by Plan, or decoded from its json/yaml form."
+ * @return        error            error          "the failed operation and the error of firewalld."
+ */
+func (c *DbusClientSerivce) Apply(plan *Plan) (err error) {
+	c, end := c.span("Apply", 0)
+	defer end(&err)
+	return applyPlan(c, plan)
+}
+
+func applyPlan(c Firewall, plan *Plan) (err error) {
+	for index, op := range plan.Operations {
+		if err = c.Execute(op); err != nil {
+			return fmt.Errorf("operation %d (%s) failed: %v", index+1, op.String(), err)
+		}
+	}
+	return nil
+}
+
+// ZoneStateOf returns the items of zone settings in the form of DesiredState, e.g. ports as 80/tcp.
+func ZoneStateOf(settings *Settings) ZoneState {
+	return zoneStateOf(settings)
+}
+
+// zoneStateOf returns the state of all managed items of zone settings.
+func zoneStateOf(settings *Settings) ZoneState {
+	state := ZoneState{
+		Ports:        []string{},
+		Services:     append([]string{}, settings.Service...),
+		Sources:      []string{},
+		Interfaces:   []string{},
+		ForwardPorts: []string{},
+		Masquerade:   &settings.Masquerade,
+		RichRules:    []string{},
+	}
+	for _, port := range settings.Port {
+		state.Ports = append(state.Ports, port.Port+"/"+port.Protocol)
+	}
+	for _, source := range settings.Source {
+		state.Sources = append(state.Sources, sourceString(source))
+	}
+	for _, iface := range settings.Interface {
+		state.Interfaces = append(state.Interfaces, iface.Name)
+	}
+	for _, forward := range settings.ForwardPort {
+		state.ForwardPorts = append(state.ForwardPorts, ForwardPortToString(forward))
+	}
+	for _, rule := range settings.Rule {
+		state.RichRules = append(state.RichRules, rule.ToString())
+	}
+	return state
+}
+
+func (p *Plan) diffZone(zone string, permanent bool, settings *Settings, state ZoneState) (err error) {
+	op := Operation{Zone: zone, Permanent: permanent}
+
+	if state.Ports != nil {
+		var live, desired []string
+		for _, port := range settings.Port {
+			live = append(live, port.Port+"/"+port.Protocol)
+		}
+		for _, port := range state.Ports {
+			port, protocol := splitPortProtocol(port)
+			desired = append(desired, port+"/"+protocol)
+		}
+		op.Kind = KIND_PORT
+		p.diff(op, live, desired)
+	}
+
+	if state.Services != nil {
+		op.Kind = KIND_SERVICE
+		p.diff(op, settings.Service, state.Services)
+	}
+
+	if state.Sources != nil {
+		var live []string
+		for _, source := range settings.Source {
+			live = append(live, sourceString(source))
+		}
+		op.Kind = KIND_SOURCE
+		p.diff(op, live, state.Sources)
+	}
+
+	if state.Interfaces != nil {
+		var live []string
+		for _, iface := range settings.Interface {
+			live = append(live, iface.Name)
+		}
+		op.Kind = KIND_INTERFACE
+		p.diff(op, live, state.Interfaces)
+	}
+
+	if state.ForwardPorts != nil {
+		var live, desired []string
+		for _, forward := range settings.ForwardPort {
+			live = append(live, ForwardPortToString(forward))
+		}
+		for _, value := range state.ForwardPorts {
+			var forward ForwardPort
+			if forward, err = StringToForwardPort(value); err != nil {
+				return err
+			}
+			desired = append(desired, ForwardPortToString(forward))
+		}
+		op.Kind = KIND_FORWARDPORT
+		p.diff(op, live, desired)
+	}
+
+	if state.Masquerade != nil && *state.Masquerade != settings.Masquerade {
+		op.Kind = KIND_MASQUERADE
+		op.Action = OP_REMOVE
+		if *state.Masquerade {
+			op.Action = OP_ADD
+		}
+		p.Operations = append(p.Operations, op)
+	}
+
+	if state.RichRules != nil {
+		var live, desired []string
+		for _, rule := range settings.Rule {
+			live = append(live, rule.ToString())
+		}
+		// rules the parser does not understand, e.g. of an ipset destination, are compared as written.
+		for _, value := range state.RichRules {
+			desired = append(desired, StringToRule(value).ToString())
+		}
+		op.Kind = KIND_RICHRULE
+		p.diff(op, live, desired)
+	}
+	return nil
+}
+
+func (op Operation) rank() (rank int) {
+	if !op.Permanent {
+		rank = 10
+	}
+	switch {
+	case op.Kind == KIND_ZONE, op.Kind == KIND_IPSET:
+		return rank
+	case op.Kind == KIND_DEFAULTZONE:
+		return 20
+	case op.Action == OP_REMOVE:
+		return rank + 1
+	}
+	return rank + 2
+}
+
+// diff appends removals of live items not in desired, then additions of desired items not in live.
+func (p *Plan) diff(op Operation, live, desired []string) {
+	for _, value := range live {
+		if !contains(desired, value) {
+			op.Action, op.Value = OP_REMOVE, value
+			p.Operations = append(p.Operations, op)
+		}
+	}
+	for _, value := range desired {
+		if !contains(live, value) {
+			op.Action, op.Value = OP_ADD, value
+			p.Operations = append(p.Operations, op)
+			live = append(live, value)
+		}
+	}
+}
+
+func contains(slice []string, value string) bool {
+	for _, item := range slice {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func zoneStateNames(m map[string]ZoneState) (names []string) {
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func ipsetStateNames(m map[string]IPSetState) (names []string) {
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
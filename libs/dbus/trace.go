@@ -0,0 +1,62 @@
+package dbus
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TRACER_NAME is the instrumentation name of the spans of the clients.
+const TRACER_NAME = "github.com/cylonchau/gofirewallder/libs/dbus"
+
+// the attributes of the spans.
+const (
+	ATTR_HOST       = attribute.Key("firewalld.host")
+	ATTR_ZONE       = attribute.Key("firewalld.zone")
+	ATTR_METHOD     = attribute.Key("firewalld.method")
+	ATTR_SCOPE      = attribute.Key("firewalld.scope")
+	ATTR_ERROR_CODE = attribute.Key("firewalld.error_code")
+)
+
+/*
+ * @title         span
+ * @description   start the span of a method of the client, e.g. firewalld.AddPort, as a child of the span of the
+ *                  context of the client, see WithContext. The spans come from the global provider of
+ *                  go.opentelemetry.io/otel, none are recorded unless one is set, e.g. for tests
+ *
+ *                  exporter := tracetest.NewInMemoryExporter()
+ *                  otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+ *
+ * @auth          author           2021-10-18
+ * @param         method           string         "the name of the method, e.g. AddPort."
+ * @param         scope            Scope          "the configuration the method works on, none if 0."
+ * @return        client           *DbusClientSerivce "a copy of c whose calls run in the span, nested methods are its children."
+ * @return        end              func(*error)   "ends the span, with the error and its firewalld code if it is not nil."
+ */
+func (c *DbusClientSerivce) span(method string, scope Scope, attributes ...attribute.KeyValue) (client *DbusClientSerivce, end func(err *error)) {
+	attributes = append(attributes, ATTR_HOST.String(c.addr), ATTR_METHOD.String(method))
+	if scope != 0 {
+		attributes = append(attributes, ATTR_SCOPE.String(scope.String()))
+	}
+	ctx, span := otel.Tracer(TRACER_NAME).Start(c.callContext(), "firewalld."+method,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+	return c.WithContext(ctx), func(err *error) {
+		if err != nil && *err != nil {
+			if code := ErrorCode(*err); code != "" {
+				span.SetAttributes(ATTR_ERROR_CODE.String(code))
+			}
+			span.RecordError(*err)
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+		span.End()
+	}
+}
+
+// zoneSpan starts the span of a method of zone, the empty zone is the default zone.
+func (c *DbusClientSerivce) zoneSpan(method string, scope Scope, zone string) (client *DbusClientSerivce, end func(err *error)) {
+	if zone == "" {
+		zone = c.GetDefaultZone()
+	}
+	return c.span(method, scope, ATTR_ZONE.String(zone))
+}
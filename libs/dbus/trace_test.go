@@ -0,0 +1,52 @@
+package dbus_test
+
+import (
+	"testing"
+
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/libs/dbustest"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestClientSpans(t *testing.T) {
+	server, err := dbustest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	spans := dbustest.RecordSpans(t)
+
+	if err = client.PermanentAddPort("8080/tcp", "public"); err != nil {
+		t.Fatalf("PermanentAddPort: %v", err)
+	}
+	span := spans.Named("firewalld.PermanentAddPort")
+	if zone := dbustest.AttributeOf(span, dbus.ATTR_ZONE); zone != "public" {
+		t.Errorf("zone attribute: got %q, want public", zone)
+	}
+	if scope := dbustest.AttributeOf(span, dbus.ATTR_SCOPE); scope != dbus.SCOPE_PERMANENT.String() {
+		t.Errorf("scope attribute: got %q, want permanent", scope)
+	}
+	if span.Status().Code != codes.Unset {
+		t.Errorf("status: got %v, want unset", span.Status())
+	}
+	lookup := spans.Named("firewalld.zonePath")
+	if lookup.Parent().SpanID() != span.SpanContext().SpanID() {
+		t.Error("zonePath is not a child of PermanentAddPort")
+	}
+
+	if err = client.PermanentAddPort("8080/tcp", "public"); err == nil {
+		t.Fatal("PermanentAddPort twice: no error")
+	}
+	span = spans.Named("firewalld.PermanentAddPort")
+	if span.Status().Code != codes.Error {
+		t.Errorf("status: got %v, want error", span.Status())
+	}
+	if code := dbustest.AttributeOf(span, dbus.ATTR_ERROR_CODE); code != "ALREADY_ENABLED" {
+		t.Errorf("error code attribute: got %q, want ALREADY_ENABLED", code)
+	}
+}
@@ -0,0 +1,178 @@
+package dbus
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+ * Tx executes operations in order, if one fails the completed ones are undone in reverse order, e.g.
+ *
+ *   tx := client.NewTx(
+ *       Operation{Action: OP_ADD, Kind: KIND_PORT, Zone: "public", Value: "8080/tcp", Permanent: true},
+ *       Operation{Action: OP_ADD, Kind: KIND_RICHRULE, Zone: "public", Value: `rule family="ipv4" source address="10.0.0.0/8" accept`, Permanent: true},
+ *       Operation{Kind: KIND_RELOAD},
+ *   )
+ *   err := tx.Commit()
+ *
+ * A completed reload is undone by reloading again after the other operations are undone, a removed zone or ipset is
+ * added again with the settings it had before.
+ */
+type Tx struct {
+	Operations []Operation
+
+	client    Firewall
+	committed bool
+	done      []Operation
+	inverses  []txInverse
+	reloaded  bool
+}
+
+// txInverse undoes a completed operation, restore is called after the operation, e.g. to set the settings of a removed zone.
+type txInverse struct {
+	Operation
+	restore func() error
+}
+
+/*
+ * TxError is returned by Commit when an operation fails.
+ *   Index            index of the failed operation in Operations
+ *   RolledBack       number of completed operations that were undone
+ *   RollbackErrors   the undo operations that failed, the host is left half-configured if not empty
+ */
+type TxError struct {
+	Index          int
+	Operation      Operation
+	Err            error
+	RolledBack     int
+	RollbackErrors []error
+}
+
+func (e *TxError) Error() string {
+	str := fmt.Sprintf("operation %d (%s) failed: %v; ", e.Index+1, e.Operation.String(), e.Err)
+	if len(e.RollbackErrors) > 0 {
+		var failures []string
+		for _, err := range e.RollbackErrors {
+			failures = append(failures, err.Error())
+		}
+		return str + fmt.Sprintf("rolled back %d operation(s), rollback failed: %s", e.RolledBack, strings.Join(failures, "; "))
+	}
+	return str + fmt.Sprintf("rolled back %d operation(s)", e.RolledBack)
+}
+
+func (e *TxError) Unwrap() error {
+	return e.Err
+}
+
+// NewTx returns a transaction executing ops, more operations can be added with Add before Commit.
+func (c *DbusClientSerivce) NewTx(ops ...Operation) *Tx {
+	return &Tx{Operations: ops, client: c}
+}
+
+// Add appends operations to the transaction.
+func (tx *Tx) Add(ops ...Operation) *Tx {
+	tx.Operations = append(tx.Operations, ops...)
+	return tx
+}
+
+/*
+ * @title         Commit
+ * @description   execute the operations in order, on the first error undo the completed ones in reverse order.
+ * @auth          author           2021-10-15
+ * @return        error            error          "*TxError, the failed operation and the outcome of the rollback."
+ */
+func (tx *Tx) Commit() (err error) {
+	if tx.committed {
+		return fmt.Errorf("INVALID_COMMAND: transaction already committed")
+	}
+	tx.committed = true
+
+	for index, op := range tx.Operations {
+		inverse, ok := tx.inverse(op)
+		if err = tx.client.Execute(op); err != nil {
+			txErr := &TxError{Index: index, Operation: op, Err: err}
+			txErr.RolledBack, txErr.RollbackErrors = tx.rollback()
+			return txErr
+		}
+		tx.done = append(tx.done, op)
+		if op.Kind == KIND_RELOAD {
+			tx.reloaded = true
+		}
+		if ok {
+			tx.inverses = append(tx.inverses, inverse)
+		}
+	}
+	return nil
+}
+
+/*
+ * @title         Rollback
+ * @description   undo the operations completed by Commit, e.g. when a later check of the caller fails.
+ * @auth          author           2021-10-15
+ * @return        error            error          "the undo operations that failed."
+ */
+func (tx *Tx) Rollback() (err error) {
+	if _, errs := tx.rollback(); len(errs) > 0 {
+		var failures []string
+		for _, err := range errs {
+			failures = append(failures, err.Error())
+		}
+		return fmt.Errorf("rollback failed: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// Done returns the operations completed by Commit.
+func (tx *Tx) Done() []Operation {
+	return tx.done
+}
+
+func (tx *Tx) rollback() (rolledBack int, errs []error) {
+	for index := len(tx.inverses) - 1; index >= 0; index-- {
+		op := tx.inverses[index]
+		err := tx.client.Execute(op.Operation)
+		if err == nil && op.restore != nil {
+			err = op.restore()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", op.String(), err))
+			continue
+		}
+		rolledBack++
+	}
+	if tx.reloaded {
+		if err := tx.client.Reload(); err != nil {
+			errs = append(errs, fmt.Errorf("reload: %v", err))
+		}
+	}
+	tx.done, tx.inverses, tx.reloaded = nil, nil, false
+	return rolledBack, errs
+}
+
+// inverse records the inverse of op before it is executed, filling in the state op would lose.
+func (tx *Tx) inverse(op Operation) (inverse txInverse, ok bool) {
+	switch {
+	case op.Kind == KIND_DEFAULTZONE && op.Previous == "":
+		op.Previous = tx.client.GetDefaultZone()
+	case op.Kind == KIND_TARGET && op.Previous == "":
+		if settings, err := tx.client.PermanentGetZoneSettings(op.Zone); err == nil {
+			op.Previous = settings.Targe
+		}
+	case op.Kind == KIND_ZONE && op.Action == OP_REMOVE:
+		settings, err := tx.client.PermanentGetZoneSettings(op.Zone)
+		if err != nil {
+			return inverse, false
+		}
+		op.Action = OP_ADD
+		return txInverse{Operation: op, restore: func() error { return tx.client.PermanentSetZoneSettings(op.Zone, settings) }}, true
+	case op.Kind == KIND_IPSET && op.Action == OP_REMOVE:
+		settings, err := tx.client.PermanentGetIPSetSettings(op.IPSet)
+		if err != nil {
+			return inverse, false
+		}
+		op.Action, op.Value = OP_ADD, settings.Type
+		return txInverse{Operation: op, restore: func() error { return tx.client.PermanentSetIPSetSettings(op.IPSet, settings) }}, true
+	}
+	inverse.Operation, ok = op.Inverse()
+	return inverse, ok
+}
@@ -4,8 +4,55 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+
+	"github.com/godbus/dbus/v5"
 )
 
+// isUnknownMethod reports whether err is the D-Bus error of a method this firewalld version does not have.
+func isUnknownMethod(err error) bool {
+	var dbusErr dbus.Error
+	if errors.As(err, &dbusErr) {
+		return dbusErr.Name == "org.freedesktop.DBus.Error.UnknownMethod"
+	}
+	return false
+}
+
+/*
+ * @title         ErrorCode
+ * @description   the firewalld error code of err, e.g. ALREADY_ENABLED for "ALREADY_ENABLED: 8080:tcp",
+ *                  INVALID_ZONE for the "invalid zone." of the client, empty if err carries no code.
+ * @auth          author           2021-10-18
+ * @param         err              error          ""
+ * @return        code             string         ""
+ */
+func ErrorCode(err error) (code string) {
+	if err == nil {
+		return ""
+	}
+	message := err.Error()
+	if strings.HasPrefix(message, "invalid ") {
+		message = strings.TrimSuffix(message, ".")
+		if strings.Count(message, " ") == 1 {
+			return strings.ToUpper(strings.Replace(message, " ", "_", 1))
+		}
+		return ""
+	}
+	index := strings.Index(message, ":")
+	if index < 0 {
+		index = len(message)
+	}
+	code = message[:index]
+	if code == "" {
+		return ""
+	}
+	for _, char := range code {
+		if (char < 'A' || char > 'Z') && char != '_' {
+			return ""
+		}
+	}
+	return code
+}
+
 func (c *DbusClientSerivce) checkZoneName(name string) error {
 	if len(name) > 17 {
 		return errors.New("zone_name is limited to 17 chars.")
@@ -21,10 +68,6 @@ func splitPortProtocol(portProtocol string) (port, protocol string) {
 	return portProtocol, "tcp"
 }
 
-func removeSliceElement(s []string, index int) []string {
-	return append(s[:index], s[index+1:]...)
-}
-
 func checkPort(portProtocol string) (err error) {
 	if strings.Contains(portProtocol, "/") {
 		slices := strings.Split(portProtocol, "/")
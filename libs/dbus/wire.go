@@ -0,0 +1,101 @@
+package dbus
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+ * The wire forms below are the settings as they travel in D-Bus bodies of firewalld,
+ * for servers speaking its API, e.g. the fake firewalld of libs/dbustest.
+ * A wire form decoded from a message body, structs are []interface{}, is converted back with the Of functions.
+ */
+
+// ZoneSettingsWire returns settings in the form of getZoneSettings, (sssbsasa(ss)asba(ssss)asasasasa(ss)b).
+func ZoneSettingsWire(settings *Settings) interface{} {
+	return settings.toTuple()
+}
+
+// ZoneSettingsOfWire converts the form of getZoneSettings into Settings.
+func ZoneSettingsOfWire(value interface{}) (*Settings, error) {
+	var raw zoneSettings
+	if err := dbus.Store([]interface{}{value}, &raw); err != nil {
+		return nil, err
+	}
+	return raw.toSettings(), nil
+}
+
+// ServiceSettingsWire returns settings in the form of getServiceSettings, (sssa(ss)asa{ss}).
+func ServiceSettingsWire(settings *ServiceSettings) interface{} {
+	return settings.toTuple()
+}
+
+// ServiceSettingsOfWire converts the form of getServiceSettings into ServiceSettings.
+func ServiceSettingsOfWire(value interface{}) (*ServiceSettings, error) {
+	var raw serviceSettings
+	if err := dbus.Store([]interface{}{value}, &raw); err != nil {
+		return nil, err
+	}
+	return raw.toServiceSettings(), nil
+}
+
+// ServiceSettingsDict returns settings in the a{sv} form of getSettings2.
+func ServiceSettingsDict(settings *ServiceSettings) map[string]dbus.Variant {
+	return settings.toDict()
+}
+
+// ServiceSettingsOfDict converts the a{sv} form of getSettings2 into ServiceSettings.
+func ServiceSettingsOfDict(value interface{}) (*ServiceSettings, error) {
+	var dict map[string]dbus.Variant
+	if err := dbus.Store([]interface{}{value}, &dict); err != nil {
+		return nil, err
+	}
+	return serviceSettingsOfDict(dict)
+}
+
+// IPSetSettingsWire returns settings in the form of config.ipset getSettings, (ssssa{ss}as).
+func IPSetSettingsWire(settings *IPSetSettings) interface{} {
+	return settings.toTuple()
+}
+
+// IPSetSettingsOfWire converts the form of config.ipset getSettings into IPSetSettings.
+func IPSetSettingsOfWire(value interface{}) (*IPSetSettings, error) {
+	var raw ipsetSettings
+	if err := dbus.Store([]interface{}{value}, &raw); err != nil {
+		return nil, err
+	}
+	return raw.toIPSetSettings(), nil
+}
+
+// PolicySettingsDict returns settings in the a{sv} form of getPolicySettings.
+func PolicySettingsDict(settings *PolicySettings) map[string]dbus.Variant {
+	return settings.toDict()
+}
+
+// PolicySettingsOfDict converts the a{sv} form of getPolicySettings into PolicySettings.
+func PolicySettingsOfDict(value interface{}) (*PolicySettings, error) {
+	var dict map[string]dbus.Variant
+	if err := dbus.Store([]interface{}{value}, &dict); err != nil {
+		return nil, err
+	}
+	return policySettingsOfDict(dict)
+}
+
+// DirectSettingsWire returns settings in the form of config.direct getSettings, (a(sss)a(sssias)a(sas)).
+func DirectSettingsWire(settings *DirectSettings) interface{} {
+	return settings.toTuple()
+}
+
+// DirectSettingsOfWire converts the form of config.direct getSettings into DirectSettings.
+func DirectSettingsOfWire(value interface{}) (*DirectSettings, error) {
+	var raw directSettings
+	if err := dbus.Store([]interface{}{value}, &raw); err != nil {
+		return nil, err
+	}
+	return raw.toDirectSettings(), nil
+}
+
+// DirectSettingsLists returns the chains a(sss), rules a(sssias) and passthroughs a(sas) of the direct interface.
+func DirectSettingsLists(settings *DirectSettings) (chains, rules, passthroughs interface{}) {
+	tuple := settings.toTuple()
+	return tuple.Chains, tuple.Rules, tuple.Passthroughs
+}
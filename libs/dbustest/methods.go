@@ -0,0 +1,880 @@
+package dbustest
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/object"
+	godbus "github.com/godbus/dbus/v5"
+)
+
+// object paths of the config objects, an index into the names is appended, e.g. /org/fedoraproject/FirewallD1/config/ipset/0
+const (
+	IPSET_PATH   = object.CONFIG_PATH + "/ipset"
+	SERVICE_PATH = object.CONFIG_PATH + "/service"
+	POLICY_PATH  = object.CONFIG_PATH + "/policy"
+)
+
+// request is a method call to a firewalld object, the arg methods record the first argument of a wrong type in err.
+type request struct {
+	server *Server
+	fw     *dbus.MemoryFirewall
+	path   godbus.ObjectPath
+	args   []interface{}
+	err    error
+}
+
+type handler func(r *request) (body []interface{}, err error)
+
+// methodPaths is the object path of the methods that do not take the object from the path, the others are on a config object.
+var methodPaths = map[string]string{
+	object.INTERFACE:                 object.PATH,
+	object.ZONE:                      object.PATH,
+	object.IPSET:                     object.PATH,
+	object.POLICY:                    object.PATH,
+	object.DIRECT:                    object.PATH,
+	object.CONFIG_INTERFACE:          object.CONFIG_PATH,
+	object.CONFIG_DIRECT_INTERFACE:   object.CONFIG_PATH,
+	object.CONFIG_POLICIES_INTERFACE: object.CONFIG_PATH,
+}
+
+// dispatch runs method on the object at path, unknown methods are the UnknownMethod error of D-Bus.
+func (s *Server) dispatch(path godbus.ObjectPath, method string, args []interface{}) (body []interface{}, err error) {
+	handle, ok := methods[method]
+	if !ok {
+		return nil, godbus.Error{Name: ERROR_UNKNOWN_METHOD, Body: []interface{}{"unknown method " + method}}
+	}
+	iface := method[:strings.LastIndex(method, ".")]
+	if want, ok := methodPaths[iface]; ok && string(path) != want {
+		return nil, godbus.Error{Name: ERROR_UNKNOWN_OBJECT, Body: []interface{}{"no " + iface + " at " + string(path)}}
+	}
+
+	r := &request{server: s, fw: s.Firewall, path: path, args: args}
+	body, err = handle(r)
+	if r.err != nil {
+		return nil, godbus.Error{Name: ERROR_INVALID_ARGS, Body: []interface{}{r.err.Error()}}
+	}
+	return body, err
+}
+
+func (r *request) arg(index int) interface{} {
+	if index >= len(r.args) {
+		r.fail("missing argument %d", index)
+		return nil
+	}
+	return r.args[index]
+}
+
+func (r *request) fail(format string, args ...interface{}) {
+	if r.err == nil {
+		r.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (r *request) str(index int) string {
+	value, ok := r.arg(index).(string)
+	if !ok {
+		r.fail("argument %d is not a string", index)
+	}
+	return value
+}
+
+func (r *request) strs(index int) []string {
+	value, ok := r.arg(index).([]string)
+	if !ok {
+		r.fail("argument %d is not an array of strings", index)
+	}
+	return value
+}
+
+// int accepts every integer type, godbus sends int as int64 and firewalld declares int32.
+func (r *request) int(index int) int {
+	value := reflect.ValueOf(r.arg(index))
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(value.Uint())
+	}
+	r.fail("argument %d is not an integer", index)
+	return 0
+}
+
+// name returns the name of the config object at the path, below base, e.g. public for /config/zone/3.
+func (r *request) name(base string, names []string) (string, error) {
+	index, ok := r.id(base)
+	if !ok || index >= len(names) {
+		return "", r.unknownObject()
+	}
+	return names[index], nil
+}
+
+// id returns the number of the config object at the path, below base.
+func (r *request) id(base string) (int, bool) {
+	id, err := strconv.Atoi(strings.TrimPrefix(string(r.path), base+"/"))
+	return id, err == nil && strings.HasPrefix(string(r.path), base+"/") && id >= 0
+}
+
+func (r *request) unknownObject() error {
+	return godbus.Error{Name: ERROR_UNKNOWN_OBJECT, Body: []interface{}{"no object at " + string(r.path)}}
+}
+
+func (r *request) zone() (string, error) {
+	if id, ok := r.id(object.ZONE_PATH); ok {
+		for name, value := range r.server.zoneIds() {
+			if value == id {
+				return name, nil
+			}
+		}
+	}
+	return "", r.unknownObject()
+}
+
+func (r *request) ipset() (string, error) {
+	names, _ := r.fw.PermanentGetIPSets()
+	return r.name(IPSET_PATH, names)
+}
+
+func (r *request) service() (string, error) {
+	names, _ := r.fw.PermanentGetServices()
+	return r.name(SERVICE_PATH, names)
+}
+
+func (r *request) policy() (string, error) {
+	names, _ := r.fw.PermanentGetPolicies()
+	return r.name(POLICY_PATH, names)
+}
+
+// config returns the kind and the name of the config object at the path, iface is the interface of the object.
+func (r *request) config(iface string) (kind, name string, err error) {
+	switch iface {
+	case object.CONFIG_ZONE:
+		name, err = r.zone()
+		return dbus.KIND_ZONE, name, err
+	case object.CONFIG_IPSET:
+		name, err = r.ipset()
+		return dbus.KIND_IPSET, name, err
+	case object.CONFIG_SERVICE:
+		name, err = r.service()
+		return dbus.KIND_SERVICE, name, err
+	case object.CONFIG_POLICY:
+		name, err = r.policy()
+		return dbus.KIND_POLICY, name, err
+	}
+	return "", "", godbus.Error{Name: ERROR_UNKNOWN_OBJECT, Body: []interface{}{"no " + iface + " at " + string(r.path)}}
+}
+
+/*
+ * zoneIds numbers the zones like firewalld numbers its config objects: a zone gets the next id when it is first seen,
+ * the predefined zones in sorted order, and keeps it until it is gone from runtime and permanent, ids are not reused.
+ */
+func (s *Server) zoneIds() map[string]int {
+	names, _ := s.Firewall.GetZones()
+	permanent, _ := s.Firewall.PermanentGetZones()
+	for _, name := range permanent {
+		if indexOf(names, name) < 0 {
+			names = append(names, name)
+		}
+	}
+
+	s.zoneLock.Lock()
+	defer s.zoneLock.Unlock()
+	for name := range s.zones {
+		if indexOf(names, name) < 0 {
+			delete(s.zones, name)
+		}
+	}
+	ids := map[string]int{}
+	for _, name := range names {
+		if _, ok := s.zones[name]; !ok {
+			s.zones[name] = s.nextZone
+			s.nextZone++
+		}
+		ids[name] = s.zones[name]
+	}
+	return ids
+}
+
+// zonePath is the reply of getZoneByName.
+func (s *Server) zonePath(name string) ([]interface{}, error) {
+	id, ok := s.zoneIds()[name]
+	if !ok {
+		return nil, godbus.Error{Name: object.EXCEPTION, Body: []interface{}{"INVALID_ZONE: " + name}}
+	}
+	return []interface{}{godbus.ObjectPath(fmt.Sprintf("%s/%d", object.ZONE_PATH, id))}, nil
+}
+
+// pathOf returns the path of name below base, names are in the order of the paths.
+func pathOf(base string, names []string, name, code string) ([]interface{}, error) {
+	index := indexOf(names, name)
+	if index < 0 {
+		return nil, godbus.Error{Name: object.EXCEPTION, Body: []interface{}{code + ": " + name}}
+	}
+	return []interface{}{godbus.ObjectPath(fmt.Sprintf("%s/%d", base, index))}, nil
+}
+
+func indexOf(list []string, value string) int {
+	for index, item := range list {
+		if item == value {
+			return index
+		}
+	}
+	return -1
+}
+
+// zoneOf is the zone argument of a runtime method, the empty string is the default zone.
+func (r *request) zoneOf(index int) string {
+	zone := r.str(index)
+	if zone == "" {
+		zone = r.fw.GetDefaultZone()
+	}
+	return zone
+}
+
+func one(value interface{}, err error) ([]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{value}, nil
+}
+
+func none(err error) ([]interface{}, error) {
+	return nil, err
+}
+
+// rule decodes the rich rule argument, rules firewalld knows and ParseRule does not are kept as written, see
+// StringToRule, an argument not starting with rule is INVALID_RULE.
+func (r *request) rule(index int) (*dbus.Rule, error) {
+	str := strings.TrimSpace(r.str(index))
+	if fields := strings.Fields(str); len(fields) == 0 || !strings.EqualFold(fields[0], "rule") {
+		return nil, godbus.Error{Name: object.EXCEPTION, Body: []interface{}{"INVALID_RULE: " + str}}
+	}
+	return dbus.StringToRule(str), nil
+}
+
+// forward returns the forward port arguments from index on, port, protocol, toport and toaddr, as the client passes them.
+func (r *request) forward(index int) (portProtocol, toHostPort string) {
+	return r.str(index) + "/" + r.str(index+1), net.JoinHostPort(r.str(index+3), r.str(index+2))
+}
+
+type portTuple struct {
+	Port     string
+	Protocol string
+}
+
+var methods = map[string]handler{
+	/************************************************** root ***********************************************************/
+	object.INTERFACE_GETDEFAULTZONE: func(r *request) ([]interface{}, error) {
+		return []interface{}{r.fw.GetDefaultZone()}, nil
+	},
+	object.INTERFACE_SETDEFAULTZONE: func(r *request) ([]interface{}, error) {
+		return none(r.fw.SetDefaultZone(r.str(0)))
+	},
+	object.INTERFACE_GETZONESETTINGS: func(r *request) ([]interface{}, error) {
+		settings, err := r.fw.GetZoneSettings(r.str(0))
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{dbus.ZoneSettingsWire(settings)}, nil
+	},
+	object.INTERFACE_GETSERVICESETTINGS: func(r *request) ([]interface{}, error) {
+		settings, err := r.fw.GetServiceSettings(r.str(0))
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{dbus.ServiceSettingsWire(settings)}, nil
+	},
+	object.INTERFACE_RELOAD: func(r *request) ([]interface{}, error) {
+		return none(r.fw.Reload())
+	},
+	object.INTERFACE + ".reload": func(r *request) ([]interface{}, error) {
+		return none(r.fw.Reload())
+	},
+	object.INTERFACE_RUNTIMETOPERMANENT: func(r *request) ([]interface{}, error) {
+		return none(r.fw.RuntimeToPermanent())
+	},
+	object.INTERFACE_QUERYPANICMODE: func(r *request) ([]interface{}, error) {
+		return one(r.fw.QueryPanicMode())
+	},
+	object.PROPERTIES_GET: func(r *request) ([]interface{}, error) {
+		iface, name := r.str(0), r.str(1)
+		if r.path == object.PATH && iface == object.INTERFACE && name == object.PROPERTY_STATE {
+			state, err := r.fw.GetState()
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{godbus.MakeVariant(state)}, nil
+		}
+		if name != object.PROPERTY_NAME && name != object.PROPERTY_BUILTIN && name != object.PROPERTY_DEFAULT {
+			return nil, godbus.Error{Name: ERROR_INVALID_ARGS, Body: []interface{}{"no property " + iface + "." + name}}
+		}
+		kind, config, err := r.config(iface)
+		if err != nil {
+			return nil, err
+		}
+		if name == object.PROPERTY_NAME {
+			return []interface{}{godbus.MakeVariant(config)}, nil
+		}
+		builtin, isDefault, err := r.fw.PermanentGetBuiltin(kind, config)
+		if err != nil {
+			return nil, err
+		}
+		if name == object.PROPERTY_BUILTIN {
+			return []interface{}{godbus.MakeVariant(builtin)}, nil
+		}
+		return []interface{}{godbus.MakeVariant(isDefault)}, nil
+	},
+
+	/************************************************** runtime zone ***********************************************************/
+	object.ZONE_GETZONES: func(r *request) ([]interface{}, error) {
+		return one(r.fw.GetZones())
+	},
+	object.ZONE_GETZONEOFINTERFACE: func(r *request) ([]interface{}, error) {
+		return []interface{}{r.fw.GetZoneOfInterface(r.str(0))}, nil
+	},
+	object.ZONE_ADDPORT: func(r *request) ([]interface{}, error) {
+		return one(r.fw.AddPort(r.str(1)+"/"+r.str(2), r.str(0), r.int(3)))
+	},
+	object.ZONE_REMOVEPORT: func(r *request) ([]interface{}, error) {
+		zone := r.zoneOf(0)
+		_, err := r.fw.RemovePort(r.str(1)+"/"+r.str(2), zone)
+		return one(zone, err)
+	},
+	object.ZONE_GETPORTS: func(r *request) ([]interface{}, error) {
+		ports, err := r.fw.GetPort(r.str(0))
+		list := [][]string{}
+		for _, port := range ports {
+			list = append(list, []string{port.Port, port.Protocol})
+		}
+		return one(list, err)
+	},
+	object.ZONE_ADDPROTOCOL: func(r *request) ([]interface{}, error) {
+		return one(r.fw.AddProtocol(r.str(0), r.str(1), r.int(2)))
+	},
+	object.ZONE_ADDSERVICE: func(r *request) ([]interface{}, error) {
+		return one(r.fw.AddService(r.str(0), r.str(1), r.int(2)))
+	},
+	object.ZONE_REMOVESERVICE: func(r *request) ([]interface{}, error) {
+		zone := r.zoneOf(0)
+		return one(zone, r.fw.RemoveService(zone, r.str(1)))
+	},
+	object.ZONE_QUERYSERVICE: func(r *request) ([]interface{}, error) {
+		return one(r.fw.Zone(r.zoneOf(0)).QueryService(dbus.SCOPE_RUNTIME, r.str(1)))
+	},
+	object.ZONE_ADDMASQUERADE: func(r *request) ([]interface{}, error) {
+		zone := r.zoneOf(0)
+		return one(zone, r.fw.EnableMasquerade(zone, r.int(1)))
+	},
+	object.ZONE_REMOVEMASQUERADE: func(r *request) ([]interface{}, error) {
+		zone := r.zoneOf(0)
+		return one(zone, r.fw.DisableMasquerade(zone))
+	},
+	object.ZONE_QUERYMASQUERADE: func(r *request) ([]interface{}, error) {
+		return one(r.fw.QueryMasquerade(r.zoneOf(0)))
+	},
+	object.ZONE_ADDINTERFACE: func(r *request) ([]interface{}, error) {
+		return one(r.fw.BindInterface(r.zoneOf(0), r.str(1)))
+	},
+	object.ZONE_REMOVEINTERFACE: func(r *request) ([]interface{}, error) {
+		zone := r.zoneOf(0)
+		return one(zone, r.fw.RemoveInterface(zone, r.str(1)))
+	},
+	object.ZONE_QUERYINTERFACE: func(r *request) ([]interface{}, error) {
+		return one(r.fw.QueryInterface(r.zoneOf(0), r.str(1)))
+	},
+	object.ZONE_ADDSOURCE: func(r *request) ([]interface{}, error) {
+		return one(r.fw.AddSource(r.zoneOf(0), r.str(1)))
+	},
+	object.ZONE_REMOVESOURCE: func(r *request) ([]interface{}, error) {
+		zone := r.zoneOf(0)
+		return one(zone, r.fw.RemoveSource(zone, r.str(1)))
+	},
+	object.ZONE_QUERYSOURCE: func(r *request) ([]interface{}, error) {
+		return one(r.fw.QuerySource(r.zoneOf(0), r.str(1)))
+	},
+	object.ZONE_ADDFORWARDPORT: func(r *request) ([]interface{}, error) {
+		zone := r.zoneOf(0)
+		portProtocol, toHostPort := r.forward(1)
+		return one(zone, r.fw.AddForwardPort(zone, portProtocol, toHostPort, r.int(5)))
+	},
+	object.ZONE_REMOVEFORWARDPORT: func(r *request) ([]interface{}, error) {
+		zone := r.zoneOf(0)
+		portProtocol, toHostPort := r.forward(1)
+		return one(zone, r.fw.RemoveForwardPort(zone, portProtocol, toHostPort))
+	},
+	object.ZONE_QUERYFORWARDPORT: func(r *request) ([]interface{}, error) {
+		portProtocol, toHostPort := r.forward(1)
+		return one(r.fw.Zone(r.zoneOf(0)).QueryForwardPort(dbus.SCOPE_RUNTIME, portProtocol, toHostPort))
+	},
+	object.ZONE_GETRICHRULES: func(r *request) ([]interface{}, error) {
+		rules, err := r.fw.GetRichRules(r.str(0))
+		list := []string{}
+		for _, rule := range rules {
+			list = append(list, rule.ToString())
+		}
+		return one(list, err)
+	},
+	object.ZONE_ADDRICHRULE: func(r *request) ([]interface{}, error) {
+		zone := r.zoneOf(0)
+		rule, err := r.rule(1)
+		if err != nil {
+			return nil, err
+		}
+		return one(zone, r.fw.AddRichRule(zone, rule, r.int(2)))
+	},
+	object.ZONE_REOMVERICHRULE: func(r *request) ([]interface{}, error) {
+		zone := r.zoneOf(0)
+		rule, err := r.rule(1)
+		if err != nil {
+			return nil, err
+		}
+		return one(zone, r.fw.RemoveRichRule(zone, rule))
+	},
+	object.ZONE_QUERYRICHRULE: func(r *request) ([]interface{}, error) {
+		rule, err := r.rule(1)
+		if err != nil {
+			return nil, err
+		}
+		return one(r.fw.Zone(r.zoneOf(0)).QueryRichRule(dbus.SCOPE_RUNTIME, rule))
+	},
+
+	/************************************************** runtime ipset, policy and direct ***********************************************************/
+	object.IPSET_GETIPSETS: func(r *request) ([]interface{}, error) {
+		return one(r.fw.GetIPSets())
+	},
+	object.IPSET_GETENTRIES: func(r *request) ([]interface{}, error) {
+		return one(r.fw.GetIPSetEntries(r.str(0)))
+	},
+	object.IPSET_ADDENTRY: func(r *request) ([]interface{}, error) {
+		return none(r.fw.AddIPSetEntry(r.str(0), r.str(1)))
+	},
+	object.IPSET_REMOVEENTRY: func(r *request) ([]interface{}, error) {
+		return none(r.fw.RemoveIPSetEntry(r.str(0), r.str(1)))
+	},
+	object.IPSET_QUERYENTRY: func(r *request) ([]interface{}, error) {
+		return one(r.fw.QueryIPSetEntry(r.str(0), r.str(1)))
+	},
+	object.POLICY_GETPOLICIES: func(r *request) ([]interface{}, error) {
+		return one(r.fw.GetPolicies())
+	},
+	object.POLICY_GETPOLICYSETTINGS: func(r *request) ([]interface{}, error) {
+		settings, err := r.fw.GetPolicySettings(r.str(0))
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{dbus.PolicySettingsDict(settings)}, nil
+	},
+	object.POLICY_SETPOLICYSETTINGS: func(r *request) ([]interface{}, error) {
+		settings, err := dbus.PolicySettingsOfDict(r.arg(1))
+		if err != nil {
+			r.fail("%v", err)
+			return nil, nil
+		}
+		return none(r.fw.SetPolicySettings(r.str(0), settings))
+	},
+	object.DIRECT_GETALLCHAINS: func(r *request) ([]interface{}, error) {
+		settings, err := r.fw.GetDirectSettings()
+		if err != nil {
+			return nil, err
+		}
+		chains, _, _ := dbus.DirectSettingsLists(settings)
+		return []interface{}{chains}, nil
+	},
+	object.DIRECT_GETALLRULES: func(r *request) ([]interface{}, error) {
+		settings, err := r.fw.GetDirectSettings()
+		if err != nil {
+			return nil, err
+		}
+		_, rules, _ := dbus.DirectSettingsLists(settings)
+		return []interface{}{rules}, nil
+	},
+	object.DIRECT_GETALLPASSTHROUGHS: func(r *request) ([]interface{}, error) {
+		settings, err := r.fw.GetDirectSettings()
+		if err != nil {
+			return nil, err
+		}
+		_, _, passthroughs := dbus.DirectSettingsLists(settings)
+		return []interface{}{passthroughs}, nil
+	},
+	object.DIRECT_ADDCHAIN: func(r *request) ([]interface{}, error) {
+		return none(r.fw.AddDirectChain(dbus.DirectChain{IPV: r.str(0), Table: r.str(1), Chain: r.str(2)}))
+	},
+	object.DIRECT_REMOVECHAIN: func(r *request) ([]interface{}, error) {
+		return none(r.fw.RemoveDirectChain(dbus.DirectChain{IPV: r.str(0), Table: r.str(1), Chain: r.str(2)}))
+	},
+	object.DIRECT_ADDRULE: func(r *request) ([]interface{}, error) {
+		return none(r.fw.AddDirectRule(dbus.DirectRule{IPV: r.str(0), Table: r.str(1), Chain: r.str(2), Priority: r.int(3), Args: r.strs(4)}))
+	},
+	object.DIRECT_REMOVERULE: func(r *request) ([]interface{}, error) {
+		return none(r.fw.RemoveDirectRule(dbus.DirectRule{IPV: r.str(0), Table: r.str(1), Chain: r.str(2), Priority: r.int(3), Args: r.strs(4)}))
+	},
+	object.DIRECT_ADDPASSTHROUGH: func(r *request) ([]interface{}, error) {
+		return none(r.fw.AddDirectPassthrough(dbus.DirectPassthrough{IPV: r.str(0), Args: r.strs(1)}))
+	},
+	object.DIRECT_REMOVEPASSTHROUGH: func(r *request) ([]interface{}, error) {
+		return none(r.fw.RemoveDirectPassthrough(dbus.DirectPassthrough{IPV: r.str(0), Args: r.strs(1)}))
+	},
+
+	/************************************************** config ***********************************************************/
+	object.CONFIG_GETZONENAMES: func(r *request) ([]interface{}, error) {
+		return one(r.fw.PermanentGetZones())
+	},
+	object.CONFIG_GETZONEBYNAME: func(r *request) ([]interface{}, error) {
+		return r.server.zonePath(r.str(0))
+	},
+	object.CONFIG_ADDZONE: func(r *request) ([]interface{}, error) {
+		name := r.str(0)
+		settings, err := dbus.ZoneSettingsOfWire(r.arg(1))
+		if err != nil {
+			r.fail("%v", err)
+			return nil, nil
+		}
+		if err = r.fw.AddZone(name); err != nil {
+			return nil, err
+		}
+		if err = r.fw.PermanentSetZoneSettings(name, settings); err != nil {
+			return nil, err
+		}
+		return r.server.zonePath(name)
+	},
+	object.CONFIG_GETIPSETNAMES: func(r *request) ([]interface{}, error) {
+		return one(r.fw.PermanentGetIPSets())
+	},
+	object.CONFIG_GETIPSETBYNAME: func(r *request) ([]interface{}, error) {
+		names, _ := r.fw.PermanentGetIPSets()
+		return pathOf(IPSET_PATH, names, r.str(0), "INVALID_IPSET")
+	},
+	object.CONFIG_ADDIPSET: func(r *request) ([]interface{}, error) {
+		name := r.str(0)
+		settings, err := dbus.IPSetSettingsOfWire(r.arg(1))
+		if err != nil {
+			r.fail("%v", err)
+			return nil, nil
+		}
+		if err = r.fw.PermanentAddIPSet(name, settings.Type); err != nil {
+			return nil, err
+		}
+		if err = r.fw.PermanentSetIPSetSettings(name, settings); err != nil {
+			return nil, err
+		}
+		names, _ := r.fw.PermanentGetIPSets()
+		return pathOf(IPSET_PATH, names, name, "INVALID_IPSET")
+	},
+	object.CONFIG_GETSERVICENAMES: func(r *request) ([]interface{}, error) {
+		return one(r.fw.PermanentGetServices())
+	},
+	object.CONFIG_GETSERVICEBYNAME: func(r *request) ([]interface{}, error) {
+		names, _ := r.fw.PermanentGetServices()
+		return pathOf(SERVICE_PATH, names, r.str(0), "INVALID_SERVICE")
+	},
+	object.CONFIG_ADDSERVICE: func(r *request) ([]interface{}, error) {
+		settings, err := dbus.ServiceSettingsOfWire(r.arg(1))
+		return addService(r, settings, err)
+	},
+	object.CONFIG_ADDSERVICE2: func(r *request) ([]interface{}, error) {
+		settings, err := dbus.ServiceSettingsOfDict(r.arg(1))
+		return addService(r, settings, err)
+	},
+	object.CONFIG_GETPOLICYNAMES: func(r *request) ([]interface{}, error) {
+		return one(r.fw.PermanentGetPolicies())
+	},
+	object.CONFIG_GETPOLICYBYNAME: func(r *request) ([]interface{}, error) {
+		names, _ := r.fw.PermanentGetPolicies()
+		return pathOf(POLICY_PATH, names, r.str(0), "INVALID_POLICY")
+	},
+	object.CONFIG_ADDPOLICY: func(r *request) ([]interface{}, error) {
+		name := r.str(0)
+		settings, err := dbus.PolicySettingsOfDict(r.arg(1))
+		if err != nil {
+			r.fail("%v", err)
+			return nil, nil
+		}
+		if err = r.fw.PermanentAddPolicy(name, settings); err != nil {
+			return nil, err
+		}
+		names, _ := r.fw.PermanentGetPolicies()
+		return pathOf(POLICY_PATH, names, name, "INVALID_POLICY")
+	},
+	object.CONFIG_DIRECT_GETSETTINGS: func(r *request) ([]interface{}, error) {
+		settings, err := r.fw.PermanentGetDirectSettings()
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{dbus.DirectSettingsWire(settings)}, nil
+	},
+	object.CONFIG_DIRECT_UPDATE: func(r *request) ([]interface{}, error) {
+		settings, err := dbus.DirectSettingsOfWire(r.arg(0))
+		if err != nil {
+			r.fail("%v", err)
+			return nil, nil
+		}
+		return none(r.fw.PermanentSetDirectSettings(settings))
+	},
+
+	/************************************************** config zone ***********************************************************/
+	object.CONFIG_ZONE_GETSETTINGS: onZone(func(r *request, zone string) ([]interface{}, error) {
+		settings, err := r.fw.PermanentGetZoneSettings(zone)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{dbus.ZoneSettingsWire(settings)}, nil
+	}),
+	object.CONFIG_UPDATE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		settings, err := dbus.ZoneSettingsOfWire(r.arg(0))
+		if err != nil {
+			r.fail("%v", err)
+			return nil, nil
+		}
+		return none(r.fw.PermanentSetZoneSettings(zone, settings))
+	}),
+	object.CONFIG_ZONE + ".loadDefaults": onZone(func(r *request, zone string) ([]interface{}, error) {
+		return none(r.fw.PermanentLoadDefaults(dbus.KIND_ZONE, zone))
+	}),
+	object.CONFIG_ZONE_REMOVE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return none(r.fw.PermanentRemoveZone(zone))
+	}),
+	object.CONFIG_ZONE_GETTARGET: onZone(func(r *request, zone string) ([]interface{}, error) {
+		settings, err := r.fw.PermanentGetZoneSettings(zone)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{settings.Targe}, nil
+	}),
+	object.CONFIG_ZONE_SETTARGET: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return none(r.fw.PermanentSetZoneTarget(zone, r.str(0)))
+	}),
+	object.CONFIG_ZONE_GETPORTS: onZone(func(r *request, zone string) ([]interface{}, error) {
+		ports, err := r.fw.PermanentGetPort(zone)
+		list := []portTuple{}
+		for _, port := range ports {
+			list = append(list, portTuple{port.Port, port.Protocol})
+		}
+		return one(list, err)
+	}),
+	object.CONFIG_ZONE_ADDPORT: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return none(r.fw.Zone(zone).AddPort(dbus.SCOPE_PERMANENT, r.str(0)+"/"+r.str(1), 0))
+	}),
+	object.CONFIG_ZONE_REMOVEPORT: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return none(r.fw.Zone(zone).RemovePort(dbus.SCOPE_PERMANENT, r.str(0)+"/"+r.str(1)))
+	}),
+	object.CONFIG_ZONE_ADDSERVICE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return none(r.fw.Zone(zone).AddService(dbus.SCOPE_PERMANENT, r.str(0), 0))
+	}),
+	object.CONFIG_ZONE_REMOVESERVICE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return none(r.fw.Zone(zone).RemoveService(dbus.SCOPE_PERMANENT, r.str(0)))
+	}),
+	object.CONFIG_ZONE_QUERYSERVICE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return one(r.fw.Zone(zone).QueryService(dbus.SCOPE_PERMANENT, r.str(0)))
+	}),
+	object.CONFIG_ZONE_ADDSOURCE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return none(r.fw.Zone(zone).AddSource(dbus.SCOPE_PERMANENT, r.str(0)))
+	}),
+	object.CONFIG_ZONE_REMOVESOURCE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return none(r.fw.Zone(zone).RemoveSource(dbus.SCOPE_PERMANENT, r.str(0)))
+	}),
+	object.CONFIG_ZONE_QUERYSOURCE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return one(r.fw.Zone(zone).QuerySource(dbus.SCOPE_PERMANENT, r.str(0)))
+	}),
+	object.CONFIG_ZONE_ADDINTERFACE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return none(r.fw.Zone(zone).BindInterface(dbus.SCOPE_PERMANENT, r.str(0)))
+	}),
+	object.CONFIG_ZONE_REMOVEINTERFACE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return none(r.fw.Zone(zone).RemoveInterface(dbus.SCOPE_PERMANENT, r.str(0)))
+	}),
+	object.CONFIG_ZONE + ".queryInterface": onZone(func(r *request, zone string) ([]interface{}, error) {
+		return one(r.fw.Zone(zone).QueryInterface(dbus.SCOPE_PERMANENT, r.str(0)))
+	}),
+	object.CONFIG_ZONE_ADDMASQUERADE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return none(r.fw.Zone(zone).EnableMasquerade(dbus.SCOPE_PERMANENT, 0))
+	}),
+	object.CONFIG_ZONE_REMOVEMASQUERADE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return none(r.fw.Zone(zone).DisableMasquerade(dbus.SCOPE_PERMANENT))
+	}),
+	object.CONFIG_ZONE_QUERYMASQUERADE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		return one(r.fw.Zone(zone).QueryMasquerade(dbus.SCOPE_PERMANENT))
+	}),
+	object.CONFIG_ZONE_ADDFORWARDPORT: onZone(func(r *request, zone string) ([]interface{}, error) {
+		portProtocol, toHostPort := r.forward(0)
+		return none(r.fw.Zone(zone).AddForwardPort(dbus.SCOPE_PERMANENT, portProtocol, toHostPort, 0))
+	}),
+	object.CONFIG_ZONE_REMOVEFORWARDPORT: onZone(func(r *request, zone string) ([]interface{}, error) {
+		portProtocol, toHostPort := r.forward(0)
+		return none(r.fw.Zone(zone).RemoveForwardPort(dbus.SCOPE_PERMANENT, portProtocol, toHostPort))
+	}),
+	object.CONFIG_ZONE_QUERYFORWARDPORT: onZone(func(r *request, zone string) ([]interface{}, error) {
+		portProtocol, toHostPort := r.forward(0)
+		return one(r.fw.Zone(zone).QueryForwardPort(dbus.SCOPE_PERMANENT, portProtocol, toHostPort))
+	}),
+	object.CONFIG_ZONE_ADDRICHRULE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		rule, err := r.rule(0)
+		if err != nil {
+			return nil, err
+		}
+		return none(r.fw.Zone(zone).AddRichRule(dbus.SCOPE_PERMANENT, rule, 0))
+	}),
+	object.CONFIG_ZONE_REOMVERICHRULE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		rule, err := r.rule(0)
+		if err != nil {
+			return nil, err
+		}
+		return none(r.fw.Zone(zone).RemoveRichRule(dbus.SCOPE_PERMANENT, rule))
+	}),
+	object.CONFIG_ZONE_QUERYRICHRULE: onZone(func(r *request, zone string) ([]interface{}, error) {
+		rule, err := r.rule(0)
+		if err != nil {
+			return nil, err
+		}
+		return one(r.fw.Zone(zone).QueryRichRule(dbus.SCOPE_PERMANENT, rule))
+	}),
+
+	/************************************************** config ipset, service and policy ***********************************************************/
+	object.CONFIG_IPSET_GETSETTINGS: onIPSet(func(r *request, ipset string) ([]interface{}, error) {
+		settings, err := r.fw.PermanentGetIPSetSettings(ipset)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{dbus.IPSetSettingsWire(settings)}, nil
+	}),
+	object.CONFIG_IPSET_UPDATE: onIPSet(func(r *request, ipset string) ([]interface{}, error) {
+		settings, err := dbus.IPSetSettingsOfWire(r.arg(0))
+		if err != nil {
+			r.fail("%v", err)
+			return nil, nil
+		}
+		return none(r.fw.PermanentSetIPSetSettings(ipset, settings))
+	}),
+	object.CONFIG_IPSET + ".loadDefaults": onIPSet(func(r *request, ipset string) ([]interface{}, error) {
+		return none(r.fw.PermanentLoadDefaults(dbus.KIND_IPSET, ipset))
+	}),
+	object.CONFIG_IPSET_REMOVE: onIPSet(func(r *request, ipset string) ([]interface{}, error) {
+		return none(r.fw.PermanentRemoveIPSet(ipset))
+	}),
+	object.CONFIG_IPSET_GETENTRIES: onIPSet(func(r *request, ipset string) ([]interface{}, error) {
+		return one(r.fw.PermanentGetIPSetEntries(ipset))
+	}),
+	object.CONFIG_IPSET_ADDENTRY: onIPSet(func(r *request, ipset string) ([]interface{}, error) {
+		return none(r.fw.PermanentAddIPSetEntry(ipset, r.str(0)))
+	}),
+	object.CONFIG_IPSET_REMOVEENTRY: onIPSet(func(r *request, ipset string) ([]interface{}, error) {
+		return none(r.fw.PermanentRemoveIPSetEntry(ipset, r.str(0)))
+	}),
+	object.CONFIG_IPSET_QUERYENTRY: onIPSet(func(r *request, ipset string) ([]interface{}, error) {
+		return one(r.fw.PermanentQueryIPSetEntry(ipset, r.str(0)))
+	}),
+	object.CONFIG_SERVICE_GETSETTINGS: onService(func(r *request, service string) ([]interface{}, error) {
+		settings, err := r.fw.PermanentGetServiceSettings(service)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{dbus.ServiceSettingsWire(settings)}, nil
+	}),
+	object.CONFIG_SERVICE_GETSETTINGS2: onService(func(r *request, service string) ([]interface{}, error) {
+		settings, err := r.fw.PermanentGetServiceSettings(service)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{dbus.ServiceSettingsDict(settings)}, nil
+	}),
+	object.CONFIG_SERVICE_UPDATE: onService(func(r *request, service string) ([]interface{}, error) {
+		settings, err := dbus.ServiceSettingsOfWire(r.arg(0))
+		if err != nil {
+			r.fail("%v", err)
+			return nil, nil
+		}
+		return none(r.fw.PermanentSetServiceSettings(service, settings))
+	}),
+	object.CONFIG_SERVICE_UPDATE2: onService(func(r *request, service string) ([]interface{}, error) {
+		settings, err := dbus.ServiceSettingsOfDict(r.arg(0))
+		if err != nil {
+			r.fail("%v", err)
+			return nil, nil
+		}
+		return none(r.fw.PermanentSetServiceSettings(service, settings))
+	}),
+	object.CONFIG_SERVICE + ".loadDefaults": onService(func(r *request, service string) ([]interface{}, error) {
+		return none(r.fw.PermanentLoadDefaults(dbus.KIND_SERVICE, service))
+	}),
+	object.CONFIG_SERVICE_REMOVE: onService(func(r *request, service string) ([]interface{}, error) {
+		return none(r.fw.PermanentRemoveServiceConfig(service))
+	}),
+	object.CONFIG_POLICY_GETSETTINGS: onPolicy(func(r *request, policy string) ([]interface{}, error) {
+		settings, err := r.fw.PermanentGetPolicySettings(policy)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{dbus.PolicySettingsDict(settings)}, nil
+	}),
+	object.CONFIG_POLICY_UPDATE: onPolicy(func(r *request, policy string) ([]interface{}, error) {
+		settings, err := dbus.PolicySettingsOfDict(r.arg(0))
+		if err != nil {
+			r.fail("%v", err)
+			return nil, nil
+		}
+		return none(r.fw.PermanentSetPolicySettings(policy, settings))
+	}),
+	object.CONFIG_POLICY + ".loadDefaults": onPolicy(func(r *request, policy string) ([]interface{}, error) {
+		return none(r.fw.PermanentLoadDefaults(dbus.KIND_POLICY, policy))
+	}),
+	object.CONFIG_POLICY_REMOVE: onPolicy(func(r *request, policy string) ([]interface{}, error) {
+		return none(r.fw.PermanentRemovePolicy(policy))
+	}),
+}
+
+// addService adds the service of a config addService or addService2 call and returns its path.
+func addService(r *request, settings *dbus.ServiceSettings, err error) ([]interface{}, error) {
+	name := r.str(0)
+	if err != nil {
+		r.fail("%v", err)
+		return nil, nil
+	}
+	if err = r.fw.PermanentAddServiceConfig(name, settings); err != nil {
+		return nil, err
+	}
+	names, _ := r.fw.PermanentGetServices()
+	return pathOf(SERVICE_PATH, names, name, "INVALID_SERVICE")
+}
+
+// onZone, onIPSet, onService and onPolicy resolve the config object of the path before calling handle.
+func onZone(handle func(r *request, zone string) ([]interface{}, error)) handler {
+	return func(r *request) ([]interface{}, error) {
+		zone, err := r.zone()
+		if err != nil {
+			return nil, err
+		}
+		return handle(r, zone)
+	}
+}
+
+func onIPSet(handle func(r *request, ipset string) ([]interface{}, error)) handler {
+	return func(r *request) ([]interface{}, error) {
+		ipset, err := r.ipset()
+		if err != nil {
+			return nil, err
+		}
+		return handle(r, ipset)
+	}
+}
+
+func onService(handle func(r *request, service string) ([]interface{}, error)) handler {
+	return func(r *request) ([]interface{}, error) {
+		service, err := r.service()
+		if err != nil {
+			return nil, err
+		}
+		return handle(r, service)
+	}
+}
+
+func onPolicy(handle func(r *request, policy string) ([]interface{}, error)) handler {
+	return func(r *request) ([]interface{}, error) {
+		policy, err := r.policy()
+		if err != nil {
+			return nil, err
+		}
+		return handle(r, policy)
+	}
+}
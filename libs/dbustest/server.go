@@ -0,0 +1,365 @@
+package dbustest
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/object"
+	godbus "github.com/godbus/dbus/v5"
+)
+
+const (
+	BUS_NAME      = "org.freedesktop.DBus"
+	BUS_INTERFACE = "org.freedesktop.DBus"
+	// FIREWALLD_NAME is the unique name of the fake firewalld on the bus, the sender of its signals.
+	FIREWALLD_NAME = ":1.0"
+
+	ERROR_UNKNOWN_METHOD = "org.freedesktop.DBus.Error.UnknownMethod"
+	ERROR_UNKNOWN_OBJECT = "org.freedesktop.DBus.Error.UnknownObject"
+	ERROR_INVALID_ARGS   = "org.freedesktop.DBus.Error.InvalidArgs"
+)
+
+/*
+ * Server is a fake firewalld for tests, it serves the D-Bus API of org.fedoraproject.FirewallD1 on 127.0.0.1, e.g.
+ *
+ *   server, err := dbustest.NewServer()
+ *   defer server.Close()
+ *   client, err := dbus.NewDbusClientService(server.Addr())
+ *   client.AddPort("8080/tcp", "public", 0)
+ *   ports, _ := server.Firewall.GetPort("public")  // [8080/tcp]
+ *
+ * It plays the bus and firewalld in one: the connection authenticates with ANONYMOUS, Hello and AddMatch are answered,
+ * method calls of the root, zone, ipset, policy, direct and config objects go to Firewall and its changes come back as signals.
+ * Signals of the clients are passed on to the others, like a bus does.
+ * Zones have the object paths of the ids given at their creation, like firewalld, see zoneIds,
+ * the paths of the other config objects are indexes into their sorted names.
+ *   Firewall  the state of the fake firewalld, change it directly to prepare a test.
+ */
+type Server struct {
+	Firewall *dbus.MemoryFirewall
+
+	listener net.Listener
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	lock   sync.Mutex
+	conns  map[*serverConn]bool
+	names  int
+	closed bool
+
+	zoneLock sync.Mutex
+	zones    map[string]int
+	nextZone int
+}
+
+// serverConn is one client connection, matches counts its AddMatch calls, it gets signals while it is positive.
+type serverConn struct {
+	server *Server
+	conn   net.Conn
+	name   string
+
+	writeLock sync.Mutex
+	lock      sync.Mutex
+	matches   int
+}
+
+// NewServer starts a fake firewalld with the predefined zones and services of NewMemoryFirewall.
+func NewServer() (*Server, error) {
+	return NewServerOf(dbus.NewMemoryFirewall())
+}
+
+/*
+ * @title         NewServerOf
+ * @description   start a fake firewalld serving fw on a free port of 127.0.0.1.
+ * @auth          author           2021-10-17
+ * @param         fw               *dbus.MemoryFirewall "the state of the fake firewalld."
+ * @return        server           *Server        "close it at the end of the test."
+ * @return        error            error          "no port could be listened on."
+ */
+func NewServerOf(fw *dbus.MemoryFirewall) (server *Server, err error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := fw.Events(ctx)
+	if err != nil {
+		cancel()
+		listener.Close()
+		return nil, err
+	}
+
+	server = &Server{
+		Firewall: fw,
+		listener: listener,
+		cancel:   cancel,
+		conns:    map[*serverConn]bool{},
+		zones:    map[string]int{},
+	}
+	server.zoneIds()
+	server.wg.Add(2)
+	go server.accept()
+	go server.signal(events)
+	return server, nil
+}
+
+// Addr returns the host:port of the server, pass it to dbus.NewDbusClientService.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Client connects a new client to the server.
+func (s *Server) Client() (*dbus.DbusClientSerivce, error) {
+	return dbus.NewDbusClientService(s.Addr())
+}
+
+// Close stops the server and closes the connections of the clients.
+func (s *Server) Close() error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil
+	}
+	s.closed = true
+	err := s.listener.Close()
+	for conn := range s.conns {
+		conn.conn.Close()
+	}
+	s.lock.Unlock()
+
+	s.cancel()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.lock.Lock()
+		if s.closed {
+			s.lock.Unlock()
+			conn.Close()
+			return
+		}
+		s.names++
+		c := &serverConn{server: s, conn: conn, name: fmt.Sprintf(":1.%d", s.names)}
+		s.conns[c] = true
+		s.wg.Add(1)
+		s.lock.Unlock()
+
+		go c.serve()
+	}
+}
+
+// signal sends the events of Firewall to the connections with a match rule.
+func (s *Server) signal(events <-chan dbus.Event) {
+	defer s.wg.Done()
+	for event := range events {
+		if event.Name == dbus.EVENT_OVERFLOW {
+			// the clients missed changes, Reloaded makes them drop what they keep.
+			event = dbus.Event{Name: dbus.EVENT_RELOADED, Interface: object.INTERFACE}
+		}
+		signal := event.Signal()
+		index := strings.LastIndex(signal.Name, ".")
+		msg := &godbus.Message{
+			Type: godbus.TypeSignal,
+			Headers: map[godbus.HeaderField]godbus.Variant{
+				godbus.FieldPath:      godbus.MakeVariant(signal.Path),
+				godbus.FieldInterface: godbus.MakeVariant(signal.Name[:index]),
+				godbus.FieldMember:    godbus.MakeVariant(signal.Name[index+1:]),
+				godbus.FieldSender:    godbus.MakeVariant(FIREWALLD_NAME),
+			},
+			Body: signal.Body,
+		}
+		if len(signal.Body) > 0 {
+			msg.Headers[godbus.FieldSignature] = godbus.MakeVariant(godbus.SignatureOf(signal.Body...))
+		}
+		s.broadcast(msg, nil)
+	}
+}
+
+// broadcast sends the signal msg to the connections with a match rule but from.
+func (s *Server) broadcast(msg *godbus.Message, from *serverConn) {
+	s.lock.Lock()
+	var conns []*serverConn
+	for conn := range s.conns {
+		if conn != from {
+			conns = append(conns, conn)
+		}
+	}
+	s.lock.Unlock()
+	for _, conn := range conns {
+		conn.lock.Lock()
+		matches := conn.matches
+		conn.lock.Unlock()
+		if matches > 0 {
+			conn.send(msg)
+		}
+	}
+}
+
+func (c *serverConn) serve() {
+	defer c.server.wg.Done()
+	defer func() {
+		c.conn.Close()
+		c.server.lock.Lock()
+		delete(c.server.conns, c)
+		c.server.lock.Unlock()
+	}()
+
+	reader := bufio.NewReader(c.conn)
+	if err := c.auth(reader); err != nil {
+		return
+	}
+	for {
+		msg, err := godbus.DecodeMessage(reader)
+		if err != nil {
+			return
+		}
+		if msg.Type == godbus.TypeSignal {
+			msg.Headers[godbus.FieldSender] = godbus.MakeVariant(c.name)
+			c.server.broadcast(msg, c)
+			continue
+		}
+		if msg.Type != godbus.TypeMethodCall {
+			continue
+		}
+		body, err := c.call(msg)
+		if msg.Flags&godbus.FlagNoReplyExpected != 0 {
+			continue
+		}
+		if err != nil {
+			c.send(c.errorOf(msg, err))
+			continue
+		}
+		c.send(c.replyOf(msg, body))
+	}
+}
+
+// auth runs the server side of the SASL handshake, every AUTH mechanism is accepted.
+func (c *serverConn) auth(reader *bufio.Reader) error {
+	if _, err := reader.ReadByte(); err != nil {
+		return err
+	}
+	guid := make([]byte, 16)
+	rand.Read(guid)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		command := strings.Fields(line)
+		if len(command) == 0 {
+			continue
+		}
+		switch command[0] {
+		case "AUTH":
+			if len(command) == 1 {
+				_, err = c.conn.Write([]byte("REJECTED ANONYMOUS EXTERNAL\r\n"))
+			} else {
+				_, err = c.conn.Write([]byte("OK " + hex.EncodeToString(guid) + "\r\n"))
+			}
+		case "BEGIN":
+			return nil
+		case "CANCEL", "ERROR":
+			_, err = c.conn.Write([]byte("REJECTED ANONYMOUS EXTERNAL\r\n"))
+		default:
+			_, err = c.conn.Write([]byte("ERROR\r\n"))
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// call answers the methods of the bus itself and hands the others to the firewalld objects.
+func (c *serverConn) call(msg *godbus.Message) (body []interface{}, err error) {
+	destination, _ := msg.Headers[godbus.FieldDestination].Value().(string)
+	iface, _ := msg.Headers[godbus.FieldInterface].Value().(string)
+	member, _ := msg.Headers[godbus.FieldMember].Value().(string)
+	path, _ := msg.Headers[godbus.FieldPath].Value().(godbus.ObjectPath)
+
+	if destination != BUS_NAME {
+		return c.server.dispatch(path, iface+"."+member, msg.Body)
+	}
+	switch iface + "." + member {
+	case BUS_INTERFACE + ".Hello":
+		return []interface{}{c.name}, nil
+	case BUS_INTERFACE + ".AddMatch":
+		c.lock.Lock()
+		c.matches++
+		c.lock.Unlock()
+		return nil, nil
+	case BUS_INTERFACE + ".RemoveMatch":
+		c.lock.Lock()
+		if c.matches > 0 {
+			c.matches--
+		}
+		c.lock.Unlock()
+		return nil, nil
+	case BUS_INTERFACE + ".RequestName":
+		return []interface{}{uint32(godbus.RequestNameReplyPrimaryOwner)}, nil
+	case BUS_INTERFACE + ".ReleaseName":
+		return []interface{}{uint32(godbus.ReleaseNameReplyReleased)}, nil
+	case BUS_INTERFACE + ".GetNameOwner":
+		return []interface{}{FIREWALLD_NAME}, nil
+	}
+	return nil, godbus.Error{Name: ERROR_UNKNOWN_METHOD, Body: []interface{}{"unknown method " + member}}
+}
+
+func (c *serverConn) replyOf(call *godbus.Message, body []interface{}) *godbus.Message {
+	msg := &godbus.Message{
+		Type: godbus.TypeMethodReply,
+		Headers: map[godbus.HeaderField]godbus.Variant{
+			godbus.FieldReplySerial: godbus.MakeVariant(call.Serial()),
+			godbus.FieldDestination: godbus.MakeVariant(c.name),
+			godbus.FieldSender:      godbus.MakeVariant(BUS_NAME),
+		},
+		Body: body,
+	}
+	if len(body) > 0 {
+		msg.Headers[godbus.FieldSignature] = godbus.MakeVariant(godbus.SignatureOf(body...))
+	}
+	return msg
+}
+
+// errorOf converts err into an error reply, errors of Firewall are already dbus.Error of firewalld.
+func (c *serverConn) errorOf(call *godbus.Message, err error) *godbus.Message {
+	dbusErr, ok := err.(godbus.Error)
+	if !ok {
+		dbusErr = godbus.Error{Name: ERROR_INVALID_ARGS, Body: []interface{}{err.Error()}}
+	}
+	msg := &godbus.Message{
+		Type: godbus.TypeError,
+		Headers: map[godbus.HeaderField]godbus.Variant{
+			godbus.FieldReplySerial: godbus.MakeVariant(call.Serial()),
+			godbus.FieldDestination: godbus.MakeVariant(c.name),
+			godbus.FieldErrorName:   godbus.MakeVariant(dbusErr.Name),
+		},
+		Body: dbusErr.Body,
+	}
+	if len(dbusErr.Body) > 0 {
+		msg.Headers[godbus.FieldSignature] = godbus.MakeVariant(godbus.SignatureOf(dbusErr.Body...))
+	}
+	return msg
+}
+
+func (c *serverConn) send(msg *godbus.Message) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	if err := msg.EncodeTo(c.conn, binary.LittleEndian); err != nil {
+		c.conn.Close()
+	}
+}
@@ -0,0 +1,419 @@
+package dbustest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/object"
+	godbus "github.com/godbus/dbus/v5"
+)
+
+// newClient starts a server and connects a client to it, both are closed at the end of the test.
+func newClient(t *testing.T) (*Server, *dbus.DbusClientSerivce) {
+	t.Helper()
+	server, err := NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func hasPort(ports []*dbus.Port, port, protocol string) bool {
+	for _, value := range ports {
+		if value.Port == port && value.Protocol == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAddPort(t *testing.T) {
+	server, client := newClient(t)
+
+	if _, err := client.AddPort("8080/tcp", "public", 0); err != nil {
+		t.Fatalf("AddPort: %v", err)
+	}
+	runtime, _ := server.Firewall.GetPort("public")
+	permanent, _ := server.Firewall.PermanentGetPort("public")
+	if !hasPort(runtime, "8080", "tcp") || hasPort(permanent, "8080", "tcp") {
+		t.Errorf("AddPort: runtime %v, permanent %v, want 8080/tcp in runtime only", runtime, permanent)
+	}
+	if _, err := client.AddPort("8080/tcp", "public", 0); err == nil || !strings.Contains(err.Error(), "ALREADY_ENABLED") {
+		t.Errorf("AddPort twice: got %v, want ALREADY_ENABLED", err)
+	}
+
+	if err := client.PermanentAddPort("8081/udp", ""); err != nil {
+		t.Fatalf("PermanentAddPort: %v", err)
+	}
+	runtime, _ = server.Firewall.GetPort("public")
+	permanent, _ = server.Firewall.PermanentGetPort("public")
+	if hasPort(runtime, "8081", "udp") || !hasPort(permanent, "8081", "udp") {
+		t.Errorf("PermanentAddPort: runtime %v, permanent %v, want 8081/udp in permanent only", runtime, permanent)
+	}
+	ports, err := client.PermanentGetPort("public")
+	if err != nil || !hasPort(ports, "8081", "udp") {
+		t.Errorf("PermanentGetPort: got %v, %v, want 8081/udp", ports, err)
+	}
+}
+
+func TestEventsOfOtherPeers(t *testing.T) {
+	server, client := newClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.Events(ctx)
+	if err != nil {
+		t.Fatalf("Events: %v", err)
+	}
+
+	peer, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+	err = peer.Conn.Emit(godbus.ObjectPath(object.PATH), object.ZONE+"."+dbus.EVENT_PORTADDED, "public", "9999", "tcp", int32(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the server has passed the signal on once it answers the next call of the peer.
+	if _, err = peer.GetZones(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = server.Firewall.AddPort("8080/tcp", "public", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-events:
+		if event.Name != dbus.EVENT_PORTADDED || event.Item != "8080/tcp" {
+			t.Errorf("Events: got %+v, want PortAdded 8080/tcp of firewalld", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Events: no event of firewalld")
+	}
+}
+
+func TestRichRuleRoundTrip(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{
+			rule: `rule family="ipv4" source address="10.0.0.0/8" service name="ssh" accept`,
+			want: `rule family="ipv4" source address="10.0.0.0/8" service name="ssh" accept`,
+		},
+		{
+			rule: `rule family="ipv4" source NOT address="10.1.0.0/16" port port="5432" protocol="tcp" drop`,
+			want: `rule family="ipv4" source NOT address="10.1.0.0/16" port port="5432" protocol="tcp" drop`,
+		},
+		{
+			rule: `RULE family=ipv4 destination NOT address=192.0.2.1 service name=http REJECT`,
+			want: `rule family="ipv4" destination NOT address="192.0.2.1" service name="http" reject`,
+		},
+	}
+	_, client := newClient(t)
+	for _, test := range tests {
+		rule, err := dbus.ParseRule(test.rule)
+		if err != nil {
+			t.Errorf("ParseRule(%s): %v", test.rule, err)
+			continue
+		}
+		if err = client.AddRichRule("public", rule, 0); err != nil {
+			t.Errorf("AddRichRule(%s): %v", test.rule, err)
+			continue
+		}
+		if !client.QueryRichRule("public", rule) {
+			t.Errorf("QueryRichRule(%s): false after AddRichRule", test.rule)
+		}
+	}
+
+	rules, err := client.GetRichRules("public")
+	if err != nil {
+		t.Fatalf("GetRichRules: %v", err)
+	}
+	if len(rules) != len(tests) {
+		t.Fatalf("GetRichRules: got %d rules, want %d", len(rules), len(tests))
+	}
+	for index, test := range tests {
+		if got := rules[index].ToString(); got != test.want {
+			t.Errorf("rule %d:\n got %s\nwant %s", index, got, test.want)
+		}
+	}
+}
+
+func TestDrift(t *testing.T) {
+	_, client := newClient(t)
+
+	if _, err := client.AddPort("8080/tcp", "public", 0); err != nil {
+		t.Fatalf("AddPort: %v", err)
+	}
+	if err := client.PermanentAddService("public", "http"); err != nil {
+		t.Fatalf("PermanentAddService: %v", err)
+	}
+	// the parser does not know ipset destinations, the rule is kept as written.
+	ipsetRule := `rule family="ipv4" destination ipset="blocklist" drop`
+	if err := client.AddRichRule("public", dbus.StringToRule(ipsetRule), 0); err != nil {
+		t.Fatalf("AddRichRule: %v", err)
+	}
+	report, err := client.Drift("public")
+	if err != nil {
+		t.Fatalf("Drift: %v", err)
+	}
+	want := []dbus.DriftItem{
+		{Kind: dbus.KIND_PORT, Value: "8080/tcp", RuntimeOnly: true},
+		{Kind: dbus.KIND_SERVICE, Value: "http", RuntimeOnly: false},
+		{Kind: dbus.KIND_RICHRULE, Value: ipsetRule, RuntimeOnly: true},
+	}
+	for _, item := range want {
+		found := false
+		for _, value := range report.Items {
+			found = found || value == item
+		}
+		if !found {
+			t.Errorf("Drift: %+v missing in %+v", item, report.Items)
+		}
+	}
+	if len(report.Items) != len(want) {
+		t.Errorf("Drift: got %d items %+v, want %d", len(report.Items), report.Items, len(want))
+	}
+
+	if err = client.PromoteDrift(report); err != nil {
+		t.Fatalf("PromoteDrift: %v", err)
+	}
+	if report, err = client.Drift("public"); err != nil || !report.IsEmpty() {
+		t.Errorf("Drift after PromoteDrift: got %v, %v, want no items", report, err)
+	}
+}
+
+func TestReplaceRichRules(t *testing.T) {
+	_, client := newClient(t)
+
+	ipsetRule := `rule family="ipv4" destination ipset="blocklist" drop`
+	sshRule := `rule family="ipv4" source address="10.0.0.0/8" service name="ssh" accept`
+	if err := client.PermanentAddRichRule("public", dbus.StringToRule(ipsetRule)); err != nil {
+		t.Fatalf("PermanentAddRichRule: %v", err)
+	}
+
+	// a new rule the parser does not know refuses the whole replace.
+	_, err := client.PermanentReplaceRichRules("public", []string{sshRule, `rule family="ipv4" destination ipset="allowlist" accept`})
+	if err == nil || !strings.HasPrefix(err.Error(), "INVALID_RULE") {
+		t.Errorf("PermanentReplaceRichRules: got %v, want INVALID_RULE", err)
+	}
+	if client.PermanentQueryRichRule("public", dbus.StringToRule(sshRule)) {
+		t.Error("PermanentReplaceRichRules: rules were replaced although it failed")
+	}
+
+	// a rule the zone has is kept as written.
+	conflicts, err := client.PermanentReplaceRichRules("public", []string{ipsetRule, sshRule, sshRule})
+	if err != nil {
+		t.Fatalf("PermanentReplaceRichRules: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Reason != "ALREADY_ENABLED" {
+		t.Errorf("PermanentReplaceRichRules: got conflicts %v, want the duplicate ssh rule", conflicts)
+	}
+	for _, rule := range []string{ipsetRule, sshRule} {
+		if !client.PermanentQueryRichRule("public", dbus.StringToRule(rule)) {
+			t.Errorf("PermanentReplaceRichRules: %s is missing", rule)
+		}
+	}
+}
+
+func TestPlanApplyNewZone(t *testing.T) {
+	server, client := newClient(t)
+
+	desired, err := dbus.ParseDesiredState([]byte(`
+zones:
+  app:
+    ports: ["8080/tcp"]
+    services: [http]
+    richrules: ['rule family="ipv4" source address="10.0.0.0/8" service name="ssh" accept']
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := client.Plan(desired)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if len(plan.Operations) != 4 || plan.Operations[0].Kind != dbus.KIND_ZONE {
+		t.Fatalf("Plan: got\n%s want the zone and 3 items", plan)
+	}
+	if err = client.Apply(plan); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	ports, _ := server.Firewall.PermanentGetPort("app")
+	if !hasPort(ports, "8080", "tcp") {
+		t.Errorf("Apply: permanent ports of app %v, want 8080/tcp", ports)
+	}
+	if !server.Firewall.PermanentQueryService("app", "http") {
+		t.Error("Apply: http is not a permanent service of app")
+	}
+	if plan, err = client.Plan(desired); err != nil || !plan.IsEmpty() {
+		t.Errorf("Plan after Apply: got %v, %v, want no operations", plan, err)
+	}
+}
+
+func TestZonePaths(t *testing.T) {
+	_, client := newClient(t)
+	config := client.Conn.Object(object.INTERFACE, object.CONFIG_PATH)
+	pathOf := func(zone string) godbus.ObjectPath {
+		t.Helper()
+		var path godbus.ObjectPath
+		if err := config.Call(object.CONFIG_GETZONEBYNAME, 0, zone).Store(&path); err != nil {
+			t.Fatalf("getZoneByName(%s): %v", zone, err)
+		}
+		return path
+	}
+
+	public := pathOf("public")
+	// a new zone sorting before public gets the next id, public keeps its path.
+	if err := client.AddZone("aaa"); err != nil {
+		t.Fatalf("AddZone: %v", err)
+	}
+	added := pathOf("aaa")
+	if got := pathOf("public"); got != public {
+		t.Errorf("path of public: got %s after AddZone, want %s", got, public)
+	}
+	if err := client.PermanentRemoveZone("aaa"); err != nil {
+		t.Fatalf("PermanentRemoveZone: %v", err)
+	}
+	if err := client.AddZone("bbb"); err != nil {
+		t.Fatalf("AddZone: %v", err)
+	}
+	if got := pathOf("bbb"); got == added || got == public {
+		t.Errorf("path of bbb: got %s, ids are not reused", got)
+	}
+	if settings, err := client.PermanentGetZoneSettings("public"); err != nil || !strings.Contains(strings.Join(settings.Service, " "), "ssh") {
+		t.Errorf("PermanentGetZoneSettings public: got %v, %v", settings, err)
+	}
+}
+
+func TestTxRollbackRemovedZone(t *testing.T) {
+	server, client := newClient(t)
+
+	setup := []func() error{
+		func() error { return client.AddZone("extra") },
+		func() error { return client.PermanentAddPort("8080/tcp", "extra") },
+		func() error { return client.PermanentAddIPSet("blocklist", "hash:ip") },
+		func() error { return client.PermanentAddIPSetEntry("blocklist", "192.0.2.1") },
+	}
+	for index, change := range setup {
+		if err := change(); err != nil {
+			t.Fatalf("setup %d: %v", index, err)
+		}
+	}
+
+	err := client.NewTx(
+		dbus.Operation{Action: dbus.OP_REMOVE, Kind: dbus.KIND_ZONE, Zone: "extra", Permanent: true},
+		dbus.Operation{Action: dbus.OP_REMOVE, Kind: dbus.KIND_IPSET, IPSet: "blocklist", Permanent: true},
+		dbus.Operation{Action: dbus.OP_ADD, Kind: dbus.KIND_PORT, Zone: "missing", Value: "80/tcp", Permanent: true},
+	).Commit()
+	txErr, ok := err.(*dbus.TxError)
+	if !ok || txErr.Index != 2 || txErr.RolledBack != 2 || len(txErr.RollbackErrors) > 0 {
+		t.Fatalf("Commit: got %v, want operation 3 to fail and 2 operations rolled back", err)
+	}
+
+	ports, err := server.Firewall.PermanentGetPort("extra")
+	if err != nil || !hasPort(ports, "8080", "tcp") {
+		t.Errorf("rollback: ports of extra %v, %v, want 8080/tcp", ports, err)
+	}
+	entries, err := server.Firewall.PermanentGetIPSetEntries("blocklist")
+	if err != nil || len(entries) != 1 || entries[0] != "192.0.2.1" {
+		t.Errorf("rollback: entries of blocklist %v, %v, want 192.0.2.1", entries, err)
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	_, client := newClient(t)
+
+	// a runtime only rule the parser does not understand is replayed as written.
+	if err := client.AddRichRule("public", dbus.StringToRule(`rule family="ipv4" destination ipset="blocklist" drop`), 0); err != nil {
+		t.Fatalf("AddRichRule: %v", err)
+	}
+	before, err := client.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	changes := []func() error{
+		func() error { _, err := client.AddPort("8080/tcp", "public", 0); return err },
+		func() error { return client.PermanentAddPort("8443/tcp", "public") },
+		func() error { return client.PermanentAddService("public", "http") },
+		func() error { return client.PermanentAddIPSet("blocklist", "hash:ip") },
+		func() error { return client.AddZone("extra") },
+	}
+	for index, change := range changes {
+		if err = change(); err != nil {
+			t.Fatalf("change %d: %v", index, err)
+		}
+	}
+	changed, err := client.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if sameSnapshot(t, before, changed) {
+		t.Fatal("Snapshot: the changes are not in the snapshot")
+	}
+
+	if err = client.Restore(before); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	after, err := client.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if !sameSnapshot(t, before, after) {
+		want, _ := before.JSON()
+		got, _ := after.JSON()
+		t.Errorf("Restore:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestRestoreInvalidSnapshot(t *testing.T) {
+	_, client := newClient(t)
+
+	before, err := client.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	data, _ := before.JSON()
+	snapshot, _ := dbus.ParseSnapshot(data)
+	snapshot.Zones["extra"] = &dbus.ZoneSnapshot{Permanent: &dbus.Settings{Short: "extra", Targe: "default"}}
+	snapshot.Zones["public"].Runtime.ForwardPort = append(snapshot.Zones["public"].Runtime.ForwardPort, dbus.ForwardPort{Port: "80"})
+	if err = client.Restore(snapshot); err == nil || !strings.Contains(err.Error(), "INVALID_FORWARD") {
+		t.Fatalf("Restore: got %v, want INVALID_FORWARD", err)
+	}
+
+	after, err := client.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if !sameSnapshot(t, before, after) {
+		t.Error("Restore: the firewall changed before the snapshot was refused")
+	}
+}
+
+// sameSnapshot compares the configuration of two snapshots, the time they were taken is left out.
+func sameSnapshot(t *testing.T, a, b *dbus.Snapshot) bool {
+	t.Helper()
+	x, y := *a, *b
+	x.Taken = y.Taken
+	left, err := json.Marshal(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	right, err := json.Marshal(y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(left) == string(right)
+}
@@ -0,0 +1,75 @@
+package dbustest
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+/*
+ * Spans records the spans of the global tracer provider during a test, e.g.
+ *
+ *   spans := dbustest.RecordSpans(t)
+ *   client.PermanentAddPort("8080/tcp", "public")
+ *   span := spans.Named("firewalld.PermanentAddPort", dbus.ATTR_ZONE.String("public"))
+ *
+ *   Provider  the recording provider, e.g. to start the span of a caller.
+ */
+type Spans struct {
+	*tracetest.SpanRecorder
+	Provider *sdktrace.TracerProvider
+
+	t testing.TB
+}
+
+// RecordSpans makes the global provider record the spans and propagate W3C trace context until the end of the test.
+func RecordSpans(t testing.TB) *Spans {
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	spans := &Spans{SpanRecorder: tracetest.NewSpanRecorder(), t: t}
+	spans.Provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans.SpanRecorder))
+	otel.SetTracerProvider(spans.Provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+		spans.Provider.Shutdown(context.Background())
+	})
+	return spans
+}
+
+// Named returns the last ended span called name that has attributes, the test fails if there is none.
+func (s *Spans) Named(name string, attributes ...attribute.KeyValue) sdktrace.ReadOnlySpan {
+	s.t.Helper()
+	ended := s.Ended()
+	for index := len(ended) - 1; index >= 0; index-- {
+		if ended[index].Name() == name && hasAttributes(ended[index], attributes) {
+			return ended[index]
+		}
+	}
+	s.t.Fatalf("no span %s %v in %d spans", name, attributes, len(ended))
+	return nil
+}
+
+// AttributeOf returns the value of the attribute key of span, empty if it has none.
+func AttributeOf(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, value := range span.Attributes() {
+		if value.Key == key {
+			return value.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func hasAttributes(span sdktrace.ReadOnlySpan, attributes []attribute.KeyValue) bool {
+	for _, want := range attributes {
+		if AttributeOf(span, want.Key) != want.Value.Emit() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,273 @@
+package fleet
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TRACER_NAME is the instrumentation name of the spans of Run, a span per run and a child per host.
+const TRACER_NAME = "github.com/cylonchau/gofirewallder/libs/fleet"
+
+var (
+	DEFAULT_CONCURRENCY = 16
+	DEFAULT_TIMEOUT     = 30 * time.Second
+)
+
+// Operation is run once per host with a client connected to that host.
+type Operation func(ctx context.Context, client *dbus.DbusClientSerivce) (interface{}, error)
+
+// Dialer connects to firewalld of a host, addr is host:port. It gives up when ctx, the host timeout, is done.
+type Dialer func(ctx context.Context, addr string) (*dbus.DbusClientSerivce, error)
+
+/*
+ * Executor runs an operation across many hosts concurrently.
+ *   Concurrency  maximum number of hosts in progress, DEFAULT_CONCURRENCY if zero.
+ *   Timeout      per host timeout, it includes connecting, DEFAULT_TIMEOUT if zero.
+ *   Dial         connects to a host, dbus.NewDbusClientServiceContext if nil.
+ */
+type Executor struct {
+	Concurrency int
+	Timeout     time.Duration
+	Dial        Dialer
+}
+
+// Result is the outcome of the operation on one host.
+type Result struct {
+	Host     string        `json:"host"`
+	Value    interface{}   `json:"value,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Err      error         `json:"-"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Report aggregates the results of all hosts, in inventory order.
+type Report struct {
+	Results  []Result      `json:"results"`
+	Success  int           `json:"success"`
+	Failure  int           `json:"failure"`
+	Duration time.Duration `json:"duration"`
+}
+
+var ErrTimeout = errors.New("host timeout.")
+
+// DialError is the error of a host that could not be connected, the operation did not run.
+type DialError struct {
+	Host string
+	Err  error
+}
+
+func (e *DialError) Error() string { return e.Err.Error() }
+func (e *DialError) Unwrap() error { return e.Err }
+
+// NewExecutor returns an executor with the default concurrency and timeout.
+func NewExecutor() *Executor {
+	return &Executor{
+		Concurrency: DEFAULT_CONCURRENCY,
+		Timeout:     DEFAULT_TIMEOUT,
+		Dial:        dbus.NewDbusClientServiceContext,
+	}
+}
+
+/*
+ * @title         Run
+ * @description   connect to every host of inventory and run op, with bounded concurrency and per host timeouts.
+ *                  the run is a span in the trace of ctx, each host a child span holding the spans of its client.
+ * @auth          author           2021-10-13
+ * @param         ctx              context.Context "cancel to stop scheduling hosts, hosts in progress are aborted."
+ * @param         inventory        []string        "host:port list, see ParseInventory."
+ * @param         op               Operation       "the operation to run on each host."
+ * @return        report           *Report         "per host results and success/failure counts."
+ */
+func (e *Executor) Run(ctx context.Context, inventory []string, op Operation) *Report {
+	concurrency, timeout, dial := e.Concurrency, e.Timeout, e.Dial
+	if concurrency <= 0 {
+		concurrency = DEFAULT_CONCURRENCY
+	}
+	if timeout <= 0 {
+		timeout = DEFAULT_TIMEOUT
+	}
+	if dial == nil {
+		dial = dbus.NewDbusClientServiceContext
+	}
+
+	ctx, span := otel.Tracer(TRACER_NAME).Start(ctx, "fleet.Run", trace.WithAttributes(attribute.Int("fleet.hosts", len(inventory))))
+	defer span.End()
+
+	start := time.Now()
+	report := &Report{Results: make([]Result, len(inventory))}
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for index, host := range inventory {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			report.Results[index] = Result{Host: host, Err: ctx.Err()}
+			continue
+		}
+		wg.Add(1)
+		go func(index int, host string) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			hostCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			hostCtx, hostSpan := otel.Tracer(TRACER_NAME).Start(hostCtx, "fleet.host", trace.WithAttributes(dbus.ATTR_HOST.String(host)))
+			defer hostSpan.End()
+
+			hostStart := time.Now()
+			value, err := runHost(hostCtx, dial, host, op)
+			if err != nil {
+				hostSpan.RecordError(err)
+				hostSpan.SetStatus(codes.Error, err.Error())
+			}
+			report.Results[index] = Result{Host: host, Value: value, Err: err, Duration: time.Since(hostStart)}
+		}(index, host)
+	}
+	wg.Wait()
+
+	for index := range report.Results {
+		result := &report.Results[index]
+		if result.Err != nil {
+			result.Error = result.Err.Error()
+			report.Failure++
+		} else {
+			report.Success++
+		}
+	}
+	report.Duration = time.Since(start)
+	span.SetAttributes(attribute.Int("fleet.failures", report.Failure))
+	if report.Failure > 0 {
+		span.SetStatus(codes.Error, fmt.Sprintf("%d host(s) failed", report.Failure))
+	}
+	return report
+}
+
+// runHost dials host and runs op, the calls of the client run with ctx, see dbus.DbusClientSerivce.WithContext.
+// The connection is also closed when ctx is done so pending calls return.
+func runHost(ctx context.Context, dial Dialer, host string, op Operation) (value interface{}, err error) {
+	type outcome struct {
+		value interface{}
+		err   error
+	}
+	done := make(chan outcome, 1)
+
+	go func() {
+		client, err := dial(ctx, host)
+		if err != nil {
+			done <- outcome{err: &DialError{Host: host, Err: err}}
+			return
+		}
+		stop := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				client.Close()
+			case <-stop:
+			}
+		}()
+		value, err := op(ctx, client.WithContext(ctx))
+		close(stop)
+		client.Close()
+		done <- outcome{value, err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.value, result.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrTimeout
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Failed returns the results of the hosts that failed.
+func (r *Report) Failed() (results []Result) {
+	for _, result := range r.Results {
+		if result.Err != nil || result.Error != "" {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// JSON encodes the report.
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// String renders a summary followed by the error of each failed host.
+func (r *Report) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%d host(s): %d succeeded, %d failed in %s.\n",
+		len(r.Results), r.Success, r.Failure, r.Duration.Round(time.Millisecond))
+	failed := r.Failed()
+	sort.SliceStable(failed, func(i, j int) bool { return failed[i].Host < failed[j].Host })
+	for _, result := range failed {
+		fmt.Fprintf(&builder, "  %s: %s\n", result.Host, result.Error)
+	}
+	return builder.String()
+}
+
+/*
+ * @title         ParseInventory
+ * @description   read one host per line, blank lines and # comments are skipped.
+ *                  a host without port uses dbus.PORT, e.g. 10.0.0.1 -> 10.0.0.1:55557
+ * @auth          author           2021-10-13
+ * @param         r                io.Reader      "the inventory."
+ * @return        inventory        []string       "host:port list."
+ * @return        error            error          ""
+ */
+func ParseInventory(r io.Reader) (inventory []string, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if index := strings.Index(line, "#"); index >= 0 {
+			line = line[:index]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		inventory = append(inventory, HostPort(line))
+	}
+	return inventory, scanner.Err()
+}
+
+// LoadInventory reads the inventory file, see ParseInventory.
+func LoadInventory(path string) (inventory []string, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseInventory(file)
+}
+
+// HostPort appends dbus.PORT to host if it has no port.
+func HostPort(host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(dbus.PORT))
+}
@@ -0,0 +1,68 @@
+package fleet_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/libs/dbustest"
+	"github.com/cylonchau/gofirewallder/libs/fleet"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func newServer(t *testing.T) *dbustest.Server {
+	t.Helper()
+	server, err := dbustest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return server
+}
+
+func TestRunSpans(t *testing.T) {
+	ok, failing := newServer(t), newServer(t)
+	if _, err := failing.Firewall.AddPort("8080/tcp", "dmz", 0); err != nil {
+		t.Fatal(err)
+	}
+	spans := dbustest.RecordSpans(t)
+
+	report := fleet.NewExecutor().Run(context.Background(), []string{ok.Addr(), failing.Addr()},
+		func(ctx context.Context, client *dbus.DbusClientSerivce) (interface{}, error) {
+			_, err := client.AddPort("8080/tcp", "dmz", 0)
+			return nil, err
+		})
+	if report.Success != 1 || report.Failure != 1 {
+		t.Fatalf("Run: %d succeeded and %d failed, want 1 and 1", report.Success, report.Failure)
+	}
+
+	run := spans.Named("fleet.Run")
+	if run.Status().Code != codes.Error {
+		t.Errorf("fleet.Run status: got %v, want error", run.Status())
+	}
+
+	for _, host := range []*dbustest.Server{ok, failing} {
+		hostSpan := spans.Named("fleet.host", dbus.ATTR_HOST.String(host.Addr()))
+		if hostSpan.Parent().SpanID() != run.SpanContext().SpanID() {
+			t.Errorf("fleet.host of %s is not a child of fleet.Run", host.Addr())
+		}
+		call := spans.Named("firewalld.AddPort", dbus.ATTR_HOST.String(host.Addr()))
+		if call.Parent().SpanID() != hostSpan.SpanContext().SpanID() {
+			t.Errorf("firewalld.AddPort of %s is not a child of its fleet.host", host.Addr())
+		}
+		if zone := dbustest.AttributeOf(call, dbus.ATTR_ZONE); zone != "dmz" {
+			t.Errorf("zone attribute of %s: got %q, want dmz", host.Addr(), zone)
+		}
+
+		want := codes.Unset
+		if host == failing {
+			want = codes.Error
+		}
+		if hostSpan.Status().Code != want || call.Status().Code != want {
+			t.Errorf("status of %s: got %v and %v, want %v", host.Addr(), hostSpan.Status(), call.Status(), want)
+		}
+	}
+	if code := dbustest.AttributeOf(spans.Named("firewalld.AddPort", dbus.ATTR_HOST.String(failing.Addr())), dbus.ATTR_ERROR_CODE); code != "ALREADY_ENABLED" {
+		t.Errorf("error code attribute: got %q, want ALREADY_ENABLED", code)
+	}
+}
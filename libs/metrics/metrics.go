@@ -0,0 +1,397 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/libs/fleet"
+)
+
+const (
+	NAMESPACE        = "gofirewallder"
+	DEFAULT_INTERVAL = time.Minute
+	CONTENT_TYPE     = "text/plain; version=0.0.4; charset=utf-8"
+
+	// CODE_UNKNOWN is the code label of the errors without firewalld code, e.g. a timeout.
+	CODE_UNKNOWN = "UNKNOWN"
+)
+
+// DEFAULT_BUCKETS are the upper bounds in seconds of the call latency histogram.
+var DEFAULT_BUCKETS = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+/*
+ * Exporter collects the state of the firewalls of Hosts every Interval and serves it together with the latency and
+ * the errors of the D-Bus calls of its clients in the Prometheus text format, e.g.
+ *
+ *   exporter := metrics.NewExporter(hosts)
+ *   go exporter.Run(ctx)
+ *   http.Handle("/metrics", exporter)
+ *
+ * The calls of other clients are measured too when they Use Intercept or are dialed by Dialer.
+ *   Hosts     host:port of the firewalls, see fleet.ParseInventory.
+ *   Interval  time between collections, DEFAULT_INTERVAL if zero.
+ *   Executor  connects to the hosts, fleet.NewExecutor if nil, its Dial is wrapped by Dialer.
+ */
+type Exporter struct {
+	Hosts    []string
+	Interval time.Duration
+	Executor *fleet.Executor
+
+	lock   sync.Mutex
+	hosts  map[string]*hostSample
+	calls  map[string]*callStats
+	errors map[callError]uint64
+}
+
+// hostSample is the state of a host at its last collection, only up and the collection if it failed.
+type hostSample struct {
+	up          bool
+	at          time.Time
+	duration    time.Duration
+	defaultZone string
+	panicMode   bool
+	state       string
+	zones       []zoneSample
+}
+
+// zoneSample is the state of a zone, driftError is the code of the error of Drift, the drift is unknown then.
+type zoneSample struct {
+	name       string
+	ports      int
+	richRules  int
+	masquerade bool
+	drift      int
+	driftError string
+}
+
+// callStats is the latency histogram of a method, counts[i] are the calls up to DEFAULT_BUCKETS[i], the last the slower ones.
+type callStats struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+type callError struct {
+	method string
+	code   string
+}
+
+// NewExporter collects hosts every DEFAULT_INTERVAL with the default executor.
+func NewExporter(hosts []string) *Exporter {
+	return &Exporter{Hosts: hosts, Interval: DEFAULT_INTERVAL, Executor: fleet.NewExecutor()}
+}
+
+// Intercept measures the latency of each call and counts its errors by firewalld code, see dbus.Interceptor.
+func (e *Exporter) Intercept(ctx context.Context, info dbus.CallInfo, next dbus.Invoker) (dbus.Result, error) {
+	start := time.Now()
+	result, err := next(ctx, info)
+	seconds := time.Since(start).Seconds()
+	method := info.FullMethod()
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.calls == nil {
+		e.calls, e.errors = map[string]*callStats{}, map[callError]uint64{}
+	}
+	stats, ok := e.calls[method]
+	if !ok {
+		stats = &callStats{counts: make([]uint64, len(DEFAULT_BUCKETS)+1)}
+		e.calls[method] = stats
+	}
+	stats.counts[sort.SearchFloat64s(DEFAULT_BUCKETS, seconds)]++
+	stats.count++
+	stats.sum += seconds
+	if err != nil {
+		e.errors[callError{method: method, code: codeOf(err)}]++
+	}
+	return result, err
+}
+
+// codeOf is the firewalld code of err, CODE_UNKNOWN without one.
+func codeOf(err error) string {
+	if code := dbus.ErrorCode(err); code != "" {
+		return code
+	}
+	return CODE_UNKNOWN
+}
+
+// Dialer returns dial whose clients Use Intercept, dial is dbus.NewDbusClientServiceContext if nil.
+func (e *Exporter) Dialer(dial fleet.Dialer) fleet.Dialer {
+	if dial == nil {
+		dial = dbus.NewDbusClientServiceContext
+	}
+	return func(ctx context.Context, addr string) (*dbus.DbusClientSerivce, error) {
+		client, err := dial(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+		client.Use(e.Intercept)
+		return client, nil
+	}
+}
+
+/*
+ * @title         Collect
+ * @description   collect the state of every host once, the hosts that fail are reported down until the next
+ *                  collection. The state of a host is its default zone, panic mode and state, and per zone the
+ *                  counts of the ports, rich rules and drift items between runtime and permanent and masquerade,
+ *                  a zone whose drift fails is reported by zone_drift_errors instead of failing the host.
+ * @auth          author           2021-10-18
+ * @param         ctx              context.Context "cancel to abort the collection."
+ * @return        report           *fleet.Report  "the result of each host, the values are internal."
+ */
+func (e *Exporter) Collect(ctx context.Context) (report *fleet.Report) {
+	executor := fleet.NewExecutor()
+	if e.Executor != nil {
+		copied := *e.Executor
+		executor = &copied
+	}
+	executor.Dial = e.Dialer(executor.Dial)
+
+	report = executor.Run(ctx, e.Hosts, func(ctx context.Context, client *dbus.DbusClientSerivce) (interface{}, error) {
+		return collectHost(client)
+	})
+	at := time.Now()
+	hosts := make(map[string]*hostSample, len(report.Results))
+	for _, result := range report.Results {
+		sample, ok := result.Value.(*hostSample)
+		if result.Err != nil || !ok {
+			sample = &hostSample{}
+		}
+		sample.up = result.Err == nil && ok
+		sample.at, sample.duration = at, result.Duration
+		hosts[result.Host] = sample
+	}
+
+	e.lock.Lock()
+	e.hosts = hosts
+	e.lock.Unlock()
+	return report
+}
+
+// collectHost reads the state of fw with its getters.
+func collectHost(fw dbus.Firewall) (sample *hostSample, err error) {
+	sample = &hostSample{defaultZone: fw.GetDefaultZone()}
+	if sample.panicMode, err = fw.QueryPanicMode(); err != nil {
+		return nil, err
+	}
+	if sample.state, err = fw.GetState(); err != nil {
+		return nil, err
+	}
+	zones, err := fw.GetZones()
+	if err != nil {
+		return nil, err
+	}
+	zones = append([]string{}, zones...)
+	sort.Strings(zones)
+	for _, zone := range zones {
+		ports, err := fw.GetPort(zone)
+		if err != nil {
+			return nil, err
+		}
+		rules, err := fw.GetRichRules(zone)
+		if err != nil {
+			return nil, err
+		}
+		masquerade, err := fw.QueryMasquerade(zone)
+		if err != nil {
+			return nil, err
+		}
+		item := zoneSample{name: zone, ports: len(ports), richRules: len(rules), masquerade: masquerade}
+		// a zone whose drift can not be computed, e.g. one not in permanent yet, does not fail the host.
+		if drift, err := fw.Drift(zone); err != nil {
+			item.driftError = codeOf(err)
+		} else {
+			item.drift = len(drift.Items)
+		}
+		sample.zones = append(sample.zones, item)
+	}
+	return sample, nil
+}
+
+// Run collects now and then every Interval until ctx is done.
+func (e *Exporter) Run(ctx context.Context) {
+	interval := e.Interval
+	if interval <= 0 {
+		interval = DEFAULT_INTERVAL
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		e.Collect(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ServeHTTP writes the metrics of the last collection and of the calls so far.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", CONTENT_TYPE)
+	w.Write(e.Render())
+}
+
+// Render returns the metrics in the Prometheus text format, the series are sorted by their labels.
+func (e *Exporter) Render() []byte {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	hosts := make([]string, 0, len(e.hosts))
+	for host := range e.hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	out := &writer{}
+
+	out.family("up", "gauge", "Whether the last collection of the host succeeded.")
+	for _, host := range hosts {
+		out.sample("up", "", boolValue(e.hosts[host].up), "host", host)
+	}
+	out.family("collect_duration_seconds", "gauge", "Duration of the last collection of the host.")
+	for _, host := range hosts {
+		out.sample("collect_duration_seconds", "", e.hosts[host].duration.Seconds(), "host", host)
+	}
+	out.family("collect_timestamp_seconds", "gauge", "Unix time of the last collection of the host.")
+	for _, host := range hosts {
+		out.sample("collect_timestamp_seconds", "", float64(e.hosts[host].at.UnixNano())/1e9, "host", host)
+	}
+
+	out.family("state", "gauge", "State of firewalld, 1 for the current one.")
+	e.eachUp(hosts, func(host string, sample *hostSample) {
+		out.sample("state", "", 1, "host", host, "state", sample.state)
+	})
+	out.family("panic_mode", "gauge", "Whether panic mode is enabled, all packets are dropped then.")
+	e.eachUp(hosts, func(host string, sample *hostSample) {
+		out.sample("panic_mode", "", boolValue(sample.panicMode), "host", host)
+	})
+	out.family("default_zone", "gauge", "Default zone of the host, 1 for the current one.")
+	e.eachUp(hosts, func(host string, sample *hostSample) {
+		out.sample("default_zone", "", 1, "host", host, "zone", sample.defaultZone)
+	})
+
+	zoneFamilies := []struct {
+		name, help string
+		value      func(zone zoneSample) float64
+	}{
+		{"zone_ports", "Number of runtime ports opened in the zone.", func(zone zoneSample) float64 { return float64(zone.ports) }},
+		{"zone_rich_rules", "Number of runtime rich rules of the zone.", func(zone zoneSample) float64 { return float64(zone.richRules) }},
+		{"zone_masquerade", "Whether masquerading is enabled in the runtime zone.", func(zone zoneSample) float64 { return boolValue(zone.masquerade) }},
+	}
+	for _, family := range zoneFamilies {
+		out.family(family.name, "gauge", family.help)
+		e.eachUp(hosts, func(host string, sample *hostSample) {
+			for _, zone := range sample.zones {
+				out.sample(family.name, "", family.value(zone), "host", host, "zone", zone.name)
+			}
+		})
+	}
+	out.family("zone_drift_items", "gauge", "Number of differences between runtime and permanent configuration of the zone.")
+	e.eachUp(hosts, func(host string, sample *hostSample) {
+		for _, zone := range sample.zones {
+			if zone.driftError == "" {
+				out.sample("zone_drift_items", "", float64(zone.drift), "host", host, "zone", zone.name)
+			}
+		}
+	})
+	out.family("zone_drift_errors", "gauge", "Whether the drift of the zone could not be computed at the last collection, by firewalld error code.")
+	e.eachUp(hosts, func(host string, sample *hostSample) {
+		for _, zone := range sample.zones {
+			if zone.driftError != "" {
+				out.sample("zone_drift_errors", "", 1, "host", host, "zone", zone.name, "code", zone.driftError)
+			}
+		}
+	})
+
+	methods := make([]string, 0, len(e.calls))
+	for method := range e.calls {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	out.family("call_duration_seconds", "histogram", "Latency of the D-Bus calls to firewalld by method.")
+	for _, method := range methods {
+		stats := e.calls[method]
+		var cumulative uint64
+		for index, bound := range DEFAULT_BUCKETS {
+			cumulative += stats.counts[index]
+			out.sample("call_duration_seconds", "_bucket", float64(cumulative), "method", method, "le", formatValue(bound))
+		}
+		out.sample("call_duration_seconds", "_bucket", float64(stats.count), "method", method, "le", "+Inf")
+		out.sample("call_duration_seconds", "_sum", stats.sum, "method", method)
+		out.sample("call_duration_seconds", "_count", float64(stats.count), "method", method)
+	}
+
+	failures := make([]callError, 0, len(e.errors))
+	for failure := range e.errors {
+		failures = append(failures, failure)
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].method != failures[j].method {
+			return failures[i].method < failures[j].method
+		}
+		return failures[i].code < failures[j].code
+	})
+	out.family("call_errors_total", "counter", "Failed D-Bus calls to firewalld by method and firewalld error code.")
+	for _, failure := range failures {
+		out.sample("call_errors_total", "", float64(e.errors[failure]), "method", failure.method, "code", failure.code)
+	}
+	return out.Bytes()
+}
+
+// eachUp calls fn with the hosts whose last collection succeeded.
+func (e *Exporter) eachUp(hosts []string, fn func(host string, sample *hostSample)) {
+	for _, host := range hosts {
+		if sample := e.hosts[host]; sample.up {
+			fn(host, sample)
+		}
+	}
+}
+
+// writer renders the text format, the names are prefixed with NAMESPACE.
+type writer struct {
+	bytes.Buffer
+}
+
+func (w *writer) family(name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s_%s %s\n# TYPE %s_%s %s\n", NAMESPACE, name, help, NAMESPACE, name, kind)
+}
+
+// sample writes a series, suffix is e.g. _bucket of a histogram, labels are name and value pairs.
+func (w *writer) sample(name, suffix string, value float64, labels ...string) {
+	fmt.Fprintf(w, "%s_%s%s", NAMESPACE, name, suffix)
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for index := 0; index+1 < len(labels); index += 2 {
+			pairs = append(pairs, labels[index]+`="`+labelEscaper.Replace(labels[index+1])+`"`)
+		}
+		fmt.Fprintf(w, "{%s}", strings.Join(pairs, ","))
+	}
+	fmt.Fprintf(w, " %s\n", formatValue(value))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata")
+
+func TestRender(t *testing.T) {
+	at := time.Unix(1634515200, 0)
+	calls := &callStats{counts: make([]uint64, len(DEFAULT_BUCKETS)+1), count: 4, sum: 12.0625}
+	calls.counts[0] = 1  // up to 1ms
+	calls.counts[4] = 2  // up to 25ms
+	calls.counts[13] = 1 // slower than 10s, only in +Inf
+	e := &Exporter{
+		hosts: map[string]*hostSample{
+			"10.0.0.1:55557": {
+				up: true, at: at, duration: 250 * time.Millisecond,
+				defaultZone: "public", state: "RUNNING",
+				zones: []zoneSample{
+					{name: "dmz", driftError: "INVALID_ZONE"},
+					{name: "public", ports: 2, richRules: 1, masquerade: true, drift: 3},
+				},
+			},
+			// a down host has only up and its collection.
+			"10.0.0.2:55557": {at: at, duration: 5 * time.Second},
+		},
+		calls: map[string]*callStats{"org.fedoraproject.FirewallD1.zone.getZoneSettings2": calls},
+		errors: map[callError]uint64{
+			{method: "org.fedoraproject.FirewallD1.zone.addPort", code: "ALREADY_ENABLED"}:  2,
+			{method: "org.fedoraproject.FirewallD1.zone.addPort", code: `say "hi"\` + "\n"}: 1,
+		},
+	}
+
+	got := e.Render()
+	golden := filepath.Join("testdata", "render.golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Render:\n%s\nwant %s", got, want)
+	}
+}
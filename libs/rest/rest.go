@@ -0,0 +1,574 @@
+/*
+ * Package rest serves the operations of the library as REST resources, for a central firewall controller, e.g.
+ *
+ *   GET    /hosts/10.0.0.1/zones/public/ports
+ *   POST   /hosts/10.0.0.1/zones/public/ports?scope=both         {"port": "8080", "protocol": "tcp"}
+ *   DELETE /hosts/10.0.0.1/zones/public/services?scope=permanent {"name": "http"}
+ *   POST   /hosts/10.0.0.1/reload
+ *
+ * The bodies are the json types of libs/dbus, Port, Service, ForwardPort, Rule and Settings.
+ * The status of a failed request follows the firewalld error code, see StatusOf, the body is an Error.
+ * GET /openapi.json returns the OpenAPI document of the resources.
+ * With a Policy the other requests need a client certificate or a bearer token, and a rule of libs/auth allowing
+ * the operation, read for GET, add-port for POST .../ports, remove-port for DELETE .../ports, reload and so on.
+ * Each request is a server span of the global provider of go.opentelemetry.io/otel, the parent is taken from the
+ * headers with the global propagator, e.g. traceparent with propagation.TraceContext.
+ */
+package rest
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cylonchau/gofirewallder/libs/auth"
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/libs/fleet"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TRACER_NAME is the instrumentation name of the spans of the requests.
+const TRACER_NAME = "github.com/cylonchau/gofirewallder/libs/rest"
+
+// MAX_BODY is the maximum size of a request body.
+const MAX_BODY = 1 << 20
+
+//go:embed openapi.json
+var openAPI []byte
+
+// statuses maps the firewalld error codes to the http status, other INVALID_ and MISSING_ codes are 400.
+var statuses = map[string]int{
+	"INVALID_ZONE":      http.StatusNotFound,
+	"INVALID_SERVICE":   http.StatusNotFound,
+	"INVALID_IPSET":     http.StatusNotFound,
+	"INVALID_POLICY":    http.StatusNotFound,
+	"INVALID_HELPER":    http.StatusNotFound,
+	"INVALID_ICMPTYPE":  http.StatusNotFound,
+	"INVALID_HOST":      http.StatusNotFound,
+	"NOT_ENABLED":       http.StatusNotFound,
+	"ALREADY_ENABLED":   http.StatusConflict,
+	"ALREADY_SET":       http.StatusConflict,
+	"NAME_CONFLICT":     http.StatusConflict,
+	"ZONE_CONFLICT":     http.StatusConflict,
+	"ZONE_ALREADY_SET":  http.StatusConflict,
+	"LOCKOUT":           http.StatusForbidden,
+	"BUILTIN_ZONE":      http.StatusForbidden,
+	"NOT_AUTHORIZED":    http.StatusForbidden,
+	"ACCESS_DENIED":     http.StatusForbidden,
+	"NOT_RUNNING":       http.StatusServiceUnavailable,
+	"NOT_AUTHENTICATED": http.StatusUnauthorized,
+}
+
+// items maps the resources of a zone to the item of their operations, e.g. POST .../ports is add-port.
+var items = map[string]string{
+	"ports":         "port",
+	"services":      "service",
+	"forward-ports": "forward-port",
+	"rich-rules":    "rich-rule",
+	"masquerade":    "masquerade",
+}
+
+// Error is the body of a failed request, Code is the firewalld error code if there is one.
+type Error struct {
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error"`
+}
+
+// Zones is the body of GET /hosts/{host}/zones.
+type Zones struct {
+	Default string   `json:"default"`
+	Zones   []string `json:"zones"`
+}
+
+// Masquerade is the body of /hosts/{host}/zones/{zone}/masquerade.
+type Masquerade struct {
+	Enabled bool `json:"enabled"`
+}
+
+/*
+ * Server is the http.Handler of the REST resources, a request connects to its host and closes the connection at the end.
+ *   Hosts    the hosts that may be managed, host:port, none if empty, the server does not relay to other hosts.
+ *   Timeout  time limit of a request on its host, fleet.DEFAULT_TIMEOUT if zero.
+ *   Dial     connects to a host, dbus.NewDbusClientServiceContext if nil.
+ *   Policy   authenticates the clients and authorizes their operations, every request is allowed if nil.
+ *            client certificates are taken from the verified chains, serve with tls.VerifyClientCertIfGiven.
+ *   Audit    records the changes, the actor is the authenticated client or else the client address, none if nil.
+ */
+type Server struct {
+	Hosts   []string
+	Timeout time.Duration
+	Dial    fleet.Dialer
+	Policy  *auth.Policy
+	Audit   *dbus.Auditor
+}
+
+// NewServer returns a server managing hosts, the port of a host defaults to dbus.PORT, see fleet.HostPort.
+func NewServer(hosts []string) *Server {
+	server := &Server{Timeout: fleet.DEFAULT_TIMEOUT, Dial: dbus.NewDbusClientServiceContext}
+	for _, host := range hosts {
+		server.Hosts = append(server.Hosts, fleet.HostPort(host))
+	}
+	return server
+}
+
+// ServeHTTP routes /openapi.json, /hosts, /hosts/{host}/zones, /hosts/{host}/zones/{zone}[/{resource}] and /hosts/{host}/reload.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	route := routeOf(parts)
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := otel.Tracer(TRACER_NAME).Start(ctx, r.Method+" "+route, trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest("", route, r)...))
+	defer span.End()
+
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.serve(recorder, r.WithContext(ctx), parts)
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(recorder.status))
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(recorder.status, trace.SpanKindServer))
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case len(parts) == 1 && parts[0] == "openapi.json":
+		if allow(w, r, http.MethodGet) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(openAPI)
+		}
+		return
+	}
+
+	identity, err := s.authenticate(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, StatusOf(err), err)
+		return
+	}
+	if s.Policy != nil {
+		r = r.WithContext(auth.NewContext(r.Context(), identity))
+	}
+	switch {
+	case len(parts) == 1 && parts[0] == "hosts":
+		if allow(w, r, http.MethodGet) {
+			hosts := s.Hosts
+			if hosts == nil {
+				hosts = []string{}
+			}
+			writeJSON(w, http.StatusOK, hosts)
+		}
+		return
+	case len(parts) < 3 || parts[0] != "hosts" || parts[1] == "":
+		writeError(w, http.StatusNotFound, fmt.Errorf("no resource %s", r.URL.Path))
+		return
+	}
+
+	host, err := s.host(parts[1])
+	if err == nil {
+		err = s.authorize(identity, actionOf(r, host, parts))
+	}
+	if err != nil {
+		writeError(w, StatusOf(err), err)
+		return
+	}
+	switch {
+	case len(parts) == 3 && parts[2] == "reload":
+		if allow(w, r, http.MethodPost) {
+			s.reply(w, r, host, http.StatusNoContent, func(client dbus.Firewall) (interface{}, error) {
+				return nil, client.Reload()
+			})
+		}
+	case len(parts) == 3 && parts[2] == "zones":
+		if allow(w, r, http.MethodGet) {
+			s.reply(w, r, host, http.StatusOK, zonesOf)
+		}
+	case len(parts) == 4 && parts[2] == "zones" && parts[3] != "":
+		if allow(w, r, http.MethodGet) {
+			s.settings(w, r, host, parts[3], func(settings *dbus.Settings) interface{} { return settings })
+		}
+	case len(parts) == 5 && parts[2] == "zones" && parts[3] != "":
+		s.resource(w, r, host, parts[3], parts[4])
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no resource %s", r.URL.Path))
+	}
+}
+
+// authenticate returns the identity of the client of r, none without Policy.
+func (s *Server) authenticate(r *http.Request) (identity auth.Identity, err error) {
+	if s.Policy == nil {
+		return identity, nil
+	}
+	return s.Policy.Authenticate(r.TLS, r.Header.Get("Authorization"))
+}
+
+func (s *Server) authorize(identity auth.Identity, action auth.Action) error {
+	if s.Policy == nil {
+		return nil
+	}
+	return s.Policy.Authorize(identity, action)
+}
+
+// actionOf is the operation of a request below /hosts/{host}, GET is read, POST and PUT add, DELETE removes.
+func actionOf(r *http.Request, host string, parts []string) auth.Action {
+	action := auth.Action{Host: host, Operation: auth.OP_READ}
+	if len(parts) > 3 && parts[2] == "zones" {
+		action.Zone = parts[3]
+	}
+	switch {
+	case len(parts) == 3 && parts[2] == "reload":
+		action.Operation = auth.OP_RELOAD
+	case len(parts) == 5 && items[parts[4]] != "":
+		switch r.Method {
+		case http.MethodPost, http.MethodPut:
+			action.Operation = "add-" + items[parts[4]]
+		case http.MethodDelete:
+			action.Operation = "remove-" + items[parts[4]]
+		}
+	}
+	return action
+}
+
+// host resolves the host of the path, it must be one of Hosts.
+func (s *Server) host(name string) (string, error) {
+	host := fleet.HostPort(name)
+	for _, value := range s.Hosts {
+		if value == host {
+			return host, nil
+		}
+	}
+	return "", fmt.Errorf("INVALID_HOST: %s is not managed", name)
+}
+
+// resource serves the items of a zone, GET lists them, POST adds the item of the body and DELETE removes it.
+func (s *Server) resource(w http.ResponseWriter, r *http.Request, host, zone, resource string) {
+	switch resource {
+	case "ports":
+		var port dbus.Port
+		s.items(w, r, host, zone, &port, func(settings *dbus.Settings) interface{} {
+			if settings.Port == nil {
+				return []dbus.Port{}
+			}
+			return settings.Port
+		}, func(z *dbus.Zone, scope dbus.Scope, add bool, timeout int) error {
+			if port.Port == "" {
+				return errors.New("INVALID_PORT: port is required")
+			}
+			if port.Protocol == "" {
+				port.Protocol = "tcp"
+			}
+			if add {
+				return z.AddPort(scope, port.Port+"/"+port.Protocol, timeout)
+			}
+			return z.RemovePort(scope, port.Port+"/"+port.Protocol)
+		})
+	case "services":
+		var service dbus.Service
+		s.items(w, r, host, zone, &service, func(settings *dbus.Settings) interface{} {
+			services := []dbus.Service{}
+			for _, name := range settings.Service {
+				services = append(services, dbus.Service{Name: name})
+			}
+			return services
+		}, func(z *dbus.Zone, scope dbus.Scope, add bool, timeout int) error {
+			if service.Name == "" {
+				return errors.New("MISSING_NAME: name is required")
+			}
+			if add {
+				return z.AddService(scope, service.Name, timeout)
+			}
+			return z.RemoveService(scope, service.Name)
+		})
+	case "forward-ports":
+		var forward dbus.ForwardPort
+		s.items(w, r, host, zone, &forward, func(settings *dbus.Settings) interface{} {
+			if settings.ForwardPort == nil {
+				return []dbus.ForwardPort{}
+			}
+			return settings.ForwardPort
+		}, func(z *dbus.Zone, scope dbus.Scope, add bool, timeout int) error {
+			if forward.Port == "" || (forward.ToPort == "" && forward.ToAddr == "") {
+				return errors.New("INVALID_FORWARD: port and toport or toaddr are required")
+			}
+			if forward.Protocol == "" {
+				forward.Protocol = "tcp"
+			}
+			portProtocol, toHostPort := forward.Port+"/"+forward.Protocol, net.JoinHostPort(forward.ToAddr, forward.ToPort)
+			if add {
+				return z.AddForwardPort(scope, portProtocol, toHostPort, timeout)
+			}
+			return z.RemoveForwardPort(scope, portProtocol, toHostPort)
+		})
+	case "rich-rules":
+		var rule dbus.Rule
+		s.items(w, r, host, zone, &rule, func(settings *dbus.Settings) interface{} {
+			if settings.Rule == nil {
+				return []dbus.Rule{}
+			}
+			return settings.Rule
+		}, func(z *dbus.Zone, scope dbus.Scope, add bool, timeout int) error {
+			if add {
+				return z.AddRichRule(scope, &rule, timeout)
+			}
+			return z.RemoveRichRule(scope, &rule)
+		})
+	case "masquerade":
+		s.masquerade(w, r, host, zone)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no resource %s", r.URL.Path))
+	}
+}
+
+/*
+ * @title         items
+ * @description   serve a list resource of a zone.
+ * @auth          author           2021-10-18
+ * @param         item             interface{}    "the body of POST and DELETE is decoded into it before change is called."
+ * @param         list             func           "the items of the zone settings for GET."
+ * @param         change           func           "adds or removes item in scope, timeout only for runtime additions."
+ */
+func (s *Server) items(w http.ResponseWriter, r *http.Request, host, zone string, item interface{},
+	list func(settings *dbus.Settings) interface{},
+	change func(z *dbus.Zone, scope dbus.Scope, add bool, timeout int) error) {
+	if !allow(w, r, http.MethodGet, http.MethodPost, http.MethodDelete) {
+		return
+	}
+	if r.Method == http.MethodGet {
+		s.settings(w, r, host, zone, list)
+		return
+	}
+
+	scope, timeout, err := changeOf(r)
+	if err == nil {
+		err = decode(r, item)
+	}
+	if err != nil {
+		writeError(w, StatusOf(err), err)
+		return
+	}
+	add, status := r.Method == http.MethodPost, http.StatusNoContent
+	if add {
+		status = http.StatusCreated
+	}
+	s.reply(w, r, host, status, func(client dbus.Firewall) (interface{}, error) {
+		return item, change(client.Zone(zone), scope, add, timeout)
+	})
+}
+
+// masquerade serves GET, PUT to enable and DELETE to disable masquerade of the zone.
+func (s *Server) masquerade(w http.ResponseWriter, r *http.Request, host, zone string) {
+	if !allow(w, r, http.MethodGet, http.MethodPut, http.MethodDelete) {
+		return
+	}
+	if r.Method == http.MethodGet {
+		s.settings(w, r, host, zone, func(settings *dbus.Settings) interface{} {
+			return Masquerade{Enabled: settings.Masquerade}
+		})
+		return
+	}
+
+	scope, timeout, err := changeOf(r)
+	if err != nil {
+		writeError(w, StatusOf(err), err)
+		return
+	}
+	if r.Method == http.MethodPut {
+		s.reply(w, r, host, http.StatusOK, func(client dbus.Firewall) (interface{}, error) {
+			return Masquerade{Enabled: true}, client.Zone(zone).EnableMasquerade(scope, timeout)
+		})
+		return
+	}
+	s.reply(w, r, host, http.StatusNoContent, func(client dbus.Firewall) (interface{}, error) {
+		return nil, client.Zone(zone).DisableMasquerade(scope)
+	})
+}
+
+// settings replies the part of the zone settings chosen by of, ?scope=permanent reads the permanent configuration.
+func (s *Server) settings(w http.ResponseWriter, r *http.Request, host, zone string, of func(settings *dbus.Settings) interface{}) {
+	scope, err := scopeOf(r)
+	if err == nil && scope == dbus.SCOPE_BOTH {
+		err = errors.New("INVALID_COMMAND: scope both can only be used for changes")
+	}
+	if err != nil {
+		writeError(w, StatusOf(err), err)
+		return
+	}
+	s.reply(w, r, host, http.StatusOK, func(client dbus.Firewall) (interface{}, error) {
+		var settings *dbus.Settings
+		var err error
+		if scope == dbus.SCOPE_PERMANENT {
+			settings, err = client.PermanentGetZoneSettings(zone)
+		} else {
+			settings, err = client.GetZoneSettings(zone)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return of(settings), nil
+	})
+}
+
+// reply runs op on host and writes its value with status, or the error with the status of StatusOf.
+func (s *Server) reply(w http.ResponseWriter, r *http.Request, host string, status int, op func(client dbus.Firewall) (interface{}, error)) {
+	executor := &fleet.Executor{Concurrency: 1, Timeout: s.Timeout, Dial: s.Dial}
+	report := executor.Run(r.Context(), []string{host}, func(ctx context.Context, client *dbus.DbusClientSerivce) (interface{}, error) {
+		if s.Audit != nil {
+			return op(s.Audit.Wrap(client, actorOf(r)))
+		}
+		return op(client)
+	})
+	result := report.Results[0]
+	if result.Err != nil {
+		writeError(w, StatusOf(result.Err), result.Err)
+		return
+	}
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+	writeJSON(w, status, result.Value)
+}
+
+// routeOf is the route of the path parts, e.g. /hosts/{host}/zones/{zone}/ports.
+func routeOf(parts []string) string {
+	route := append([]string{}, parts...)
+	if len(route) > 1 && route[0] == "hosts" {
+		route[1] = "{host}"
+	}
+	if len(route) > 3 && route[2] == "zones" {
+		route[3] = "{zone}"
+	}
+	return "/" + strings.Join(route, "/")
+}
+
+// statusRecorder keeps the status written to the ResponseWriter for the span of the request.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// actorOf is the client of r for the audit records, the authenticated name or else the client address.
+func actorOf(r *http.Request) string {
+	if identity, ok := auth.FromContext(r.Context()); ok {
+		return identity.Name
+	}
+	return r.RemoteAddr
+}
+
+func zonesOf(client dbus.Firewall) (interface{}, error) {
+	zones, err := client.GetZones()
+	if err != nil {
+		return nil, err
+	}
+	if zones == nil {
+		zones = []string{}
+	}
+	return Zones{Default: client.GetDefaultZone(), Zones: zones}, nil
+}
+
+/*
+ * @title         StatusOf
+ * @description   the http status of err, from its firewalld error code.
+ *                  404 unknown zone, service, host or item not enabled, 409 already enabled or conflicts,
+ *                  403 LOCKOUT and builtin zones, 400 other INVALID_ and MISSING_ codes,
+ *                  502 the host can not be connected, 504 the host timed out, 500 otherwise.
+ * @auth          author           2021-10-18
+ * @param         err              error          ""
+ * @return        status           int            ""
+ */
+func StatusOf(err error) (status int) {
+	var dialErr *fleet.DialError
+	switch {
+	case errors.As(err, &dialErr):
+		return http.StatusBadGateway
+	case errors.Is(err, fleet.ErrTimeout):
+		return http.StatusGatewayTimeout
+	}
+	code := dbus.ErrorCode(err)
+	if status, ok := statuses[code]; ok {
+		return status
+	}
+	if strings.HasPrefix(code, "INVALID_") || strings.HasPrefix(code, "MISSING_") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// scopeOf reads ?scope=runtime|permanent|both, runtime if it is not given.
+func scopeOf(r *http.Request) (dbus.Scope, error) {
+	switch value := r.URL.Query().Get("scope"); value {
+	case "", "runtime":
+		return dbus.SCOPE_RUNTIME, nil
+	case "permanent":
+		return dbus.SCOPE_PERMANENT, nil
+	case "both":
+		return dbus.SCOPE_BOTH, nil
+	default:
+		return 0, fmt.Errorf("INVALID_COMMAND: unknown scope '%s'", value)
+	}
+}
+
+// changeOf reads the scope and ?timeout= of a change, the timeout is seconds or a duration like 5m.
+func changeOf(r *http.Request) (scope dbus.Scope, timeout int, err error) {
+	if scope, err = scopeOf(r); err != nil {
+		return 0, 0, err
+	}
+	value := r.URL.Query().Get("timeout")
+	if value == "" {
+		return scope, 0, nil
+	}
+	if scope != dbus.SCOPE_RUNTIME {
+		return 0, 0, errors.New("INVALID_COMMAND: timeout can only be used with scope runtime")
+	}
+	if timeout, err = strconv.Atoi(value); err == nil && timeout >= 0 {
+		return scope, timeout, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < time.Second {
+		return 0, 0, fmt.Errorf("INVALID_VALUE: invalid timeout '%s'", value)
+	}
+	return scope, int(duration / time.Second), nil
+}
+
+func decode(r *http.Request, value interface{}) error {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, MAX_BODY))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(value); err != nil {
+		return fmt.Errorf("INVALID_VALUE: invalid body: %v", err)
+	}
+	return nil
+}
+
+// allow reports whether the method of r is one of methods, otherwise it replies 405.
+func allow(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, Error{Code: dbus.ErrorCode(err), Error: err.Error()})
+}
@@ -0,0 +1,92 @@
+package rest_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/libs/dbustest"
+	"github.com/cylonchau/gofirewallder/libs/rest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestRequestSpans(t *testing.T) {
+	firewalld, err := dbustest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer firewalld.Close()
+	spans := dbustest.RecordSpans(t)
+
+	// the caller's span is the parent of the span of the request.
+	ctx, caller := spans.Provider.Tracer("test").Start(context.Background(), "caller")
+	r := httptest.NewRequest(http.MethodGet, "/hosts/"+firewalld.Addr()+"/zones/public", nil)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+	w := httptest.NewRecorder()
+	rest.NewServer([]string{firewalld.Addr()}).ServeHTTP(w, r)
+	caller.End()
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET zone: status %d: %s", w.Code, w.Body)
+	}
+
+	request := spans.Named("GET /hosts/{host}/zones/{zone}")
+	if request.Parent().SpanID() != caller.SpanContext().SpanID() {
+		t.Error("the span of the request is not a child of the caller")
+	}
+	if request.Status().Code == codes.Error {
+		t.Errorf("request status: got %v, want no error", request.Status())
+	}
+	run := spans.Named("fleet.Run")
+	if run.Parent().SpanID() != request.SpanContext().SpanID() {
+		t.Error("fleet.Run is not a child of the request")
+	}
+	call := spans.Named("firewalld.GetZoneSettings")
+	if call.SpanContext().TraceID() != caller.SpanContext().TraceID() {
+		t.Error("firewalld.GetZoneSettings is not in the trace of the caller")
+	}
+	if zone := dbustest.AttributeOf(call, dbus.ATTR_ZONE); zone != "public" {
+		t.Errorf("zone attribute: got %q, want public", zone)
+	}
+
+	// a host that can not be connected is a bad gateway, an error of the server.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	down := listener.Addr().String()
+	listener.Close()
+	spans = dbustest.RecordSpans(t)
+	w = httptest.NewRecorder()
+	rest.NewServer([]string{down}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/hosts/"+down+"/zones/public", nil))
+	if w.Code != http.StatusBadGateway {
+		t.Fatalf("GET zone of a down host: status %d, want %d", w.Code, http.StatusBadGateway)
+	}
+	if request = spans.Named("GET /hosts/{host}/zones/{zone}"); request.Status().Code != codes.Error {
+		t.Errorf("request status: got %v, want error", request.Status())
+	}
+	if host := spans.Named("fleet.host"); host.Status().Code != codes.Error {
+		t.Errorf("fleet.host status: got %v, want error", host.Status())
+	}
+}
+
+func TestUnmanagedHost(t *testing.T) {
+	firewalld, err := dbustest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer firewalld.Close()
+
+	// without hosts the server manages none, it is not a relay to any host.
+	for _, hosts := range [][]string{nil, {"192.0.2.1"}} {
+		w := httptest.NewRecorder()
+		rest.NewServer(hosts).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/hosts/"+firewalld.Addr()+"/zones/public", nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("GET zone of a host not in %v: status %d, want %d", hosts, w.Code, http.StatusNotFound)
+		}
+	}
+}
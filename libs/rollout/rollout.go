@@ -0,0 +1,153 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/libs/fleet"
+)
+
+/*
+ * Rollout applies operations to a canary batch first, then to the remaining hosts in waves.
+ *   Executor       runs a wave, fleet.NewExecutor() if nil.
+ *   CanarySize     hosts in the canary batch, 1 if zero.
+ *   WaveSize       hosts per wave after the canary, all remaining hosts if zero.
+ *   MaxErrorRatio  halt when failed/attempted hosts so far exceeds it, e.g. 0.1; 0 halts on the first failure.
+ *   Rollback       undo the operations on hosts that were already changed when the rollout halts.
+ *   Pause          wait between waves, e.g. to let monitoring catch up.
+ */
+type Rollout struct {
+	Executor      *fleet.Executor
+	CanarySize    int
+	WaveSize      int
+	MaxErrorRatio float64
+	Rollback      bool
+	Pause         time.Duration
+}
+
+// Wave is the report of one batch of hosts.
+type Wave struct {
+	Name   string        `json:"name"`
+	Report *fleet.Report `json:"report"`
+}
+
+// Report is the outcome of a rollout.
+type Report struct {
+	Waves      []Wave        `json:"waves"`
+	Changed    []string      `json:"changed"`
+	Failed     []string      `json:"failed"`
+	Skipped    []string      `json:"skipped"`
+	Halted     bool          `json:"halted"`
+	Reason     string        `json:"reason,omitempty"`
+	RolledBack *fleet.Report `json:"rolledback,omitempty"`
+}
+
+/*
+ * @title         Run
+ * @description   apply ops on every host of inventory, canary first and then in waves.
+ *                  on a host the ops run in order, if one fails the completed ones are undone on that host.
+ * @auth          author           2021-10-14
+ * @param         ctx              context.Context  "cancel to halt the rollout."
+ * @param         inventory        []string         "host:port list, see fleet.ParseInventory."
+ * @param         ops              []dbus.Operation "the change, e.g. {add rich-rule ...}, {add port 80/tcp}"
+ * @return        report           *Report          "the waves, the changed, failed and skipped hosts."
+ */
+func (r *Rollout) Run(ctx context.Context, inventory []string, ops []dbus.Operation) *Report {
+	executor := r.Executor
+	if executor == nil {
+		executor = fleet.NewExecutor()
+	}
+	canary := r.CanarySize
+	if canary <= 0 {
+		canary = 1
+	}
+
+	report := &Report{}
+	attempted := 0
+	for start, index := 0, 0; start < len(inventory); index++ {
+		size := r.WaveSize
+		if index == 0 {
+			size = canary
+		}
+		if size <= 0 || start+size > len(inventory) {
+			size = len(inventory) - start
+		}
+		batch := inventory[start : start+size]
+		start += size
+
+		name := fmt.Sprintf("wave %d", index)
+		if index == 0 {
+			name = "canary"
+		}
+		wave := executor.Run(ctx, batch, Apply(ops))
+		report.Waves = append(report.Waves, Wave{Name: name, Report: wave})
+
+		for _, result := range wave.Results {
+			if result.Err != nil {
+				report.Failed = append(report.Failed, result.Host)
+			} else {
+				report.Changed = append(report.Changed, result.Host)
+			}
+		}
+		attempted += len(batch)
+
+		ratio := float64(len(report.Failed)) / float64(attempted)
+		switch {
+		case ratio > r.MaxErrorRatio:
+			report.Halted = true
+			report.Reason = fmt.Sprintf("%s: error ratio %.2f exceeds %.2f", name, ratio, r.MaxErrorRatio)
+		case ctx.Err() != nil:
+			report.Halted = true
+			report.Reason = ctx.Err().Error()
+		}
+		if report.Halted {
+			report.Skipped = append(report.Skipped, inventory[start:]...)
+			break
+		}
+
+		if r.Pause > 0 && start < len(inventory) {
+			select {
+			case <-time.After(r.Pause):
+			case <-ctx.Done():
+			}
+		}
+	}
+
+	if report.Halted && r.Rollback && len(report.Changed) > 0 {
+		// the rollout context may be canceled, the rollback must still run.
+		report.RolledBack = executor.Run(context.Background(), report.Changed, Apply(dbus.Inverse(ops)))
+	}
+	return report
+}
+
+// String renders the waves and the outcome of the rollout.
+func (r *Report) String() string {
+	var builder strings.Builder
+	for _, wave := range r.Waves {
+		fmt.Fprintf(&builder, "%s: %s", wave.Name, wave.Report.String())
+	}
+	if r.Halted {
+		fmt.Fprintf(&builder, "halted, %s; %d host(s) skipped.\n", r.Reason, len(r.Skipped))
+	}
+	if r.RolledBack != nil {
+		fmt.Fprintf(&builder, "rollback: %s", r.RolledBack.String())
+	}
+	fmt.Fprintf(&builder, "%d changed, %d failed.\n", len(r.Changed), len(r.Failed))
+	return builder.String()
+}
+
+// Apply returns a fleet operation executing ops as a dbus.Tx, if one fails the completed ones are undone.
+func Apply(ops []dbus.Operation) fleet.Operation {
+	return func(ctx context.Context, client *dbus.DbusClientSerivce) (interface{}, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := client.NewTx(ops...).Commit(); err != nil {
+			return nil, err
+		}
+		return len(ops), nil
+	}
+}
@@ -0,0 +1,221 @@
+package rpc
+
+import (
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/libs/rpc/pb"
+)
+
+/*
+ * The Proto functions convert the json types of libs/dbus into the messages of firewall.proto,
+ * the OfProto functions convert them back. A nested struct of Rule that is empty has no message,
+ * an action or a log of a rule is set if its message is present, e.g. Accept{} is dbus.Accept{Flag: true}.
+ */
+
+func PortProto(port dbus.Port) *pb.Port {
+	return &pb.Port{Port: port.Port, Protocol: port.Protocol}
+}
+
+func PortOfProto(port *pb.Port) dbus.Port {
+	return dbus.Port{Port: port.GetPort(), Protocol: port.GetProtocol()}
+}
+
+func ForwardPortProto(forward dbus.ForwardPort) *pb.ForwardPort {
+	return &pb.ForwardPort{Port: forward.Port, Protocol: forward.Protocol, ToPort: forward.ToPort, ToAddr: forward.ToAddr}
+}
+
+func ForwardPortOfProto(forward *pb.ForwardPort) dbus.ForwardPort {
+	return dbus.ForwardPort{Port: forward.GetPort(), Protocol: forward.GetProtocol(), ToPort: forward.GetToPort(), ToAddr: forward.GetToAddr()}
+}
+
+// RuleProto converts a rich rule, e.g. the Rule of Settings.
+func RuleProto(rule dbus.Rule) *pb.Rule {
+	message := &pb.Rule{Family: rule.Family, Priority: int32(rule.Priority), Masquerade: rule.Masquerade}
+	if !rule.Source.IsEmpty() {
+		message.Source = &pb.Source{Address: rule.Source.Address, Mac: rule.Source.Mac, Ipset: rule.Source.Ipset, Invert: rule.Source.Invert}
+	}
+	if !rule.Destination.IsEmpty() {
+		message.Destination = &pb.Destination{Address: rule.Destination.Address, Invert: rule.Destination.Invert}
+	}
+	if !rule.Service.IsEmpty() {
+		message.Service = &pb.Service{Name: rule.Service.Name}
+	}
+	if !rule.Port.IsEmpty() {
+		message.Port = PortProto(rule.Port)
+	}
+	if !rule.Protocol.IsEmpty() {
+		message.Protocol = &pb.Protocol{Value: rule.Protocol.Value}
+	}
+	if !rule.IcmpBlock.IsEmpty() {
+		message.IcmpBlock = &pb.IcmpBlock{Name: rule.IcmpBlock.Name}
+	}
+	if !rule.IcmpType.IsEmpty() {
+		message.IcmpType = &pb.IcmpType{Name: rule.IcmpType.Name}
+	}
+	if !rule.ForwardPort.IsEmpty() {
+		message.ForwardPort = ForwardPortProto(rule.ForwardPort)
+	}
+	if !rule.SourcePort.IsEmpty() {
+		message.SourcePort = &pb.Port{Port: rule.SourcePort.Port, Protocol: rule.SourcePort.Protocol}
+	}
+	if !rule.Log.IsEmpty() {
+		message.Log = &pb.Log{Prefix: rule.Log.Prefix, Level: rule.Log.Level, Limit: rule.Log.Limit.Value}
+	}
+	if !rule.Audit.IsEmpty() {
+		message.Audit = &pb.Audit{Limit: rule.Audit.Limit.Value}
+	}
+	if !rule.Accept.IsEmpty() {
+		message.Accept = &pb.Accept{Limit: rule.Accept.Limit.Value}
+	}
+	if !rule.Reject.IsEmpty() {
+		message.Reject = &pb.Reject{Type: rule.Reject.Type, Limit: rule.Reject.Limit.Value}
+	}
+	if !rule.Drop.IsEmpty() {
+		message.Drop = &pb.Drop{Limit: rule.Drop.Limit.Value}
+	}
+	if !rule.Mark.IsEmpty() {
+		message.Mark = &pb.Mark{Set: rule.Mark.Set, Limit: rule.Mark.Limit.Value}
+	}
+	return message
+}
+
+// RuleOfProto converts a rich rule message, the getters of pb return the zero values of absent messages.
+func RuleOfProto(message *pb.Rule) *dbus.Rule {
+	rule := &dbus.Rule{
+		Family:     message.GetFamily(),
+		Priority:   int(message.GetPriority()),
+		Masquerade: message.GetMasquerade(),
+		Source: dbus.Source{
+			Address: message.GetSource().GetAddress(),
+			Mac:     message.GetSource().GetMac(),
+			Ipset:   message.GetSource().GetIpset(),
+			Invert:  message.GetSource().GetInvert(),
+		},
+		Destination: dbus.Destination{Address: message.GetDestination().GetAddress(), Invert: message.GetDestination().GetInvert()},
+		Service:     dbus.Service{Name: message.GetService().GetName()},
+		Protocol:    dbus.Protocol{Value: message.GetProtocol().GetValue()},
+		IcmpBlock:   dbus.IcmpBlock{Name: message.GetIcmpBlock().GetName()},
+		IcmpType:    dbus.IcmpType{Name: message.GetIcmpType().GetName()},
+		SourcePort:  dbus.SourcePort{Port: message.GetSourcePort().GetPort(), Protocol: message.GetSourcePort().GetProtocol()},
+		Mark:        dbus.Mark{Set: message.GetMark().GetSet(), Limit: dbus.Limit{Value: message.GetMark().GetLimit()}},
+	}
+	if message.GetLog() != nil {
+		rule.Log = dbus.Log{
+			Flag:   true,
+			Prefix: message.GetLog().GetPrefix(),
+			Level:  message.GetLog().GetLevel(),
+			Limit:  dbus.Limit{Value: message.GetLog().GetLimit()},
+		}
+	}
+	if message.GetAudit() != nil {
+		rule.Audit = dbus.Audit{Flag: true, Limit: dbus.Limit{Value: message.GetAudit().GetLimit()}}
+	}
+	if message.GetReject() != nil {
+		rule.Reject = dbus.Reject{Flag: true, Type: message.GetReject().GetType(), Limit: dbus.Limit{Value: message.GetReject().GetLimit()}}
+	}
+	if message.GetPort() != nil {
+		rule.Port = PortOfProto(message.GetPort())
+	}
+	if message.GetForwardPort() != nil {
+		rule.ForwardPort = ForwardPortOfProto(message.GetForwardPort())
+	}
+	if message.GetAccept() != nil {
+		rule.Accept = dbus.Accept{Flag: true, Limit: dbus.Limit{Value: message.GetAccept().GetLimit()}}
+	}
+	if message.GetDrop() != nil {
+		rule.Drop = dbus.Drop{Flag: true, Limit: dbus.Limit{Value: message.GetDrop().GetLimit()}}
+	}
+	return rule
+}
+
+// SettingsProto converts the settings of a zone.
+func SettingsProto(settings *dbus.Settings) *pb.Settings {
+	message := &pb.Settings{
+		Version:            settings.Version,
+		Short:              settings.Short,
+		Description:        settings.Description,
+		Forward:            settings.Forward,
+		Target:             settings.Targe,
+		Services:           settings.Service,
+		Masquerade:         settings.Masquerade,
+		IcmpBlockInversion: settings.IcmpBlockInversion,
+	}
+	for _, port := range settings.Port {
+		message.Ports = append(message.Ports, PortProto(port))
+	}
+	for _, icmp := range settings.IcmpBlock {
+		message.IcmpBlocks = append(message.IcmpBlocks, &pb.IcmpBlock{Name: icmp.Name})
+	}
+	for _, forward := range settings.ForwardPort {
+		message.ForwardPorts = append(message.ForwardPorts, ForwardPortProto(forward))
+	}
+	for _, iface := range settings.Interface {
+		message.Interfaces = append(message.Interfaces, &pb.Interface{Name: iface.Name})
+	}
+	for _, source := range settings.Source {
+		message.Sources = append(message.Sources, &pb.Source{Address: source.Address, Mac: source.Mac, Ipset: source.Ipset, Invert: source.Invert})
+	}
+	for _, rule := range settings.Rule {
+		message.Rules = append(message.Rules, RuleProto(rule))
+	}
+	for _, protocol := range settings.Protocol {
+		message.Protocols = append(message.Protocols, &pb.Protocol{Value: protocol.Value})
+	}
+	for _, port := range settings.SourcePort {
+		message.SourcePorts = append(message.SourcePorts, &pb.Port{Port: port.Port, Protocol: port.Protocol})
+	}
+	return message
+}
+
+// SettingsOfProto converts a settings message back, e.g. for dbus.Firewall.PermanentSetZoneSettings.
+func SettingsOfProto(message *pb.Settings) *dbus.Settings {
+	settings := &dbus.Settings{
+		Version:            message.GetVersion(),
+		Short:              message.GetShort(),
+		Description:        message.GetDescription(),
+		Forward:            message.GetForward(),
+		Targe:              message.GetTarget(),
+		Service:            message.GetServices(),
+		Masquerade:         message.GetMasquerade(),
+		IcmpBlockInversion: message.GetIcmpBlockInversion(),
+	}
+	for _, port := range message.GetPorts() {
+		settings.Port = append(settings.Port, PortOfProto(port))
+	}
+	for _, icmp := range message.GetIcmpBlocks() {
+		settings.IcmpBlock = append(settings.IcmpBlock, dbus.IcmpBlock{Name: icmp.GetName()})
+	}
+	for _, forward := range message.GetForwardPorts() {
+		settings.ForwardPort = append(settings.ForwardPort, ForwardPortOfProto(forward))
+	}
+	for _, iface := range message.GetInterfaces() {
+		settings.Interface = append(settings.Interface, dbus.Interface{Name: iface.GetName()})
+	}
+	for _, source := range message.GetSources() {
+		settings.Source = append(settings.Source, dbus.Source{Address: source.GetAddress(), Mac: source.GetMac(), Ipset: source.GetIpset(), Invert: source.GetInvert()})
+	}
+	for _, rule := range message.GetRules() {
+		settings.Rule = append(settings.Rule, *RuleOfProto(rule))
+	}
+	for _, protocol := range message.GetProtocols() {
+		settings.Protocol = append(settings.Protocol, dbus.Protocol{Value: protocol.GetValue()})
+	}
+	for _, port := range message.GetSourcePorts() {
+		settings.SourcePort = append(settings.SourcePort, dbus.SourcePort{Port: port.GetPort(), Protocol: port.GetProtocol()})
+	}
+	return settings
+}
+
+// EventProto converts an event of host.
+func EventProto(host string, event dbus.Event) *pb.Event {
+	return &pb.Event{
+		Host:      host,
+		Name:      event.Name,
+		Interface: event.Interface,
+		Path:      string(event.Path),
+		Zone:      event.Zone,
+		Ipset:     event.IPSet,
+		Item:      event.Item,
+		Timeout:   uint32(event.Timeout),
+		Permanent: event.Permanent,
+	}
+}
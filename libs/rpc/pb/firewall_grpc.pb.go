@@ -0,0 +1,746 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// FirewallClient is the client API for Firewall service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FirewallClient interface {
+	ListZones(ctx context.Context, in *HostRequest, opts ...grpc.CallOption) (*Zones, error)
+	GetZoneSettings(ctx context.Context, in *ZoneRequest, opts ...grpc.CallOption) (*Settings, error)
+	ListPorts(ctx context.Context, in *ZoneRequest, opts ...grpc.CallOption) (*Ports, error)
+	AddPort(ctx context.Context, in *PortRequest, opts ...grpc.CallOption) (*Port, error)
+	RemovePort(ctx context.Context, in *PortRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListServices(ctx context.Context, in *ZoneRequest, opts ...grpc.CallOption) (*Services, error)
+	AddService(ctx context.Context, in *ServiceRequest, opts ...grpc.CallOption) (*Service, error)
+	RemoveService(ctx context.Context, in *ServiceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListForwardPorts(ctx context.Context, in *ZoneRequest, opts ...grpc.CallOption) (*ForwardPorts, error)
+	AddForwardPort(ctx context.Context, in *ForwardPortRequest, opts ...grpc.CallOption) (*ForwardPort, error)
+	RemoveForwardPort(ctx context.Context, in *ForwardPortRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListRichRules(ctx context.Context, in *ZoneRequest, opts ...grpc.CallOption) (*Rules, error)
+	AddRichRule(ctx context.Context, in *RuleRequest, opts ...grpc.CallOption) (*Rule, error)
+	RemoveRichRule(ctx context.Context, in *RuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetMasquerade(ctx context.Context, in *ZoneRequest, opts ...grpc.CallOption) (*Masquerade, error)
+	SetMasquerade(ctx context.Context, in *MasqueradeRequest, opts ...grpc.CallOption) (*Masquerade, error)
+	Reload(ctx context.Context, in *HostRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// WatchEvents streams the signals of firewalld of the hosts until the call is cancelled.
+	// A host that can not be connected or drops its connection sends one event with error set, the others go on.
+	WatchEvents(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Firewall_WatchEventsClient, error)
+}
+
+type firewallClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFirewallClient(cc grpc.ClientConnInterface) FirewallClient {
+	return &firewallClient{cc}
+}
+
+func (c *firewallClient) ListZones(ctx context.Context, in *HostRequest, opts ...grpc.CallOption) (*Zones, error) {
+	out := new(Zones)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/ListZones", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) GetZoneSettings(ctx context.Context, in *ZoneRequest, opts ...grpc.CallOption) (*Settings, error) {
+	out := new(Settings)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/GetZoneSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) ListPorts(ctx context.Context, in *ZoneRequest, opts ...grpc.CallOption) (*Ports, error) {
+	out := new(Ports)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/ListPorts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) AddPort(ctx context.Context, in *PortRequest, opts ...grpc.CallOption) (*Port, error) {
+	out := new(Port)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/AddPort", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) RemovePort(ctx context.Context, in *PortRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/RemovePort", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) ListServices(ctx context.Context, in *ZoneRequest, opts ...grpc.CallOption) (*Services, error) {
+	out := new(Services)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/ListServices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) AddService(ctx context.Context, in *ServiceRequest, opts ...grpc.CallOption) (*Service, error) {
+	out := new(Service)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/AddService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) RemoveService(ctx context.Context, in *ServiceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/RemoveService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) ListForwardPorts(ctx context.Context, in *ZoneRequest, opts ...grpc.CallOption) (*ForwardPorts, error) {
+	out := new(ForwardPorts)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/ListForwardPorts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) AddForwardPort(ctx context.Context, in *ForwardPortRequest, opts ...grpc.CallOption) (*ForwardPort, error) {
+	out := new(ForwardPort)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/AddForwardPort", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) RemoveForwardPort(ctx context.Context, in *ForwardPortRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/RemoveForwardPort", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) ListRichRules(ctx context.Context, in *ZoneRequest, opts ...grpc.CallOption) (*Rules, error) {
+	out := new(Rules)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/ListRichRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) AddRichRule(ctx context.Context, in *RuleRequest, opts ...grpc.CallOption) (*Rule, error) {
+	out := new(Rule)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/AddRichRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) RemoveRichRule(ctx context.Context, in *RuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/RemoveRichRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) GetMasquerade(ctx context.Context, in *ZoneRequest, opts ...grpc.CallOption) (*Masquerade, error) {
+	out := new(Masquerade)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/GetMasquerade", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) SetMasquerade(ctx context.Context, in *MasqueradeRequest, opts ...grpc.CallOption) (*Masquerade, error) {
+	out := new(Masquerade)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/SetMasquerade", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) Reload(ctx context.Context, in *HostRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/gofirewallder.v1.Firewall/Reload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) WatchEvents(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Firewall_WatchEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Firewall_ServiceDesc.Streams[0], "/gofirewallder.v1.Firewall/WatchEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &firewallWatchEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Firewall_WatchEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type firewallWatchEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *firewallWatchEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FirewallServer is the server API for Firewall service.
+// All implementations must embed UnimplementedFirewallServer
+// for forward compatibility
+type FirewallServer interface {
+	ListZones(context.Context, *HostRequest) (*Zones, error)
+	GetZoneSettings(context.Context, *ZoneRequest) (*Settings, error)
+	ListPorts(context.Context, *ZoneRequest) (*Ports, error)
+	AddPort(context.Context, *PortRequest) (*Port, error)
+	RemovePort(context.Context, *PortRequest) (*emptypb.Empty, error)
+	ListServices(context.Context, *ZoneRequest) (*Services, error)
+	AddService(context.Context, *ServiceRequest) (*Service, error)
+	RemoveService(context.Context, *ServiceRequest) (*emptypb.Empty, error)
+	ListForwardPorts(context.Context, *ZoneRequest) (*ForwardPorts, error)
+	AddForwardPort(context.Context, *ForwardPortRequest) (*ForwardPort, error)
+	RemoveForwardPort(context.Context, *ForwardPortRequest) (*emptypb.Empty, error)
+	ListRichRules(context.Context, *ZoneRequest) (*Rules, error)
+	AddRichRule(context.Context, *RuleRequest) (*Rule, error)
+	RemoveRichRule(context.Context, *RuleRequest) (*emptypb.Empty, error)
+	GetMasquerade(context.Context, *ZoneRequest) (*Masquerade, error)
+	SetMasquerade(context.Context, *MasqueradeRequest) (*Masquerade, error)
+	Reload(context.Context, *HostRequest) (*emptypb.Empty, error)
+	// WatchEvents streams the signals of firewalld of the hosts until the call is cancelled.
+	// A host that can not be connected or drops its connection sends one event with error set, the others go on.
+	WatchEvents(*WatchRequest, Firewall_WatchEventsServer) error
+	mustEmbedUnimplementedFirewallServer()
+}
+
+// UnimplementedFirewallServer must be embedded to have forward compatible implementations.
+type UnimplementedFirewallServer struct {
+}
+
+func (UnimplementedFirewallServer) ListZones(context.Context, *HostRequest) (*Zones, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListZones not implemented")
+}
+func (UnimplementedFirewallServer) GetZoneSettings(context.Context, *ZoneRequest) (*Settings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetZoneSettings not implemented")
+}
+func (UnimplementedFirewallServer) ListPorts(context.Context, *ZoneRequest) (*Ports, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPorts not implemented")
+}
+func (UnimplementedFirewallServer) AddPort(context.Context, *PortRequest) (*Port, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPort not implemented")
+}
+func (UnimplementedFirewallServer) RemovePort(context.Context, *PortRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemovePort not implemented")
+}
+func (UnimplementedFirewallServer) ListServices(context.Context, *ZoneRequest) (*Services, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServices not implemented")
+}
+func (UnimplementedFirewallServer) AddService(context.Context, *ServiceRequest) (*Service, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddService not implemented")
+}
+func (UnimplementedFirewallServer) RemoveService(context.Context, *ServiceRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveService not implemented")
+}
+func (UnimplementedFirewallServer) ListForwardPorts(context.Context, *ZoneRequest) (*ForwardPorts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListForwardPorts not implemented")
+}
+func (UnimplementedFirewallServer) AddForwardPort(context.Context, *ForwardPortRequest) (*ForwardPort, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddForwardPort not implemented")
+}
+func (UnimplementedFirewallServer) RemoveForwardPort(context.Context, *ForwardPortRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveForwardPort not implemented")
+}
+func (UnimplementedFirewallServer) ListRichRules(context.Context, *ZoneRequest) (*Rules, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRichRules not implemented")
+}
+func (UnimplementedFirewallServer) AddRichRule(context.Context, *RuleRequest) (*Rule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRichRule not implemented")
+}
+func (UnimplementedFirewallServer) RemoveRichRule(context.Context, *RuleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRichRule not implemented")
+}
+func (UnimplementedFirewallServer) GetMasquerade(context.Context, *ZoneRequest) (*Masquerade, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMasquerade not implemented")
+}
+func (UnimplementedFirewallServer) SetMasquerade(context.Context, *MasqueradeRequest) (*Masquerade, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMasquerade not implemented")
+}
+func (UnimplementedFirewallServer) Reload(context.Context, *HostRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reload not implemented")
+}
+func (UnimplementedFirewallServer) WatchEvents(*WatchRequest, Firewall_WatchEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedFirewallServer) mustEmbedUnimplementedFirewallServer() {}
+
+// UnsafeFirewallServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FirewallServer will
+// result in compilation errors.
+type UnsafeFirewallServer interface {
+	mustEmbedUnimplementedFirewallServer()
+}
+
+func RegisterFirewallServer(s grpc.ServiceRegistrar, srv FirewallServer) {
+	s.RegisterService(&Firewall_ServiceDesc, srv)
+}
+
+func _Firewall_ListZones_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).ListZones(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/ListZones",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).ListZones(ctx, req.(*HostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_GetZoneSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).GetZoneSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/GetZoneSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).GetZoneSettings(ctx, req.(*ZoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_ListPorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).ListPorts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/ListPorts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).ListPorts(ctx, req.(*ZoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_AddPort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PortRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).AddPort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/AddPort",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).AddPort(ctx, req.(*PortRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_RemovePort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PortRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).RemovePort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/RemovePort",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).RemovePort(ctx, req.(*PortRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_ListServices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).ListServices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/ListServices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).ListServices(ctx, req.(*ZoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_AddService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).AddService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/AddService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).AddService(ctx, req.(*ServiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_RemoveService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).RemoveService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/RemoveService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).RemoveService(ctx, req.(*ServiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_ListForwardPorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).ListForwardPorts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/ListForwardPorts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).ListForwardPorts(ctx, req.(*ZoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_AddForwardPort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardPortRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).AddForwardPort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/AddForwardPort",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).AddForwardPort(ctx, req.(*ForwardPortRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_RemoveForwardPort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardPortRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).RemoveForwardPort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/RemoveForwardPort",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).RemoveForwardPort(ctx, req.(*ForwardPortRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_ListRichRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).ListRichRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/ListRichRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).ListRichRules(ctx, req.(*ZoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_AddRichRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).AddRichRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/AddRichRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).AddRichRule(ctx, req.(*RuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_RemoveRichRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).RemoveRichRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/RemoveRichRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).RemoveRichRule(ctx, req.(*RuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_GetMasquerade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).GetMasquerade(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/GetMasquerade",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).GetMasquerade(ctx, req.(*ZoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_SetMasquerade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MasqueradeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).SetMasquerade(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/SetMasquerade",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).SetMasquerade(ctx, req.(*MasqueradeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_Reload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).Reload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofirewallder.v1.Firewall/Reload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).Reload(ctx, req.(*HostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FirewallServer).WatchEvents(m, &firewallWatchEventsServer{stream})
+}
+
+type Firewall_WatchEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type firewallWatchEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *firewallWatchEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Firewall_ServiceDesc is the grpc.ServiceDesc for Firewall service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Firewall_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gofirewallder.v1.Firewall",
+	HandlerType: (*FirewallServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListZones",
+			Handler:    _Firewall_ListZones_Handler,
+		},
+		{
+			MethodName: "GetZoneSettings",
+			Handler:    _Firewall_GetZoneSettings_Handler,
+		},
+		{
+			MethodName: "ListPorts",
+			Handler:    _Firewall_ListPorts_Handler,
+		},
+		{
+			MethodName: "AddPort",
+			Handler:    _Firewall_AddPort_Handler,
+		},
+		{
+			MethodName: "RemovePort",
+			Handler:    _Firewall_RemovePort_Handler,
+		},
+		{
+			MethodName: "ListServices",
+			Handler:    _Firewall_ListServices_Handler,
+		},
+		{
+			MethodName: "AddService",
+			Handler:    _Firewall_AddService_Handler,
+		},
+		{
+			MethodName: "RemoveService",
+			Handler:    _Firewall_RemoveService_Handler,
+		},
+		{
+			MethodName: "ListForwardPorts",
+			Handler:    _Firewall_ListForwardPorts_Handler,
+		},
+		{
+			MethodName: "AddForwardPort",
+			Handler:    _Firewall_AddForwardPort_Handler,
+		},
+		{
+			MethodName: "RemoveForwardPort",
+			Handler:    _Firewall_RemoveForwardPort_Handler,
+		},
+		{
+			MethodName: "ListRichRules",
+			Handler:    _Firewall_ListRichRules_Handler,
+		},
+		{
+			MethodName: "AddRichRule",
+			Handler:    _Firewall_AddRichRule_Handler,
+		},
+		{
+			MethodName: "RemoveRichRule",
+			Handler:    _Firewall_RemoveRichRule_Handler,
+		},
+		{
+			MethodName: "GetMasquerade",
+			Handler:    _Firewall_GetMasquerade_Handler,
+		},
+		{
+			MethodName: "SetMasquerade",
+			Handler:    _Firewall_SetMasquerade_Handler,
+		},
+		{
+			MethodName: "Reload",
+			Handler:    _Firewall_Reload_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _Firewall_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "firewall.proto",
+}
@@ -0,0 +1,553 @@
+/*
+ * Package rpc serves the operations of libs/rest as the gRPC service Firewall of pb/firewall.proto, e.g.
+ *
+ *   server := grpc.NewServer()
+ *   pb.RegisterFirewallServer(server, rpc.NewServer(inventory))
+ *   server.Serve(listener)
+ *
+ * A call connects to its host and closes the connection at the end, WatchEvents keeps the connections of its hosts.
+ * The status code of a failed call follows the firewalld error code like the http status of rest.StatusOf.
+ * With a Policy the calls are authorized like the requests of libs/rest, the client is the verified certificate of
+ * credentials.NewTLS or the metadata authorization: Bearer <token>.
+ * Each call is a server span like the requests of libs/rest, the parent is taken from the incoming metadata.
+ */
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cylonchau/gofirewallder/libs/auth"
+	"github.com/cylonchau/gofirewallder/libs/dbus"
+	"github.com/cylonchau/gofirewallder/libs/fleet"
+	"github.com/cylonchau/gofirewallder/libs/rest"
+	"github.com/cylonchau/gofirewallder/libs/rpc/pb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// TRACER_NAME is the instrumentation name of the spans of the calls.
+const TRACER_NAME = "github.com/cylonchau/gofirewallder/libs/rpc"
+
+// statusCodes maps the http status of rest.StatusOf to the grpc code.
+var statusCodes = map[int]codes.Code{
+	400: codes.InvalidArgument,
+	401: codes.Unauthenticated,
+	403: codes.PermissionDenied,
+	404: codes.NotFound,
+	409: codes.AlreadyExists,
+	502: codes.Unavailable,
+	503: codes.Unavailable,
+	504: codes.DeadlineExceeded,
+}
+
+/*
+ * Server implements pb.FirewallServer.
+ *   Hosts    the hosts that may be managed, host:port, none if empty, WatchEvents watches them if its request has none.
+ *   Timeout  time limit of a call on its host, fleet.DEFAULT_TIMEOUT if zero, WatchEvents has none.
+ *   Dial     connects to a host, dbus.NewDbusClientServiceContext if nil.
+ *   Policy   authenticates the clients and authorizes their calls, every call is allowed if nil.
+ *   Audit    records the changes, the actor is the authenticated client or else the peer address, none if nil.
+ */
+type Server struct {
+	pb.UnimplementedFirewallServer
+
+	Hosts   []string
+	Timeout time.Duration
+	Dial    fleet.Dialer
+	Policy  *auth.Policy
+	Audit   *dbus.Auditor
+}
+
+// NewServer returns a server managing hosts, the port of a host defaults to dbus.PORT, see fleet.HostPort.
+func NewServer(hosts []string) *Server {
+	server := &Server{Timeout: fleet.DEFAULT_TIMEOUT, Dial: dbus.NewDbusClientServiceContext}
+	for _, host := range hosts {
+		server.Hosts = append(server.Hosts, fleet.HostPort(host))
+	}
+	return server
+}
+
+func (s *Server) ListZones(ctx context.Context, req *pb.HostRequest) (*pb.Zones, error) {
+	value, err := s.call(ctx, auth.Action{Host: req.GetHost(), Operation: auth.OP_READ}, func(client dbus.Firewall) (interface{}, error) {
+		zones, err := client.GetZones()
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Zones{Default: client.GetDefaultZone(), Zones: zones}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*pb.Zones), nil
+}
+
+func (s *Server) GetZoneSettings(ctx context.Context, req *pb.ZoneRequest) (*pb.Settings, error) {
+	settings, err := s.settings(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return SettingsProto(settings), nil
+}
+
+func (s *Server) ListPorts(ctx context.Context, req *pb.ZoneRequest) (*pb.Ports, error) {
+	settings, err := s.settings(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Ports{Ports: SettingsProto(settings).Ports}, nil
+}
+
+func (s *Server) AddPort(ctx context.Context, req *pb.PortRequest) (*pb.Port, error) {
+	return req.GetPort(), s.changePort(ctx, req, true)
+}
+
+func (s *Server) RemovePort(ctx context.Context, req *pb.PortRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, s.changePort(ctx, req, false)
+}
+
+func (s *Server) changePort(ctx context.Context, req *pb.PortRequest, add bool) error {
+	port := PortOfProto(req.GetPort())
+	if port.Port == "" {
+		return statusOf(errors.New("INVALID_PORT: port is required"))
+	}
+	if port.Protocol == "" {
+		port.Protocol = "tcp"
+	}
+	return s.change(ctx, operationOf(add, "port"), req.GetHost(), req.GetZone(), req.GetScope(), req.GetTimeout(), func(zone *dbus.Zone, scope dbus.Scope, timeout int) error {
+		if add {
+			return zone.AddPort(scope, port.Port+"/"+port.Protocol, timeout)
+		}
+		return zone.RemovePort(scope, port.Port+"/"+port.Protocol)
+	})
+}
+
+func (s *Server) ListServices(ctx context.Context, req *pb.ZoneRequest) (*pb.Services, error) {
+	settings, err := s.settings(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	services := &pb.Services{}
+	for _, name := range settings.Service {
+		services.Services = append(services.Services, &pb.Service{Name: name})
+	}
+	return services, nil
+}
+
+func (s *Server) AddService(ctx context.Context, req *pb.ServiceRequest) (*pb.Service, error) {
+	return req.GetService(), s.changeService(ctx, req, true)
+}
+
+func (s *Server) RemoveService(ctx context.Context, req *pb.ServiceRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, s.changeService(ctx, req, false)
+}
+
+func (s *Server) changeService(ctx context.Context, req *pb.ServiceRequest, add bool) error {
+	name := req.GetService().GetName()
+	if name == "" {
+		return statusOf(errors.New("MISSING_NAME: name is required"))
+	}
+	return s.change(ctx, operationOf(add, "service"), req.GetHost(), req.GetZone(), req.GetScope(), req.GetTimeout(), func(zone *dbus.Zone, scope dbus.Scope, timeout int) error {
+		if add {
+			return zone.AddService(scope, name, timeout)
+		}
+		return zone.RemoveService(scope, name)
+	})
+}
+
+func (s *Server) ListForwardPorts(ctx context.Context, req *pb.ZoneRequest) (*pb.ForwardPorts, error) {
+	settings, err := s.settings(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ForwardPorts{ForwardPorts: SettingsProto(settings).ForwardPorts}, nil
+}
+
+func (s *Server) AddForwardPort(ctx context.Context, req *pb.ForwardPortRequest) (*pb.ForwardPort, error) {
+	return req.GetForwardPort(), s.changeForwardPort(ctx, req, true)
+}
+
+func (s *Server) RemoveForwardPort(ctx context.Context, req *pb.ForwardPortRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, s.changeForwardPort(ctx, req, false)
+}
+
+func (s *Server) changeForwardPort(ctx context.Context, req *pb.ForwardPortRequest, add bool) error {
+	forward := ForwardPortOfProto(req.GetForwardPort())
+	if forward.Port == "" || (forward.ToPort == "" && forward.ToAddr == "") {
+		return statusOf(errors.New("INVALID_FORWARD: port and to_port or to_addr are required"))
+	}
+	if forward.Protocol == "" {
+		forward.Protocol = "tcp"
+	}
+	portProtocol, toHostPort := forward.Port+"/"+forward.Protocol, net.JoinHostPort(forward.ToAddr, forward.ToPort)
+	return s.change(ctx, operationOf(add, "forward-port"), req.GetHost(), req.GetZone(), req.GetScope(), req.GetTimeout(), func(zone *dbus.Zone, scope dbus.Scope, timeout int) error {
+		if add {
+			return zone.AddForwardPort(scope, portProtocol, toHostPort, timeout)
+		}
+		return zone.RemoveForwardPort(scope, portProtocol, toHostPort)
+	})
+}
+
+func (s *Server) ListRichRules(ctx context.Context, req *pb.ZoneRequest) (*pb.Rules, error) {
+	settings, err := s.settings(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Rules{Rules: SettingsProto(settings).Rules}, nil
+}
+
+func (s *Server) AddRichRule(ctx context.Context, req *pb.RuleRequest) (*pb.Rule, error) {
+	return req.GetRule(), s.changeRichRule(ctx, req, true)
+}
+
+func (s *Server) RemoveRichRule(ctx context.Context, req *pb.RuleRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, s.changeRichRule(ctx, req, false)
+}
+
+func (s *Server) changeRichRule(ctx context.Context, req *pb.RuleRequest, add bool) error {
+	if req.GetRule() == nil {
+		return statusOf(errors.New("INVALID_RULE: rule is required"))
+	}
+	rule := RuleOfProto(req.GetRule())
+	return s.change(ctx, operationOf(add, "rich-rule"), req.GetHost(), req.GetZone(), req.GetScope(), req.GetTimeout(), func(zone *dbus.Zone, scope dbus.Scope, timeout int) error {
+		if add {
+			return zone.AddRichRule(scope, rule, timeout)
+		}
+		return zone.RemoveRichRule(scope, rule)
+	})
+}
+
+func (s *Server) GetMasquerade(ctx context.Context, req *pb.ZoneRequest) (*pb.Masquerade, error) {
+	settings, err := s.settings(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Masquerade{Enabled: settings.Masquerade}, nil
+}
+
+func (s *Server) SetMasquerade(ctx context.Context, req *pb.MasqueradeRequest) (*pb.Masquerade, error) {
+	enabled := req.GetEnabled()
+	err := s.change(ctx, operationOf(enabled, "masquerade"), req.GetHost(), req.GetZone(), req.GetScope(), req.GetTimeout(), func(zone *dbus.Zone, scope dbus.Scope, timeout int) error {
+		if enabled {
+			return zone.EnableMasquerade(scope, timeout)
+		}
+		return zone.DisableMasquerade(scope)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Masquerade{Enabled: enabled}, nil
+}
+
+func (s *Server) Reload(ctx context.Context, req *pb.HostRequest) (*emptypb.Empty, error) {
+	_, err := s.call(ctx, auth.Action{Host: req.GetHost(), Operation: auth.OP_RELOAD}, func(client dbus.Firewall) (interface{}, error) {
+		return nil, client.Reload()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+/*
+ * @title         WatchEvents
+ * @description   stream the events of the hosts of req, or of Hosts, until the call is cancelled or every host has ended.
+ *                  a host that can not be connected or drops its connection sends an event with Error.
+ * @auth          author           2021-10-18
+ * @param         req              *pb.WatchRequest "the hosts, addr[:port]."
+ * @param         stream           pb.Firewall_WatchEventsServer ""
+ * @return        error            error          "InvalidArgument without hosts, NotFound for hosts that are not managed."
+ */
+func (s *Server) WatchEvents(req *pb.WatchRequest, stream pb.Firewall_WatchEventsServer) (err error) {
+	ctx, end := startSpan(stream.Context())
+	defer func() { end(err) }()
+
+	hosts := s.Hosts
+	if len(req.GetHosts()) > 0 {
+		hosts = nil
+		for _, name := range req.GetHosts() {
+			host, err := s.host(name)
+			if err != nil {
+				return statusOf(err)
+			}
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		return status.Error(codes.InvalidArgument, "no host to watch")
+	}
+	for _, host := range hosts {
+		if _, err := s.authorize(ctx, auth.Action{Host: host, Operation: auth.OP_READ}); err != nil {
+			return statusOf(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := make(chan *pb.Event)
+	var wg sync.WaitGroup
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			s.watch(ctx, host, events)
+		}(host)
+	}
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	for event := range events {
+		if err := stream.Send(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watch sends the events of host to events until ctx is done, the end of the connection is sent as an error event.
+func (s *Server) watch(ctx context.Context, host string, events chan<- *pb.Event) {
+	send := func(event *pb.Event) bool {
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	dial := s.Dial
+	if dial == nil {
+		dial = dbus.NewDbusClientServiceContext
+	}
+	client, err := dial(ctx, host)
+	if err != nil {
+		send(&pb.Event{Host: host, Error: err.Error()})
+		return
+	}
+	defer client.Close()
+	stream, err := client.Events(ctx)
+	if err != nil {
+		send(&pb.Event{Host: host, Error: err.Error()})
+		return
+	}
+	for event := range stream {
+		if !send(EventProto(host, event)) {
+			return
+		}
+	}
+	if ctx.Err() == nil {
+		send(&pb.Event{Host: host, Error: "connection closed"})
+	}
+}
+
+// settings reads the zone settings of req, in the configuration of its scope.
+func (s *Server) settings(ctx context.Context, req *pb.ZoneRequest) (*dbus.Settings, error) {
+	scope, err := scopeOf(req.GetScope())
+	if err == nil && scope == dbus.SCOPE_BOTH {
+		err = errors.New("INVALID_COMMAND: scope both can only be used for changes")
+	}
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	value, err := s.call(ctx, auth.Action{Host: req.GetHost(), Zone: req.GetZone(), Operation: auth.OP_READ}, func(client dbus.Firewall) (interface{}, error) {
+		if scope == dbus.SCOPE_PERMANENT {
+			return client.PermanentGetZoneSettings(req.GetZone())
+		}
+		return client.GetZoneSettings(req.GetZone())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*dbus.Settings), nil
+}
+
+// change runs a change of a zone authorized as operation, the timeout only with the runtime scope.
+func (s *Server) change(ctx context.Context, operation, host, zone string, scope pb.Scope, timeout uint32, op func(zone *dbus.Zone, scope dbus.Scope, timeout int) error) error {
+	configuration, err := scopeOf(scope)
+	if err == nil && timeout > 0 && configuration != dbus.SCOPE_RUNTIME {
+		err = errors.New("INVALID_COMMAND: timeout can only be used with scope runtime")
+	}
+	if err != nil {
+		return statusOf(err)
+	}
+	_, err = s.call(ctx, auth.Action{Host: host, Zone: zone, Operation: operation}, func(client dbus.Firewall) (interface{}, error) {
+		return nil, op(client.Zone(zone), configuration, int(timeout))
+	})
+	return err
+}
+
+// call runs op on the host of action if the client may, the error is a grpc status.
+func (s *Server) call(ctx context.Context, action auth.Action, op func(client dbus.Firewall) (interface{}, error)) (value interface{}, err error) {
+	attributes := []attribute.KeyValue{dbus.ATTR_HOST.String(action.Host)}
+	if action.Zone != "" {
+		attributes = append(attributes, dbus.ATTR_ZONE.String(action.Zone))
+	}
+	ctx, end := startSpan(ctx, attributes...)
+	defer func() { end(err) }()
+
+	host, err := s.host(action.Host)
+	var actor string
+	if err == nil {
+		action.Host = host
+		actor, err = s.authorize(ctx, action)
+	}
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	executor := &fleet.Executor{Concurrency: 1, Timeout: s.Timeout, Dial: s.Dial}
+	report := executor.Run(ctx, []string{host}, func(ctx context.Context, client *dbus.DbusClientSerivce) (interface{}, error) {
+		if s.Audit != nil {
+			return op(s.Audit.Wrap(client, actor))
+		}
+		return op(client)
+	})
+	if result := report.Results[0]; result.Err != nil {
+		return nil, statusOf(result.Err)
+	}
+	return report.Results[0].Value, nil
+}
+
+/*
+ * @title         startSpan
+ * @description   start the server span of the call of ctx, named after its method, e.g. gofirewallder.Firewall/AddPort.
+ *                  the parent is taken from the incoming metadata with the global propagator of go.opentelemetry.io/otel.
+ * @auth          author           2021-10-18
+ * @param         ctx              context.Context "the context of the call."
+ * @return        ctx              context.Context "the context of the span, the clients of the call run in it."
+ * @return        end              func(error)    "ends the span with the grpc status of the error."
+ */
+func startSpan(ctx context.Context, attributes ...attribute.KeyValue) (context.Context, func(err error)) {
+	method, _ := grpc.Method(ctx)
+	method = strings.TrimPrefix(method, "/")
+	attributes = append(attributes, semconv.RPCSystemKey.String("grpc"))
+	if index := strings.LastIndex(method, "/"); index >= 0 {
+		attributes = append(attributes, semconv.RPCServiceKey.String(method[:index]), semconv.RPCMethodKey.String(method[index+1:]))
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	}
+	ctx, span := otel.Tracer(TRACER_NAME).Start(ctx, method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attributes...))
+	return ctx, func(err error) {
+		code := status.Code(err)
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, status.Convert(err).Message())
+		}
+		span.End()
+	}
+}
+
+// metadataCarrier reads and writes the trace context in grpc metadata, see propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// authorize checks action against Policy for the client of ctx, the actor is its name or else the peer address.
+// The error is not a grpc status.
+func (s *Server) authorize(ctx context.Context, action auth.Action) (actor string, err error) {
+	var state *tls.ConnectionState
+	if client, ok := peer.FromContext(ctx); ok {
+		if client.Addr != nil {
+			actor = client.Addr.String()
+		}
+		if info, ok := client.AuthInfo.(credentials.TLSInfo); ok {
+			state = &info.State
+		}
+	}
+	if s.Policy == nil {
+		return actor, nil
+	}
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+	identity, err := s.Policy.Authenticate(state, authorization)
+	if err != nil {
+		return actor, err
+	}
+	return identity.Name, s.Policy.Authorize(identity, action)
+}
+
+// host resolves the host of a request, it must be one of Hosts.
+func (s *Server) host(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("INVALID_HOST: host is required")
+	}
+	host := fleet.HostPort(name)
+	for _, value := range s.Hosts {
+		if value == host {
+			return host, nil
+		}
+	}
+	return "", fmt.Errorf("INVALID_HOST: %s is not managed", name)
+}
+
+// operationOf is the operation of auth adding or removing item, e.g. add-port.
+func operationOf(add bool, item string) string {
+	if add {
+		return "add-" + item
+	}
+	return "remove-" + item
+}
+
+func scopeOf(scope pb.Scope) (dbus.Scope, error) {
+	switch scope {
+	case pb.Scope_SCOPE_RUNTIME:
+		return dbus.SCOPE_RUNTIME, nil
+	case pb.Scope_SCOPE_PERMANENT:
+		return dbus.SCOPE_PERMANENT, nil
+	case pb.Scope_SCOPE_BOTH:
+		return dbus.SCOPE_BOTH, nil
+	}
+	return 0, fmt.Errorf("INVALID_COMMAND: unknown scope %d", scope)
+}
+
+// statusOf converts err into a grpc status, the code follows rest.StatusOf, the message is the firewalld error.
+func statusOf(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	code, ok := statusCodes[rest.StatusOf(err)]
+	if !ok {
+		code = codes.Internal
+	}
+	return status.Error(code, err.Error())
+}
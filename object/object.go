@@ -8,6 +8,8 @@ const (
 	IPSET          = INTERFACE + ".ipset"
 	POLICIES       = INTERFACE + ".policies"
 	ZONE           = INTERFACE + ".zone"
+	POLICY         = INTERFACE + ".policy"
+	EXCEPTION      = INTERFACE + ".Exception"
 	INTROSPECTABLE = "org.freedesktop.DBus.Introspectable"
 	PROPERTIES     = "org.freedesktop.DBus.Properties"
 
@@ -29,9 +31,22 @@ const (
 	ICMP_INTERFACE = INTERFACE + ".config.icmptype"
 
 	// org.fedoraproject.FirewallD1
-	INTERFACE_GETDEFAULTZONE  = INTERFACE + ".getDefaultZone"
-	INTERFACE_GETZONESETTINGS = INTERFACE + ".getZoneSettings"
-	INTERFACE_RELOAD          = INTERFACE + ".completeReload"
+	INTERFACE_GETDEFAULTZONE     = INTERFACE + ".getDefaultZone"
+	INTERFACE_GETZONESETTINGS    = INTERFACE + ".getZoneSettings"
+	INTERFACE_RELOAD             = INTERFACE + ".completeReload"
+	INTERFACE_GETSERVICESETTINGS = INTERFACE + ".getServiceSettings"
+	INTERFACE_SETDEFAULTZONE     = INTERFACE + ".setDefaultZone"
+	INTERFACE_RUNTIMETOPERMANENT = INTERFACE + ".runtimeToPermanent"
+	INTERFACE_QUERYPANICMODE     = INTERFACE + ".queryPanicMode"
+
+	// org.freedesktop.DBus.Properties, the state of firewalld is the property state of org.fedoraproject.FirewallD1.
+	PROPERTIES_GET = PROPERTIES + ".Get"
+	PROPERTY_STATE = "state"
+
+	// the properties of the config objects, name: the name of the object, builtin: shipped with firewalld, default: not changed since.
+	PROPERTY_NAME    = "name"
+	PROPERTY_BUILTIN = "builtin"
+	PROPERTY_DEFAULT = "default"
 
 	//config
 
@@ -45,6 +60,8 @@ const (
 	ZONE_ADDRICHRULE       = ZONE + ".addRichRule"
 	ZONE_ADDSERVICE        = ZONE + ".addService"
 	ZONE_ADDSOURCE         = ZONE + ".addSource"
+	ZONE_REMOVESOURCE      = ZONE + ".removeSource"
+	ZONE_QUERYSOURCE       = ZONE + ".querySource"
 	ZONE_ADDINTERFACE      = ZONE + ".addInterface"
 	ZONE_QUERYINTERFACE    = ZONE + ".queryInterface"
 	ZONE_REMOVEINTERFACE   = ZONE + ".removeInterface"
@@ -53,6 +70,14 @@ const (
 	ZONE_ADDFORWARDPORT    = ZONE + ".addForwardPort"
 	ZONE_REMOVEFORWARDPORT = ZONE + ".removeForwardPort"
 	ZONE_QUERYFORWARDPORT  = ZONE + ".queryForwardPort"
+	ZONE_REMOVEPROTOCOL    = ZONE + ".removeProtocol"
+	ZONE_ADDICMPBLOCK      = ZONE + ".addIcmpBlock"
+	ZONE_REMOVEICMPBLOCK   = ZONE + ".removeIcmpBlock"
+	ZONE_ADDSOURCEPORT     = ZONE + ".addSourcePort"
+	ZONE_REMOVESOURCEPORT  = ZONE + ".removeSourcePort"
+
+	ZONE_ADDICMPBLOCKINVERSION    = ZONE + ".addIcmpBlockInversion"
+	ZONE_REMOVEICMPBLOCKINVERSION = ZONE + ".removeIcmpBlockInversion"
 
 	// get
 	ZONE_GETZONES           = ZONE + ".getZones"
@@ -62,12 +87,80 @@ const (
 	ZONE_QUERYSERVICE       = ZONE + ".queryService"
 	ZONE_REMOVESERVICE      = ZONE + ".removeService"
 
+	// org.fedoraproject.FirewallD1.ipset
+	IPSET_GETIPSETS   = IPSET + ".getIPSets"
+	IPSET_GETENTRIES  = IPSET + ".getEntries"
+	IPSET_ADDENTRY    = IPSET + ".addEntry"
+	IPSET_REMOVEENTRY = IPSET + ".removeEntry"
+	IPSET_QUERYENTRY  = IPSET + ".queryEntry"
+
+	// org.fedoraproject.FirewallD1.policy
+	POLICY_GETPOLICIES       = POLICY + ".getPolicies"
+	POLICY_GETPOLICYSETTINGS = POLICY + ".getPolicySettings"
+	POLICY_SETPOLICYSETTINGS = POLICY + ".setPolicySettings"
+
+	// org.fedoraproject.FirewallD1.direct
+	DIRECT_GETALLCHAINS       = DIRECT + ".getAllChains"
+	DIRECT_ADDCHAIN           = DIRECT + ".addChain"
+	DIRECT_REMOVECHAIN        = DIRECT + ".removeChain"
+	DIRECT_GETALLRULES        = DIRECT + ".getAllRules"
+	DIRECT_ADDRULE            = DIRECT + ".addRule"
+	DIRECT_REMOVERULE         = DIRECT + ".removeRule"
+	DIRECT_GETALLPASSTHROUGHS = DIRECT + ".getAllPassthroughs"
+	DIRECT_ADDPASSTHROUGH     = DIRECT + ".addPassthrough"
+	DIRECT_REMOVEPASSTHROUGH  = DIRECT + ".removePassthrough"
+	CONFIG_DIRECT_GETSETTINGS = CONFIG_DIRECT_INTERFACE + ".getSettings"
+	CONFIG_DIRECT_UPDATE      = CONFIG_DIRECT_INTERFACE + ".update"
+
 	// org.fedoraproject.FirewallD1.config
-	CONFIG_ADDZONE = CONFIG_INTERFACE + ".addZone"
+	CONFIG_ADDZONE          = CONFIG_INTERFACE + ".addZone"
+	CONFIG_GETZONENAMES     = CONFIG_INTERFACE + ".getZoneNames"
+	CONFIG_GETZONEBYNAME    = CONFIG_INTERFACE + ".getZoneByName"
+	CONFIG_ADDIPSET         = CONFIG_INTERFACE + ".addIPSet"
+	CONFIG_GETIPSETNAMES    = CONFIG_INTERFACE + ".getIPSetNames"
+	CONFIG_GETIPSETBYNAME   = CONFIG_INTERFACE + ".getIPSetByName"
+	CONFIG_ADDSERVICE       = CONFIG_INTERFACE + ".addService"
+	CONFIG_ADDSERVICE2      = CONFIG_INTERFACE + ".addService2"
+	CONFIG_GETSERVICENAMES  = CONFIG_INTERFACE + ".getServiceNames"
+	CONFIG_GETSERVICEBYNAME = CONFIG_INTERFACE + ".getServiceByName"
+	CONFIG_ADDPOLICY        = CONFIG_INTERFACE + ".addPolicy"
+	CONFIG_GETPOLICYNAMES   = CONFIG_INTERFACE + ".getPolicyNames"
+	CONFIG_GETPOLICYBYNAME  = CONFIG_INTERFACE + ".getPolicyByName"
+
+	// org.fedoraproject.FirewallD1.config.service
+	CONFIG_SERVICE              = CONFIG_INTERFACE + ".service"
+	CONFIG_SERVICE_GETSETTINGS  = CONFIG_SERVICE + ".getSettings"
+	CONFIG_SERVICE_GETSETTINGS2 = CONFIG_SERVICE + ".getSettings2"
+	CONFIG_SERVICE_UPDATE       = CONFIG_SERVICE + ".update"
+	CONFIG_SERVICE_UPDATE2      = CONFIG_SERVICE + ".update2"
+	CONFIG_SERVICE_REMOVE       = CONFIG_SERVICE + ".remove"
+
+	// org.fedoraproject.FirewallD1.config.policy
+	CONFIG_POLICY             = CONFIG_INTERFACE + ".policy"
+	CONFIG_POLICY_GETSETTINGS = CONFIG_POLICY + ".getSettings"
+	CONFIG_POLICY_UPDATE      = CONFIG_POLICY + ".update"
+	CONFIG_POLICY_REMOVE      = CONFIG_POLICY + ".remove"
+
+	// org.fedoraproject.FirewallD1.config.ipset
+	CONFIG_IPSET             = CONFIG_INTERFACE + ".ipset"
+	CONFIG_IPSET_GETSETTINGS = CONFIG_IPSET + ".getSettings"
+	CONFIG_IPSET_GETENTRIES  = CONFIG_IPSET + ".getEntries"
+	CONFIG_IPSET_ADDENTRY    = CONFIG_IPSET + ".addEntry"
+	CONFIG_IPSET_REMOVEENTRY = CONFIG_IPSET + ".removeEntry"
+	CONFIG_IPSET_QUERYENTRY  = CONFIG_IPSET + ".queryEntry"
+	CONFIG_IPSET_REMOVE      = CONFIG_IPSET + ".remove"
+	CONFIG_IPSET_UPDATE      = CONFIG_IPSET + ".update"
 
 	// org.fedoraproject.FirewallD1.config.zone
 	CONFIG_ZONE                   = CONFIG_INTERFACE + ".zone"
 	CONFIG_UPDATE                 = CONFIG_ZONE + ".update"
+	CONFIG_ZONE_GETSETTINGS       = CONFIG_ZONE + ".getSettings"
+	CONFIG_ZONE_REMOVE            = CONFIG_ZONE + ".remove"
+	CONFIG_ZONE_GETTARGET         = CONFIG_ZONE + ".getTarget"
+	CONFIG_ZONE_SETTARGET         = CONFIG_ZONE + ".setTarget"
+	CONFIG_ZONE_ADDSOURCE         = CONFIG_ZONE + ".addSource"
+	CONFIG_ZONE_REMOVESOURCE      = CONFIG_ZONE + ".removeSource"
+	CONFIG_ZONE_QUERYSOURCE       = CONFIG_ZONE + ".querySource"
 	CONFIG_ZONE_ADDRICHRULE       = CONFIG_ZONE + ".addRichRule"
 	CONFIG_ZONE_REOMVERICHRULE    = CONFIG_ZONE + ".removeRichRule"
 	CONFIG_ZONE_QUERYRICHRULE     = CONFIG_ZONE + ".queryRichRule"